@@ -172,25 +172,27 @@ func (c *ChartInfo) BuildClusterSchema() error {
 
 // ValidateValues validates the given values against the schema.
 func ValidateValues(c *ChartInfo, values map[string]interface{}) error {
-	validateFn := func(s *spec.Schema, values map[string]interface{}) error {
-		if s == nil {
-			return nil
-		}
-		v := validate.NewSchemaValidator(s, nil, "", strfmt.Default)
-		err := v.Validate(values).AsError()
-		if err != nil {
-			// the default error message is like "cpu in body should be a multiple of 0.5"
-			// the "in body" is not necessary, so we remove it
-			errMsg := strings.ReplaceAll(err.Error(), " in body", "")
-			return errors.New(errMsg)
-		}
-		return nil
+	if err := ValidateValue(c.Schema, values); err != nil {
+		return err
 	}
+	return ValidateValue(c.SubSchema, values)
+}
 
-	if err := validateFn(c.Schema, values); err != nil {
-		return err
+// ValidateValue validates the value against the schema, the value can be the values of
+// the chart or the value of a property.
+func ValidateValue(s *spec.Schema, value interface{}) error {
+	if s == nil {
+		return nil
 	}
-	return validateFn(c.SubSchema, values)
+	v := validate.NewSchemaValidator(s, nil, "", strfmt.Default)
+	err := v.Validate(value).AsError()
+	if err != nil {
+		// the default error message is like "cpu in body should be a multiple of 0.5"
+		// the "in body" is not necessary, so we remove it
+		errMsg := strings.ReplaceAll(err.Error(), " in body", "")
+		return errors.New(errMsg)
+	}
+	return nil
 }
 
 func loadHelmChart(ci *ChartInfo, t ClusterType) error {
@@ -22,6 +22,7 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
@@ -32,6 +33,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -483,3 +485,24 @@ func CompatibleComponentDefs(compDefs []string, compDef string) bool {
 	}
 	return false
 }
+
+// GetCompDefClusters returns the clusters grouped by the ComponentDefinition used by their components.
+func GetCompDefClusters(dynamic dynamic.Interface) (map[string][]string, error) {
+	objs, err := dynamic.Resource(types.ComponentGVR()).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	res := map[string][]string{}
+	for i := range objs.Items {
+		comp := &kbappsv1.Component{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(objs.Items[i].Object, comp); err != nil {
+			return nil, err
+		}
+		res[comp.Spec.CompDef] = append(res[comp.Spec.CompDef], fmt.Sprintf("%s/%s(%s)",
+			comp.Namespace, comp.Labels[constant.AppInstanceLabelKey], comp.Labels[constant.KBAppComponentLabelKey]))
+	}
+	for k := range res {
+		sort.Strings(res[k])
+	}
+	return res, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+// chartProfilesDir is the directory of the profiles shipped inside the cluster chart
+const chartProfilesDir = "profiles"
+
+// profilesDir returns the directory of the profiles of the cluster type in the kbcli home dir,
+// such as ~/.kbcli/profiles/mysql.
+func profilesDir(t ClusterType) (string, error) {
+	home, err := util.GetCliHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, types.CliClusterProfiles, t.String()), nil
+}
+
+// LoadProfile loads the values of the named profile, the profile in the kbcli home dir
+// overrides the one with the same name shipped inside the cluster chart.
+func LoadProfile(c *chart.Chart, t ClusterType, name string) (map[string]interface{}, error) {
+	var data []byte
+	dir, err := profilesDir(t)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = os.ReadFile(filepath.Join(dir, name+".yaml")); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		data = chartProfile(c, name)
+	}
+	if data == nil {
+		return nil, fmt.Errorf("profile %s of cluster type %s is not found, available profiles: [%s]",
+			name, t, strings.Join(ListProfiles(c, t), ", "))
+	}
+
+	values := map[string]interface{}{}
+	if err = yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %s: %w", name, err)
+	}
+	return values, nil
+}
+
+// ListProfiles returns the names of the profiles in the kbcli home dir and the cluster chart
+func ListProfiles(c *chart.Chart, t ClusterType) []string {
+	names := map[string]bool{}
+	if dir, err := profilesDir(t); err == nil {
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			if !e.IsDir() && filepath.Ext(e.Name()) == ".yaml" {
+				names[strings.TrimSuffix(e.Name(), ".yaml")] = true
+			}
+		}
+	}
+	if c != nil {
+		for _, f := range c.Files {
+			if filepath.Dir(f.Name) == chartProfilesDir && filepath.Ext(f.Name) == ".yaml" {
+				names[strings.TrimSuffix(filepath.Base(f.Name), ".yaml")] = true
+			}
+		}
+	}
+	var res []string
+	for name := range names {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func chartProfile(c *chart.Chart, name string) []byte {
+	if c == nil {
+		return nil
+	}
+	for _, f := range c.Files {
+		if f.Name == chartProfilesDir+"/"+name+".yaml" {
+			return f.Data
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("cluster profile", func() {
+	var c *chart.Chart
+	BeforeEach(func() {
+		GinkgoT().Setenv(types.CliHomeEnv, GinkgoT().TempDir())
+		c = &chart.Chart{Files: []*chart.File{
+			{Name: "profiles/small.yaml", Data: []byte("cpu: 0.5\nmemory: 0.5\n")},
+			{Name: "profiles/ha.yaml", Data: []byte("replicas: 3\n")},
+			{Name: "templates/notes.txt", Data: []byte("notes")},
+		}}
+	})
+
+	It("load the profiles in the chart and the home dir", func() {
+		Expect(ListProfiles(c, "mysql")).Should(Equal([]string{"ha", "small"}))
+		values, err := LoadProfile(c, "mysql", "small")
+		Expect(err).Should(Succeed())
+		Expect(values).Should(HaveKeyWithValue("cpu", 0.5))
+
+		By("the profile in the home dir overrides the chart one")
+		dir, err := profilesDir("mysql")
+		Expect(err).Should(Succeed())
+		Expect(os.MkdirAll(dir, 0750)).Should(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "small.yaml"), []byte("cpu: 1\n"), 0644)).Should(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "prod.yaml"), []byte("replicas: 5\n"), 0644)).Should(Succeed())
+		values, err = LoadProfile(c, "mysql", "small")
+		Expect(err).Should(Succeed())
+		Expect(values).Should(HaveKeyWithValue("cpu", float64(1)))
+		Expect(ListProfiles(c, "mysql")).Should(Equal([]string{"ha", "prod", "small"}))
+
+		_, err = LoadProfile(c, "mysql", "not-exist")
+		Expect(err).Should(HaveOccurred())
+	})
+})
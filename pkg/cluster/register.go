@@ -20,7 +20,14 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package cluster
 
 import (
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"k8s.io/klog"
 )
 
 type ClusterType string
@@ -44,3 +51,76 @@ type chartLoader interface {
 // ClusterType is the type of the cluster, the ClusterType t will be used as sub command name,
 // chartLoader is the interface for the chart config, implement this interface to register cluster type.
 var ClusterTypeCharts = map[ClusterType]chartLoader{}
+
+// ClusterTypeInfo is the information of a registered cluster type
+type ClusterTypeInfo struct {
+	Name    ClusterType
+	Alias   string
+	Builtin bool
+	// Source is the chart source the cluster type is registered from
+	Source string
+	// Version is the chart version, it is empty if the chart can not be loaded
+	Version string
+	// CachePath is the path of the cached chart, it is empty for the built-in cluster types
+	CachePath string
+}
+
+// ListClusterTypes returns the information of the registered cluster types sorted by name
+func ListClusterTypes() []ClusterTypeInfo {
+	var res []ClusterTypeInfo
+	for name, l := range ClusterTypeCharts {
+		info := ClusterTypeInfo{
+			Name:    name,
+			Alias:   l.getAlias(),
+			Version: chartVersion(l),
+		}
+		if instance, ok := l.(*TypeInstance); ok {
+			info.Source = instance.URL
+			info.CachePath = filepath.Join(CliChartsCacheDir, instance.getChartFileName())
+		} else {
+			info.Builtin = true
+			info.Source = "builtin"
+		}
+		res = append(res, info)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+func chartVersion(l chartLoader) string {
+	file, err := l.loadChart()
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	c, err := loader.LoadArchive(file)
+	if err != nil || c.Metadata == nil {
+		return ""
+	}
+	return c.Metadata.Version
+}
+
+// UnregisterClusterType removes the registered cluster type and its cached chart,
+// the built-in cluster type replaced by it is restored in the next run.
+func UnregisterClusterType(name ClusterType) error {
+	if _, ok := ClusterTypeCharts[name].(*TypeInstance); !ok {
+		if IsBuiltinCharts(name) {
+			return fmt.Errorf("cluster type %s is built-in and can not be unregistered", name)
+		}
+		return fmt.Errorf("cluster type %s is not registered", name)
+	}
+	if GlobalClusterChartConfig.RemoveConfig(name) {
+		if err := GlobalClusterChartConfig.WriteConfigs(CliClusterChartConfig); err != nil {
+			return fmt.Errorf("failed to remove cluster type %s from the config: %w", name, err)
+		}
+	}
+	chartFile := filepath.Join(CliChartsCacheDir, ClusterTypeCharts[name].getChartFileName())
+	if err := os.Remove(chartFile); err != nil && !os.IsNotExist(err) {
+		klog.V(2).Infof("failed to remove the cached chart %s: %s", chartFile, err.Error())
+	}
+	CacheFiles = GetChartCacheFiles()
+	delete(ClusterTypeCharts, name)
+	return nil
+}
@@ -118,6 +118,11 @@ func NewAddonCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.C
 		newUninstallCmd(f, streams),
 		newUpgradeCmd(f, streams),
 		newPurgeResourcesCmd(f, streams),
+		newPackCmd(f, streams),
+		newImagesCmd(f, streams),
+		newRollbackCmd(f, streams),
+		newSyncCmd(f, streams),
+		newLintCmd(streams),
 	)
 	return cmd
 }
@@ -0,0 +1,476 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/helm"
+)
+
+const (
+	bundleMetadataFile = "bundle.yaml"
+	bundleAddonFile    = "addon.yaml"
+	bundleImagesFile   = "images.txt"
+	bundleChartsDir    = "charts"
+	bundleImagesDir    = "images"
+
+	// defaultChartsPathInImage is the default path of the charts in the charts image of an addon
+	defaultChartsPathInImage = "/charts"
+)
+
+var addonPackExample = templates.Examples(`
+	# pack an addon with its chart and image list to apecloud-mysql-0.7.0.tgz
+	kbcli addon pack apecloud-mysql --version 0.7.0
+
+	# pack an addon to the specified file
+	kbcli addon pack apecloud-mysql --version 0.7.0 -o /tmp/apecloud-mysql.tgz
+
+	# pack an addon with the image tarballs, the images are pulled from a local registry
+	kbcli addon pack apecloud-mysql --version 0.7.0 --save-images --pull-registry localhost:5000
+`)
+
+// bundleMetadata is the metadata of an addon bundle, it is saved as bundle.yaml in the bundle.
+type bundleMetadata struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Index   string `json:"index,omitempty"`
+	// Chart is the path of the chart in the bundle
+	Chart string `json:"chart"`
+	// ChartDigest is the sha256 digest of the chart
+	ChartDigest string   `json:"chartDigest"`
+	Images      []string `json:"images"`
+	// ImageArchives are the paths of the image tarballs in the bundle, keyed by image
+	ImageArchives map[string]string `json:"imageArchives,omitempty"`
+	CreatedAt     string            `json:"createdAt"`
+}
+
+// bundleContent is the content of an addon bundle.
+type bundleContent struct {
+	metadata bundleMetadata
+	addon    *extensionsv1alpha1.Addon
+	// entry is the addon index entry and entrySig is its signature, which is nil if the entry is not signed
+	entry    []byte
+	entrySig []byte
+	// chart is the chart archive in the bundle
+	chart []byte
+}
+
+type packOption struct {
+	*installOption
+
+	output string
+	// save the image tarballs to the bundle
+	saveImages bool
+	// the registry to pull the images from when saving the image tarballs
+	pullRegistry string
+}
+
+func newPackCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &packOption{installOption: newInstallOption(f, streams)}
+	cmd := &cobra.Command{
+		Use:     "pack NAME",
+		Short:   "Pack an addon with its chart, index metadata and images to a bundle for air-gapped installation",
+		Args:    cobra.ExactArgs(1),
+		Example: addonPackExample,
+		PersistentPreRun: func(_ *cobra.Command, _ []string) {
+			util.CheckErr(addDefaultIndex())
+		},
+		ValidArgsFunction: addonNameCompletionFunc,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.name = args[0]
+			util.CheckErr(o.complete())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVar(&o.version, "version", "", "specify the addon version to pack, run 'kbcli addon search <addon-name>' to get the available versions")
+	cmd.Flags().StringVar(&o.index, "index", types.DefaultIndexName, "specify the addon index, use 'kubeblocks' by default")
+	cmd.Flags().StringVar(&o.path, "path", "", "specify the local path contains addon CRs")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "the output file of the bundle, use '<name>-<version>.tgz' by default")
+	cmd.Flags().BoolVar(&o.saveImages, "save-images", false, "save the image tarballs to the bundle")
+	cmd.Flags().StringVar(&o.pullRegistry, "pull-registry", "", "pull the images from the specified registry instead of their original registries when saving the image tarballs")
+
+	_ = cmd.MarkFlagRequired("version")
+	return cmd
+}
+
+func (o *packOption) complete() error {
+	if o.version == "" {
+		return fmt.Errorf("please specify the version, run 'kbcli addon search %s' to get the available versions", o.name)
+	}
+	if o.pullRegistry != "" && !o.saveImages {
+		return fmt.Errorf("--pull-registry can only be used with --save-images")
+	}
+	if err := o.findAddon(); err != nil {
+		return err
+	}
+	if err := checkAddonChart(o.addon); err != nil {
+		return err
+	}
+	if o.output == "" {
+		o.output = fmt.Sprintf("%s-%s.tgz", o.name, o.version)
+	}
+	return nil
+}
+
+func (o *packOption) run() error {
+	dir, err := os.MkdirTemp("", "kbcli-addon-pack-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	chartsDir := filepath.Join(dir, bundleChartsDir)
+	if err = os.MkdirAll(chartsDir, 0755); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Downloading chart %s\n", o.addon.Spec.Helm.ChartLocationURL)
+	chartPath, err := downloadAddonChart(o.addon, chartsDir)
+	if err != nil {
+		return err
+	}
+	digest, err := fileDigest(chartPath)
+	if err != nil {
+		return err
+	}
+
+	manifests, err := renderAddonChart(o.addon, chartPath, nil, nil)
+	if err != nil {
+		return err
+	}
+	images, err := collectImages(o.addon, manifests)
+	if err != nil {
+		return err
+	}
+
+	metadata := bundleMetadata{
+		Name:        o.name,
+		Version:     o.version,
+		Index:       o.index,
+		Chart:       filepath.ToSlash(filepath.Join(bundleChartsDir, filepath.Base(chartPath))),
+		ChartDigest: digest,
+		Images:      images,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	if o.path != "" {
+		metadata.Index = filepath.Base(o.path)
+	}
+	if o.saveImages {
+		if metadata.ImageArchives, err = o.saveImageArchives(images, dir); err != nil {
+			return err
+		}
+	}
+
+	// keep the index entry as it is, so that its signature can be verified when installing
+	entry, entrySig, err := readSignedFile(o.addonPath)
+	if err != nil {
+		return err
+	}
+	metadataBytes, err := yaml.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{
+		bundleAddonFile:    entry,
+		bundleMetadataFile: metadataBytes,
+		bundleImagesFile:   []byte(strings.Join(images, "\n") + "\n"),
+	}
+	if entrySig != nil {
+		files[bundleAddonFile+signatureSuffix] = entrySig
+	}
+	for name, data := range files {
+		if err = os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return err
+		}
+	}
+	if err = writeBundle(dir, o.output); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "addon %s-%s is packed to %s with %d images\n", o.name, o.version, o.output, len(images))
+	return nil
+}
+
+// saveImageArchives pulls the images and saves them as tarballs in the images directory of the bundle.
+func (o *packOption) saveImageArchives(images []string, dir string) (map[string]string, error) {
+	imagesDir := filepath.Join(dir, bundleImagesDir)
+	if err := os.MkdirAll(imagesDir, 0755); err != nil {
+		return nil, err
+	}
+	var rewrites []registryRewrite
+	if o.pullRegistry != "" {
+		rewrites = []registryRewrite{{to: o.pullRegistry}}
+	}
+	archives := map[string]string{}
+	for i, image := range images {
+		src := rewriteImage(image, rewrites)
+		fmt.Fprintf(o.Out, "Saving image %s\n", src)
+		img, err := crane.Pull(src)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull image %s: %w", src, err)
+		}
+		archive := filepath.ToSlash(filepath.Join(bundleImagesDir, fmt.Sprintf("%d.tar", i)))
+		// tag the tarball with the original image, it can be loaded by 'docker load'
+		if err = crane.Save(img, image, filepath.Join(dir, archive)); err != nil {
+			return nil, fmt.Errorf("failed to save image %s: %w", image, err)
+		}
+		archives[image] = archive
+	}
+	return archives, nil
+}
+
+// completeFromBundle reads the bundle and completes the addon from it.
+func (o *installOption) completeFromBundle() error {
+	bundle, err := readBundle(o.fromBundle)
+	if err != nil {
+		return err
+	}
+	o.bundle = bundle
+	if o.name != "" && o.name != bundle.metadata.Name {
+		return fmt.Errorf("the bundle %s is for addon %s, not %s", o.fromBundle, bundle.metadata.Name, o.name)
+	}
+	if o.version != "" && o.version != bundle.metadata.Version {
+		return fmt.Errorf("the bundle %s is for addon version %s, not %s", o.fromBundle, bundle.metadata.Version, o.version)
+	}
+	o.name, o.version, o.addon = bundle.metadata.Name, bundle.metadata.Version, bundle.addon
+	if o.clusterChartVersion == "" {
+		o.clusterChartVersion = o.version
+	}
+	return o.verifyAddon()
+}
+
+// completeBundleAddon points the addon to a chart which is reachable by the addon controller and rewrites
+// the image registries by the install values. The chart in the bundle is pushed to the chart registry if
+// specified, otherwise the chart is installed from the charts image of the addon, which is saved to the
+// bundle with the other images.
+func (o *installOption) completeBundleAddon() error {
+	rewrites, err := parseRegistryRewrites(o.registryRewrites)
+	if err != nil {
+		return err
+	}
+	c, err := loader.LoadArchive(bytes.NewReader(o.bundle.chart))
+	if err != nil {
+		return err
+	}
+	images := map[string]bool{}
+	for _, image := range o.bundle.metadata.Images {
+		images[image] = true
+	}
+
+	helmSpec := o.addon.Spec.Helm
+	// the values specified by --set take precedence over the overlay
+	helmSpec.InstallValues.SetValues = append(buildRegistryOverlay(c.Values, images, rewrites), helmSpec.InstallValues.SetValues...)
+	switch {
+	case o.chartRegistry != "":
+		fmt.Fprintf(o.Out, "Pushing chart %s to %s\n", path.Base(o.bundle.metadata.Chart), o.chartRegistry)
+		if helmSpec.ChartLocationURL, err = helm.PushOCIChart(o.bundle.chart, o.chartRegistry, o.plainHTTP); err != nil {
+			return err
+		}
+	case helmSpec.ChartsImage != "":
+		helmSpec.ChartsImage = rewriteImage(helmSpec.ChartsImage, rewrites)
+		if !strings.HasPrefix(helmSpec.ChartLocationURL, "file://") {
+			chartsPath := helmSpec.ChartsPathInImage
+			if chartsPath == "" {
+				chartsPath = defaultChartsPathInImage
+			}
+			helmSpec.ChartLocationURL = "file://" + path.Join(chartsPath, path.Base(o.bundle.metadata.Chart))
+		}
+	default:
+		return fmt.Errorf("addon %s has no charts image, please specify --chart-registry to push the chart in the bundle to an OCI registry reachable by KubeBlocks", o.name)
+	}
+
+	if o.addon.Annotations == nil {
+		o.addon.Annotations = map[string]string{}
+	}
+	o.addon.Annotations[types.AddonBundleDigestAnnotationKey] = o.bundle.metadata.ChartDigest
+	return nil
+}
+
+// printRewrittenImages prints the images rewritten by the registry rewrites.
+func (o *installOption) printRewrittenImages() {
+	rewrites, _ := parseRegistryRewrites(o.registryRewrites)
+	var lines []string
+	for _, image := range o.bundle.metadata.Images {
+		if newImage := rewriteImage(image, rewrites); newImage != image {
+			lines = append(lines, fmt.Sprintf("  %s -> %s", image, newImage))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(o.Out, "%d images are rewritten, make sure they are available in the registries:\n", len(lines))
+	fmt.Fprintln(o.Out, strings.Join(lines, "\n"))
+}
+
+// readBundle reads the metadata, the addon entry and the chart from the bundle, and verifies the chart digest.
+// The image tarballs in the bundle are skipped.
+func readBundle(file string) (*bundleContent, error) {
+	files, err := readBundleFiles(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle %s: %w", file, err)
+	}
+	bundle := &bundleContent{addon: &extensionsv1alpha1.Addon{}}
+	data, ok := files[bundleMetadataFile]
+	if !ok {
+		return nil, fmt.Errorf("invalid bundle %s: %s is not found", file, bundleMetadataFile)
+	}
+	if err = yaml.Unmarshal(data, &bundle.metadata); err != nil {
+		return nil, err
+	}
+	if bundle.entry, ok = files[bundleAddonFile]; !ok {
+		return nil, fmt.Errorf("invalid bundle %s: %s is not found", file, bundleAddonFile)
+	}
+	bundle.entrySig = files[bundleAddonFile+signatureSuffix]
+	if err = yaml.Unmarshal(bundle.entry, bundle.addon); err != nil {
+		return nil, err
+	}
+	if bundle.addon.Spec.Helm == nil {
+		return nil, fmt.Errorf("invalid bundle %s: addon %s is not installed by a helm chart", file, bundle.addon.Name)
+	}
+	if bundle.chart, ok = files[path.Clean(bundle.metadata.Chart)]; !ok {
+		return nil, fmt.Errorf("invalid bundle %s: chart %s is not found", file, bundle.metadata.Chart)
+	}
+	if digest := dataDigest(bundle.chart); digest != bundle.metadata.ChartDigest {
+		return nil, fmt.Errorf("the digest of chart %s is %s, but %s is expected", bundle.metadata.Chart, digest, bundle.metadata.ChartDigest)
+	}
+	return bundle, nil
+}
+
+// readBundleFiles reads the metadata, the addon entry with its signature and the charts from
+// the gzipped tarball into memory, keyed by the paths in the bundle.
+func readBundleFiles(file string) (map[string][]byte, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+	files := map[string][]byte{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := path.Clean(header.Name)
+		if header.Typeflag != tar.TypeReg || !isBundleContentFile(name) {
+			continue
+		}
+		if files[name], err = io.ReadAll(tr); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func isBundleContentFile(name string) bool {
+	switch name {
+	case bundleMetadataFile, bundleAddonFile, bundleAddonFile + signatureSuffix:
+		return true
+	}
+	return path.Dir(name) == bundleChartsDir
+}
+
+func dataDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func fileDigest(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeBundle archives all files in the directory to a gzipped tarball.
+func writeBundle(dir, output string) error {
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	if err = filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err = tw.WriteHeader(header); err != nil {
+			return err
+		}
+		src, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	}); err != nil {
+		return err
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"sigs.k8s.io/yaml"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("addon bundle test", func() {
+	It("write and read bundle", func() {
+		dir := GinkgoT().TempDir()
+		bundleDir := filepath.Join(dir, "bundle")
+		Expect(os.MkdirAll(filepath.Join(bundleDir, bundleChartsDir), 0755)).Should(Succeed())
+		c := &chart.Chart{
+			Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "mysql", Version: "0.7.0"},
+			Values:   map[string]interface{}{"image": map[string]interface{}{"registry": "docker.io", "repository": "apecloud/mysql"}},
+		}
+		chartPath, err := chartutil.Save(c, filepath.Join(bundleDir, bundleChartsDir))
+		Expect(err).Should(Succeed())
+		digest, err := fileDigest(chartPath)
+		Expect(err).Should(Succeed())
+
+		addon := &extensionsv1alpha1.Addon{}
+		addon.Name = "mysql"
+		addon.Spec.Helm = &extensionsv1alpha1.HelmTypeInstallSpec{
+			ChartLocationURL: "https://example.com/mysql-0.7.0.tgz",
+			ChartsImage:      "apecloud/kubeblocks-charts:0.7.0",
+		}
+		metadata := bundleMetadata{
+			Name:        "mysql",
+			Version:     "0.7.0",
+			Chart:       bundleChartsDir + "/mysql-0.7.0.tgz",
+			ChartDigest: digest,
+			Images:      []string{"apecloud/mysql:8.0.30"},
+		}
+		writeYAML := func(name string, obj interface{}) {
+			data, err := yaml.Marshal(obj)
+			Expect(err).Should(Succeed())
+			Expect(os.WriteFile(filepath.Join(bundleDir, name), data, 0644)).Should(Succeed())
+		}
+		writeYAML(bundleAddonFile, addon)
+		writeYAML(bundleMetadataFile, metadata)
+		Expect(os.MkdirAll(filepath.Join(bundleDir, bundleImagesDir), 0755)).Should(Succeed())
+		Expect(os.WriteFile(filepath.Join(bundleDir, bundleImagesDir, "0.tar"), []byte("image"), 0644)).Should(Succeed())
+		output := filepath.Join(dir, "mysql-0.7.0.tgz")
+		Expect(writeBundle(bundleDir, output)).Should(Succeed())
+
+		By("the image tarballs are not read")
+		files, err := readBundleFiles(output)
+		Expect(err).Should(Succeed())
+		Expect(files).Should(HaveKey(bundleMetadataFile))
+		Expect(files).ShouldNot(HaveKey(bundleImagesDir + "/0.tar"))
+
+		bundle, err := readBundle(output)
+		Expect(err).Should(Succeed())
+		Expect(bundle.metadata).Should(Equal(metadata))
+		Expect(bundle.addon.Name).Should(Equal("mysql"))
+		Expect(bundle.chart).ShouldNot(BeEmpty())
+
+		By("install from the bundle")
+		streams, _, _, _ := genericiooptions.NewTestIOStreams()
+		o := newInstallOption(nil, streams)
+		o.fromBundle = output
+		o.name = "postgresql"
+		Expect(o.completeFromBundle()).Should(HaveOccurred())
+		o.name = ""
+		Expect(o.completeFromBundle()).Should(Succeed())
+		Expect(o.name).Should(Equal("mysql"))
+		Expect(o.version).Should(Equal("0.7.0"))
+		Expect(o.clusterChartVersion).Should(Equal("0.7.0"))
+
+		By("install the chart from the charts image")
+		o.registryRewrites = []string{"docker.io=registry.example.com"}
+		Expect(o.completeBundleAddon()).Should(Succeed())
+		Expect(o.addon.Spec.Helm.ChartsImage).Should(Equal("registry.example.com/apecloud/kubeblocks-charts:0.7.0"))
+		Expect(o.addon.Spec.Helm.ChartLocationURL).Should(Equal("file:///charts/mysql-0.7.0.tgz"))
+		Expect(o.addon.Spec.Helm.InstallValues.SetValues).Should(ContainElement("image.registry=registry.example.com"))
+		Expect(o.addon.Annotations[types.AddonBundleDigestAnnotationKey]).Should(Equal(digest))
+
+		By("the addon has no charts image")
+		o.addon = bundle.addon.DeepCopy()
+		o.addon.Spec.Helm.ChartsImage = ""
+		Expect(o.completeBundleAddon()).Should(HaveOccurred())
+
+		By("the chart is modified")
+		metadata.ChartDigest = "sha256:invalid"
+		writeYAML(bundleMetadataFile, metadata)
+		Expect(writeBundle(bundleDir, output)).Should(Succeed())
+		_, err = readBundle(output)
+		Expect(err).Should(HaveOccurred())
+	})
+})
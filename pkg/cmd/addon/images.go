@@ -0,0 +1,445 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/strvals"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/helm"
+)
+
+// defaultImageRegistry is the registry of the images without a registry host
+const defaultImageRegistry = "docker.io"
+
+var addonImagesExample = templates.Examples(`
+	# list the images of the latest version of an addon
+	kbcli addon images apecloud-mysql
+
+	# list the images of an addon with a specified version in JSON format
+	kbcli addon images apecloud-mysql --version 0.7.0 -o json
+
+	# generate the values to pull the images from an internal registry
+	kbcli addon images apecloud-mysql --version 0.7.0 --rewrite-registry docker.io=registry.example.com
+`)
+
+// imageReference is an image and the objects which reference it.
+type imageReference struct {
+	Image   string   `json:"image"`
+	Objects []string `json:"objects"`
+}
+
+type imagesOption struct {
+	*installOption
+
+	output printer.Format
+	// rewrite the image registries in the format of 'old=new' or 'new'
+	rewriteRegistries []string
+}
+
+func newImagesCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &imagesOption{installOption: newInstallOption(f, streams)}
+	cmd := &cobra.Command{
+		Use:     "images NAME",
+		Short:   "List the images of an addon, or generate the values to rewrite the image registries",
+		Args:    cobra.ExactArgs(1),
+		Example: addonImagesExample,
+		PersistentPreRun: func(_ *cobra.Command, _ []string) {
+			util.CheckErr(addDefaultIndex())
+		},
+		ValidArgsFunction: addonNameCompletionFunc,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.name = args[0]
+			util.CheckErr(o.complete())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVar(&o.version, "version", "", "specify the addon version, use the latest version in the index by default")
+	cmd.Flags().StringVar(&o.index, "index", types.DefaultIndexName, "specify the addon index, use 'kubeblocks' by default")
+	cmd.Flags().StringVar(&o.path, "path", "", "specify the local path contains addon CRs")
+	cmd.Flags().StringArrayVar(&o.rewriteRegistries, "rewrite-registry", nil, "generate the values to rewrite the image registry, in the format of 'old=new', or 'new' to rewrite all registries")
+	printer.AddOutputFlag(cmd, &o.output)
+	return cmd
+}
+
+func (o *imagesOption) complete() error {
+	if err := o.findAddon(); err != nil {
+		return err
+	}
+	return checkAddonChart(o.addon)
+}
+
+func (o *imagesOption) run() error {
+	rewrites, err := parseRegistryRewrites(o.rewriteRegistries)
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp("", "kbcli-addon-images-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	chartPath, err := downloadAddonChart(o.addon, dir)
+	if err != nil {
+		return err
+	}
+	manifests, err := renderAddonChart(o.addon, chartPath, nil, nil)
+	if err != nil {
+		return err
+	}
+	refs, err := collectImageReferences(o.addon, manifests)
+	if err != nil {
+		return err
+	}
+	if len(rewrites) == 0 {
+		return printImageReferences(o.Out, refs, o.output)
+	}
+
+	c, err := loader.Load(chartPath)
+	if err != nil {
+		return err
+	}
+	images := map[string]bool{}
+	for _, ref := range refs {
+		images[ref.Image] = true
+	}
+	overlay := buildRegistryOverlay(c.Values, images, rewrites)
+
+	// render the chart with the overlay to find the images which can not be rewritten by values
+	if manifests, err = renderAddonChart(o.addon, chartPath, nil, overlay); err != nil {
+		return err
+	}
+	if refs, err = collectImageReferences(o.addon, manifests); err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if rewriteImage(ref.Image, rewrites) != ref.Image {
+			printer.Warning(o.ErrOut, "image %s referenced by %s can not be rewritten by the values\n", ref.Image, strings.Join(ref.Objects, ", "))
+		}
+	}
+	return o.printOverlay(overlay)
+}
+
+func (o *imagesOption) printOverlay(overlay []string) error {
+	if o.output.IsHumanReadable() {
+		if len(overlay) == 0 {
+			fmt.Fprintf(o.Out, "No values found to rewrite the image registries of addon %s-%s\n", o.name, o.version)
+			return nil
+		}
+		var args []string
+		for _, v := range overlay {
+			fmt.Fprintln(o.Out, v)
+			args = append(args, "--set "+v)
+		}
+		fmt.Fprintf(o.Out, "\nYou can run the following command to install the addon with the values:\n")
+		fmt.Fprintf(o.Out, "  kbcli addon install %s --version %s %s\n", o.name, o.version, strings.Join(args, " "))
+		return nil
+	}
+	values := map[string]interface{}{}
+	for _, v := range overlay {
+		if err := strvals.ParseInto(v, values); err != nil {
+			return err
+		}
+	}
+	return printObject(o.Out, values, o.output)
+}
+
+func printImageReferences(out io.Writer, refs []imageReference, format printer.Format) error {
+	if !format.IsHumanReadable() {
+		if refs == nil {
+			refs = []imageReference{}
+		}
+		return printObject(out, refs, format)
+	}
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("IMAGE", "REFERENCED BY")
+	for _, ref := range refs {
+		tbl.AddRow(ref.Image, strings.Join(ref.Objects, ", "))
+	}
+	tbl.Print()
+	return nil
+}
+
+func printObject(out io.Writer, obj interface{}, format printer.Format) error {
+	var (
+		data []byte
+		err  error
+	)
+	if format == printer.JSON {
+		data, err = json.MarshalIndent(obj, "", "  ")
+	} else {
+		data, err = yaml.Marshal(obj)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, strings.TrimSpace(string(data)))
+	return nil
+}
+
+// checkAddonChart checks if the chart of the addon can be downloaded.
+func checkAddonChart(addon *extensionsv1alpha1.Addon) error {
+	if addon.Spec.Helm == nil || addon.Spec.Helm.ChartLocationURL == "" {
+		return fmt.Errorf("addon %s is not installed by a helm chart", addon.Name)
+	}
+	if strings.HasPrefix(addon.Spec.Helm.ChartLocationURL, "file://") {
+		return fmt.Errorf("the chart of addon %s is stored in the charts image %s and can not be downloaded", addon.Name, addon.Spec.Helm.ChartsImage)
+	}
+	return nil
+}
+
+// downloadAddonChart downloads the chart of the addon to the directory and returns the chart path.
+func downloadAddonChart(addon *extensionsv1alpha1.Addon, dir string) (string, error) {
+	chartsDownloader, err := helm.NewDownloader(helm.NewConfig("", "", "", false))
+	if err != nil {
+		return "", err
+	}
+	chartPath, _, err := chartsDownloader.DownloadTo(addon.Spec.Helm.ChartLocationURL, "", dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to download the chart of addon %s: %w", addon.Name, err)
+	}
+	return chartPath, nil
+}
+
+// renderAddonChart renders the addon chart with the values, the install values of the addon and the extra values,
+// the later ones take precedence. The values are modified in place.
+func renderAddonChart(addon *extensionsv1alpha1.Addon, chartPath string, values map[string]interface{}, setValues []string) (map[string]string, error) {
+	c, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, err
+	}
+	setValues = append(append([]string{}, addon.Spec.Helm.InstallValues.SetValues...), setValues...)
+	manifests, err := renderChart(c, metav1.NamespaceDefault, util.BuildAddonReleaseName(addon.Name), helm.FakeKubeVersion, values, setValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render addon %s: %w", addon.Name, err)
+	}
+	return manifests, nil
+}
+
+// renderChart renders the chart as the release in the namespace with the values and the set values,
+// the values are modified in place.
+func renderChart(c *chart.Chart, namespace, releaseName, kubeVersion string, values map[string]interface{}, setValues []string) (map[string]string, error) {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	for _, v := range setValues {
+		if err := strvals.ParseInto(v, values); err != nil {
+			return nil, fmt.Errorf("invalid value %s: %w", v, err)
+		}
+	}
+	return cluster.GetManifests(c, true, namespace, releaseName, kubeVersion, values)
+}
+
+// collectImageReferences returns the images referenced by the `image` fields of the manifests, such as the
+// workloads, ComponentDefinitions, ActionSets and OpsDefinitions, and the images of the ComponentVersion releases.
+// The charts image of the addon is also included.
+func collectImageReferences(addon *extensionsv1alpha1.Addon, manifests map[string]string) ([]imageReference, error) {
+	objects := map[string]map[string]struct{}{}
+	add := func(image, object string) {
+		if image = strings.TrimSpace(image); image == "" {
+			return
+		}
+		if objects[image] == nil {
+			objects[image] = map[string]struct{}{}
+		}
+		objects[image][object] = struct{}{}
+	}
+	if addon.Spec.Helm != nil {
+		add(addon.Spec.Helm.ChartsImage, fmt.Sprintf("%s/%s", types.KindAddon, addon.Name))
+	}
+	for _, m := range manifests {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(m), &obj.Object); err != nil {
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		object := fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+		if obj.GetKind() == types.KindComponentVersion {
+			releases, _, _ := unstructured.NestedSlice(obj.Object, "spec", "releases")
+			for _, r := range releases {
+				release, ok := r.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				releaseImages, _, _ := unstructured.NestedStringMap(release, "images")
+				for _, image := range releaseImages {
+					add(image, object)
+				}
+			}
+		}
+		walkImageFields(obj.Object, func(image string) {
+			add(image, object)
+		})
+	}
+
+	refs := make([]imageReference, 0, len(objects))
+	for image, set := range objects {
+		ref := imageReference{Image: image}
+		for object := range set {
+			ref.Objects = append(ref.Objects, object)
+		}
+		sort.Strings(ref.Objects)
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Image < refs[j].Image
+	})
+	return refs, nil
+}
+
+// collectImages returns the sorted images referenced by the addon.
+func collectImages(addon *extensionsv1alpha1.Addon, manifests map[string]string) ([]string, error) {
+	refs, err := collectImageReferences(addon, manifests)
+	if err != nil {
+		return nil, err
+	}
+	images := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		images = append(images, ref.Image)
+	}
+	return images, nil
+}
+
+func walkImageFields(obj interface{}, fn func(image string)) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if image, ok := value.(string); ok && key == "image" {
+				fn(image)
+				continue
+			}
+			walkImageFields(value, fn)
+		}
+	case []interface{}:
+		for _, value := range v {
+			walkImageFields(value, fn)
+		}
+	}
+}
+
+// buildRegistryOverlay builds the values in the format of 'key=value' to rewrite the image registries, it
+// rewrites the `registry` values and the values which are the full image references.
+func buildRegistryOverlay(values map[string]interface{}, images map[string]bool, rewrites []registryRewrite) []string {
+	var overlay []string
+	var walk func(prefix string, values map[string]interface{})
+	walk = func(prefix string, values map[string]interface{}) {
+		for key, value := range values {
+			path := prefix + strings.ReplaceAll(key, ".", `\.`)
+			switch v := value.(type) {
+			case map[string]interface{}:
+				walk(path+".", v)
+			case string:
+				if key == "registry" {
+					registry := v
+					if registry == "" {
+						registry = defaultImageRegistry
+					}
+					if to, ok := matchRegistryRewrite(registry, rewrites); ok && to != v {
+						overlay = append(overlay, fmt.Sprintf("%s=%s", path, to))
+					}
+				} else if images[v] {
+					if image := rewriteImage(v, rewrites); image != v {
+						overlay = append(overlay, fmt.Sprintf("%s=%s", path, image))
+					}
+				}
+			}
+		}
+	}
+	walk("", values)
+	sort.Strings(overlay)
+	return overlay
+}
+
+// registryRewrite rewrites the registry of images from `from` to `to`, an empty `from` matches all registries.
+type registryRewrite struct {
+	from string
+	to   string
+}
+
+func parseRegistryRewrites(rewrites []string) ([]registryRewrite, error) {
+	var res []registryRewrite
+	for _, r := range rewrites {
+		from, to, found := strings.Cut(r, "=")
+		if !found {
+			from, to = "", r
+		}
+		from, to = strings.TrimSpace(from), strings.TrimSuffix(strings.TrimSpace(to), "/")
+		if to == "" || (found && from == "") {
+			return nil, fmt.Errorf("invalid registry rewrite %q, it should be in the format of 'old=new' or 'new'", r)
+		}
+		res = append(res, registryRewrite{from: from, to: to})
+	}
+	return res, nil
+}
+
+// splitImageRegistry splits the image to the registry and the repository with the tag or digest.
+func splitImageRegistry(image string) (string, string) {
+	i := strings.Index(image, "/")
+	if i < 0 {
+		return defaultImageRegistry, image
+	}
+	if host := image[:i]; strings.ContainsAny(host, ".:") || host == "localhost" {
+		return host, image[i+1:]
+	}
+	return defaultImageRegistry, image
+}
+
+// matchRegistryRewrite returns the new registry of the first matched rewrite.
+func matchRegistryRewrite(registry string, rewrites []registryRewrite) (string, bool) {
+	for _, r := range rewrites {
+		if r.from == "" || r.from == registry {
+			return r.to, true
+		}
+	}
+	return "", false
+}
+
+// rewriteImage rewrites the registry of the image by the first matched rewrite.
+func rewriteImage(image string, rewrites []registryRewrite) string {
+	registry, repository := splitImageRegistry(image)
+	if to, ok := matchRegistryRewrite(registry, rewrites); ok {
+		return to + "/" + repository
+	}
+	return image
+}
@@ -0,0 +1,157 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/releaseutil"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+)
+
+const testAddonManifest = `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: mysql-exporter
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox:1.35
+      containers:
+      - name: exporter
+        image: registry.example.com/apecloud/mysqld-exporter:0.15.1
+---
+apiVersion: apps.kubeblocks.io/v1
+kind: ComponentVersion
+metadata:
+  name: mysql
+spec:
+  releases:
+  - name: 8.0.30
+    serviceVersion: 8.0.30
+    images:
+      mysql: apecloud/mysql:8.0.30
+      exporter: registry.example.com/apecloud/mysqld-exporter:0.15.1
+---
+apiVersion: dataprotection.kubeblocks.io/v1alpha1
+kind: ActionSet
+metadata:
+  name: mysql-xtrabackup
+spec:
+  backup:
+    backupData:
+      image: apecloud/mysql:8.0.30
+`
+
+var _ = Describe("addon images test", func() {
+	var addon *extensionsv1alpha1.Addon
+
+	BeforeEach(func() {
+		addon = &extensionsv1alpha1.Addon{}
+		addon.Name = "mysql"
+		addon.Spec.Helm = &extensionsv1alpha1.HelmTypeInstallSpec{
+			ChartLocationURL: "https://example.com/mysql-0.7.0.tgz",
+			ChartsImage:      "apecloud/kb-charts:0.7.0",
+		}
+	})
+
+	It("check addon chart", func() {
+		Expect(checkAddonChart(addon)).Should(Succeed())
+		addon.Spec.Helm.ChartLocationURL = "file:///charts/mysql-0.7.0.tgz"
+		Expect(checkAddonChart(addon)).Should(HaveOccurred())
+		addon.Spec.Helm = nil
+		Expect(checkAddonChart(addon)).Should(HaveOccurred())
+	})
+
+	It("collect images", func() {
+		manifests := releaseutil.SplitManifests(testAddonManifest)
+		refs, err := collectImageReferences(addon, manifests)
+		Expect(err).Should(Succeed())
+		Expect(refs).Should(Equal([]imageReference{
+			{Image: "apecloud/kb-charts:0.7.0", Objects: []string{"Addon/mysql"}},
+			{Image: "apecloud/mysql:8.0.30", Objects: []string{"ActionSet/mysql-xtrabackup", "ComponentVersion/mysql"}},
+			{Image: "busybox:1.35", Objects: []string{"Deployment/mysql-exporter"}},
+			{Image: "registry.example.com/apecloud/mysqld-exporter:0.15.1", Objects: []string{"ComponentVersion/mysql", "Deployment/mysql-exporter"}},
+		}))
+
+		images, err := collectImages(addon, manifests)
+		Expect(err).Should(Succeed())
+		Expect(images).Should(HaveLen(4))
+
+		out := &bytes.Buffer{}
+		Expect(printImageReferences(out, refs, printer.Table)).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("ActionSet/mysql-xtrabackup, ComponentVersion/mysql"))
+		out.Reset()
+		Expect(printImageReferences(out, refs, printer.JSON)).Should(Succeed())
+		var res []imageReference
+		Expect(json.Unmarshal(out.Bytes(), &res)).Should(Succeed())
+		Expect(res).Should(Equal(refs))
+	})
+
+	It("rewrite registry", func() {
+		_, err := parseRegistryRewrites([]string{"=mirror.local"})
+		Expect(err).Should(HaveOccurred())
+		_, err = parseRegistryRewrites([]string{"docker.io="})
+		Expect(err).Should(HaveOccurred())
+
+		rewrites, err := parseRegistryRewrites([]string{"docker.io=mirror.local/", "registry.example.com=localhost:5000"})
+		Expect(err).Should(Succeed())
+		Expect(rewriteImage("apecloud/mysql:8.0.30", rewrites)).Should(Equal("mirror.local/apecloud/mysql:8.0.30"))
+		Expect(rewriteImage("busybox:1.35", rewrites)).Should(Equal("mirror.local/busybox:1.35"))
+		Expect(rewriteImage("registry.example.com/apecloud/mysqld-exporter:0.15.1", rewrites)).Should(Equal("localhost:5000/apecloud/mysqld-exporter:0.15.1"))
+		Expect(rewriteImage("quay.io/prometheus/node-exporter:v1.8.0", rewrites)).Should(Equal("quay.io/prometheus/node-exporter:v1.8.0"))
+
+		rewrites, err = parseRegistryRewrites([]string{"mirror.local"})
+		Expect(err).Should(Succeed())
+		Expect(rewriteImage("quay.io/prometheus/node-exporter:v1.8.0", rewrites)).Should(Equal("mirror.local/prometheus/node-exporter:v1.8.0"))
+	})
+
+	It("build registry overlay", func() {
+		values := map[string]interface{}{
+			"image": map[string]interface{}{
+				"registry":   "docker.io",
+				"repository": "apecloud/mysql",
+			},
+			"exporter": map[string]interface{}{
+				"image": "registry.example.com/apecloud/mysqld-exporter:0.15.1",
+			},
+			"backup": map[string]interface{}{
+				"registry": "quay.io",
+			},
+			"app.kubernetes.io/name": "mysql",
+		}
+		images := map[string]bool{"registry.example.com/apecloud/mysqld-exporter:0.15.1": true}
+		rewrites, err := parseRegistryRewrites([]string{"docker.io=mirror.local", "registry.example.com=mirror.local"})
+		Expect(err).Should(Succeed())
+		Expect(buildRegistryOverlay(values, images, rewrites)).Should(Equal([]string{
+			"exporter.image=mirror.local/apecloud/mysqld-exporter:0.15.1",
+			"image.registry=mirror.local",
+		}))
+	})
+})
@@ -101,6 +101,7 @@ func newIndexCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		newIndexDeleteCmd(),
 		newIndexListCmd(streams),
 		newIndexUpdateCmd(streams),
+		newIndexTrustCmd(streams),
 	)
 
 	return indexCmd
@@ -33,6 +33,7 @@ import (
 	"github.com/spf13/cobra"
 	helmaction "helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/releaseutil"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -73,6 +74,18 @@ var addonInstallExample = templates.Examples(`
 
 	# install an addon with a specified version and local path.
 	kbcli addon install apecloud-mysql --version 0.7.0 --path /path/to/local/chart
+
+	# install an addon with the image registry overlay generated by 'kbcli addon images --rewrite-registry'
+	kbcli addon install apecloud-mysql --version 0.7.0 --set image.registry=registry.example.com
+
+	# install an addon from a bundle created by 'kbcli addon pack'
+	kbcli addon install --from-bundle apecloud-mysql-0.7.0.tgz
+
+	# install an addon from a bundle and pull the images from an internal registry
+	kbcli addon install --from-bundle apecloud-mysql-0.7.0.tgz --registry-rewrite docker.io=registry.example.com
+
+	# install an addon from a bundle, and push the chart in the bundle to an internal registry
+	kbcli addon install --from-bundle apecloud-mysql-0.7.0.tgz --registry-rewrite registry.example.com --chart-registry oci://registry.example.com/charts
 `)
 
 type baseOption struct {
@@ -114,8 +127,21 @@ type installOption struct {
 	clusterChartRepo string
 	// the local path contains addon CRs and needs to be specified when operating offline
 	path string
+	// the addon bundle created by 'kbcli addon pack' to install from
+	fromBundle string
+	// rewrite the image registries when installing from a bundle, in the format of 'old=new' or 'new'
+	registryRewrites []string
+	// the OCI registry to push the chart in the bundle to, the addon is installed from the pushed chart
+	chartRegistry string
+	// use insecure HTTP connections to push the chart
+	plainHTTP bool
+	// the helm values to set in addition to the install values of the addon
+	setValues []string
 
 	addon *extensionsv1alpha1.Addon
+	// the file of the addon in the index
+	addonPath string
+	bundle    *bundleContent
 }
 
 func newInstallOption(f cmdutil.Factory, streams genericiooptions.IOStreams) *installOption {
@@ -135,22 +161,39 @@ func newInstallCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 	cmd := &cobra.Command{
 		Use:     "install",
 		Short:   "Install KubeBlocks addon",
-		Args:    cobra.ExactArgs(1),
+		Args:    cobra.MaximumNArgs(1),
 		Example: addonInstallExample,
 		PersistentPreRun: func(_ *cobra.Command, _ []string) {
 			util.CheckErr(addDefaultIndex())
 		},
 		ValidArgsFunction: addonNameCompletionFunc,
+		PreRun: func(cmd *cobra.Command, _ []string) {
+			// the version is read from the bundle
+			if o.fromBundle != "" {
+				_ = cmd.Flags().SetAnnotation("version", cobra.BashCompOneRequiredFlag, []string{"false"})
+			}
+		},
 		Run: func(cmd *cobra.Command, args []string) {
-			o.name = args[0]
+			if len(args) > 0 {
+				o.name = args[0]
+			}
+			if o.name == "" && o.fromBundle == "" {
+				util.CheckErr(fmt.Errorf("missing addon name"))
+			}
 			util.CheckErr(o.Complete())
 			util.CheckErr(o.Validate())
-			util.CheckErr(o.process09ClusterDefAndComponentVersions())
+			if o.bundle == nil {
+				util.CheckErr(o.process09ClusterDefAndComponentVersions())
+			}
 			util.CheckErr(o.Run(f, streams))
 			// avoid unnecessary messages for upgrade
 			fmt.Fprintf(o.Out, "addon %s installed successfully\n", o.name)
 			fmt.Fprintf(o.Out, "You can run the following command to register a cluster:\n")
 			fmt.Fprint(o.Out, color.GreenString(fmt.Sprintf("  kbcli cluster register %s --engine %s --repo %s --version <cluster-chart-version>\n", o.name, o.name, o.clusterChartRepo)))
+			if registry.IsOCI(o.clusterChartRepo) {
+				// the latest chart is registered if the version is not specified
+				return
+			}
 			fmt.Fprintf(o.Out, "To find available cluster chart versions, run:\n")
 			fmt.Fprint(o.Out, color.GreenString(fmt.Sprintf("  helm search repo kubeblocks-addons/%s-cluster --versions\n", o.name)))
 		},
@@ -159,8 +202,13 @@ func newInstallCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 	cmd.Flags().StringVar(&o.version, "version", "", "specify the addon version to install, run 'kbcli addon search <addon-name>' to get the available versions")
 	cmd.Flags().StringVar(&o.index, "index", types.DefaultIndexName, "specify the addon index, use 'kubeblocks' by default")
 	cmd.Flags().StringVar(&o.clusterChartVersion, "cluster-chart-version", "", "specify the cluster chart version, use the same version as the addon by default")
-	cmd.Flags().StringVar(&o.clusterChartRepo, "cluster-chart-repo", types.ClusterChartsRepoURL, "specify the repo of cluster chart, use the url of 'kubeblocks-addons' by default")
+	cmd.Flags().StringVar(&o.clusterChartRepo, "cluster-chart-repo", types.ClusterChartsRepoURL, "specify the helm repo or OCI registry (oci://) of cluster chart, use the url of 'kubeblocks-addons' by default")
 	cmd.Flags().StringVar(&o.path, "path", "", "specify the local path contains addon CRs and needs to be specified when operating offline")
+	cmd.Flags().StringVar(&o.fromBundle, "from-bundle", "", "install the addon from a bundle created by 'kbcli addon pack'")
+	cmd.Flags().StringArrayVar(&o.setValues, "set", nil, "set helm values of the addon on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	cmd.Flags().StringArrayVar(&o.registryRewrites, "registry-rewrite", nil, "rewrite the image registry when installing from a bundle, in the format of 'old=new', or 'new' to rewrite all registries")
+	cmd.Flags().StringVar(&o.chartRegistry, "chart-registry", "", "push the chart in the bundle to the OCI registry (oci://) and install the addon from it, the charts image of the addon is used if not specified")
+	cmd.Flags().BoolVar(&o.plainHTTP, "plain-http", false, "use insecure HTTP connections to push the chart to the chart registry")
 
 	_ = cmd.MarkFlagRequired("version")
 
@@ -169,19 +217,59 @@ func newInstallCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 
 // Complete will finalize the basic K8s client configuration and find the corresponding addon from the index
 func (o *installOption) Complete() error {
-	var (
-		err    error
-		addons []searchResult
-	)
-
-	if err = o.baseOption.complete(); err != nil {
+	if err := o.baseOption.complete(); err != nil {
 		return err
 	}
 
+	if o.fromBundle != "" {
+		if o.chartRegistry != "" && !registry.IsOCI(o.chartRegistry) {
+			return fmt.Errorf("the chart registry %s should start with oci://", o.chartRegistry)
+		}
+		if err := o.completeFromBundle(); err != nil {
+			return err
+		}
+		return o.completeSetValues()
+	}
+	if len(o.registryRewrites) > 0 || o.chartRegistry != "" {
+		return fmt.Errorf("--registry-rewrite and --chart-registry can only be used with --from-bundle")
+	}
+
 	if o.version == "" {
 		return fmt.Errorf("please specify the version, run 'kbcli addon search %s' to get the available versions", o.name)
 	}
 
+	// complete the version of the cluster chart
+	if o.clusterChartVersion == "" {
+		o.clusterChartVersion = o.version
+	}
+	if err := o.findAddon(); err != nil {
+		return err
+	}
+	if err := o.verifyAddon(); err != nil {
+		return err
+	}
+	return o.completeSetValues()
+}
+
+// completeSetValues appends the values specified by --set to the install values of the addon
+func (o *installOption) completeSetValues() error {
+	if len(o.setValues) == 0 {
+		return nil
+	}
+	if o.addon.Spec.Helm == nil {
+		return fmt.Errorf("--set can only be used for the addon installed by a helm chart")
+	}
+	o.addon.Spec.Helm.InstallValues.SetValues = append(o.addon.Spec.Helm.InstallValues.SetValues, o.setValues...)
+	return nil
+}
+
+// findAddon searches the addon with the specified version from the index, it does not need a K8s cluster
+func (o *installOption) findAddon() error {
+	var (
+		err    error
+		addons []searchResult
+	)
+
 	// search specified addon and match its index
 	if _, err = semver.NewVersion(o.version); err != nil && o.version != "" {
 		return fmt.Errorf("the version %s does not comply with the standards", o.version)
@@ -212,26 +300,25 @@ func (o *installOption) Complete() error {
 		return vi.GreaterThan(vj)
 	})
 
-	// descending order of versions
+	// descending order of versions, use the latest version if the version is not specified
 	for _, item := range addons {
 		if o.path != "" || item.index.name == o.index {
-			if o.version == getAddonVersion(item.addon) {
+			if o.version == "" || o.version == getAddonVersion(item.addon) {
 				o.addon = item.addon
+				o.addonPath = item.path
 				break
 			}
 		}
 	}
 
-	// complete the version of the cluster chart
-	if o.clusterChartVersion == "" {
-		o.clusterChartVersion = o.version
-	}
-
 	if o.addon == nil {
 		var addonInfo = o.name
-		addonInfo += "-" + o.version
+		if o.version != "" {
+			addonInfo += "-" + o.version
+		}
 		return fmt.Errorf("addon '%s' not found in the index '%s'", addonInfo, o.index)
 	}
+	o.version = getAddonVersion(o.addon)
 	return nil
 }
 
@@ -270,6 +357,11 @@ It will automatically skip version checks, which may result in the cluster not r
 
 // Run will apply the addon.yaml to K8s
 func (o *installOption) Run(f cmdutil.Factory, streams genericiooptions.IOStreams) error {
+	if o.bundle != nil {
+		if err := o.completeBundleAddon(); err != nil {
+			return err
+		}
+	}
 	item, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o.addon)
 	if err != nil {
 		return err
@@ -278,6 +370,9 @@ func (o *installOption) Run(f cmdutil.Factory, streams genericiooptions.IOStream
 	if err != nil {
 		return err
 	}
+	if o.bundle != nil {
+		o.printRewrittenImages()
+	}
 	return nil
 }
 
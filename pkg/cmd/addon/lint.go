@@ -0,0 +1,436 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	parametersv1alpha1 "github.com/apecloud/kubeblocks/apis/parameters/v1alpha1"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/strvals"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+	"github.com/apecloud/kbcli/pkg/util/helm"
+	"github.com/apecloud/kbcli/pkg/util/lint"
+)
+
+const valuesSchemaFile = "values.schema.json"
+
+var addonLintExample = templates.Examples(`
+	# lint an addon chart in a local directory
+	kbcli addon lint ./mysql
+
+	# lint a packaged addon chart with the values to render it
+	kbcli addon lint mysql-1.0.0.tgz --set image.registry=registry.example.com
+
+	# lint a cluster chart, the values.schema.json is used to build the flags of 'kbcli cluster create'
+	kbcli addon lint ./mysql-cluster
+`)
+
+type lintOption struct {
+	genericiooptions.IOStreams
+
+	path      string
+	setValues []string
+}
+
+func newLintCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &lintOption{IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:     "lint PATH",
+		Short:   "Lint an addon chart or a cluster chart in a local directory or a packaged chart",
+		Args:    cobra.ExactArgs(1),
+		Example: addonLintExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.path = args[0]
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringArrayVar(&o.setValues, "set", nil, "set helm values to render the chart (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	return cmd
+}
+
+func (o *lintOption) run() error {
+	c, err := loader.Load(o.path)
+	if err != nil {
+		return fmt.Errorf("failed to load the chart %s: %w", o.path, err)
+	}
+	values := map[string]interface{}{}
+	for _, v := range o.setValues {
+		if err = strvals.ParseInto(v, values); err != nil {
+			return fmt.Errorf("invalid value %s: %w", v, err)
+		}
+	}
+	results := lintChart(c, values)
+	results.Print(o.Out, "Chart", c.Name(), "OBJECT")
+	if errCount := results.ErrorCount(); errCount > 0 {
+		return fmt.Errorf("%d error(s) found in chart %s", errCount, c.Name())
+	}
+	return nil
+}
+
+// lintChart validates the values.schema.json of the chart, renders it and checks the cross-references
+// between the rendered KubeBlocks objects.
+func lintChart(c *chart.Chart, values map[string]interface{}) lint.Results {
+	l := &chartLinter{}
+	l.lintSchema(c)
+	manifests, err := cluster.GetManifests(c, true, metav1.NamespaceDefault, c.Name(), helm.FakeKubeVersion, values)
+	if err != nil {
+		l.results.Errorf("chart "+c.Name(), "failed to render the chart: %s", err.Error())
+		return l.results
+	}
+	if err = l.loadObjects(manifests); err != nil {
+		l.results.Errorf("chart "+c.Name(), "%s", err.Error())
+		return l.results
+	}
+	l.lintClusterDefinitions()
+	l.lintShardingDefinitions()
+	l.lintComponentVersions()
+	l.lintComponentDefinitions()
+	l.lintBackupPolicyTemplates()
+	l.lintParamConfigRenderers()
+	return l.results
+}
+
+type chartLinter struct {
+	clusterDefs     []*kbappsv1.ClusterDefinition
+	shardingDefs    []*kbappsv1.ShardingDefinition
+	compDefs        []*kbappsv1.ComponentDefinition
+	compVersions    []*kbappsv1.ComponentVersion
+	bpts            []*dpv1alpha1.BackupPolicyTemplate
+	configRenderers []*parametersv1alpha1.ParamConfigRenderer
+	actionSets      map[string]bool
+	paramsDefs      map[string]bool
+	configMaps      map[string]bool
+	results         lint.Results
+}
+
+// lintSchema makes sure 'kbcli cluster create' can build the flags from the values.schema.json of the chart
+// and its sub chart, and the default values match the schema.
+func (l *chartLinter) lintSchema(c *chart.Chart) {
+	if c.Schema == nil {
+		return
+	}
+	ci := &cluster.ChartInfo{Chart: c}
+	if err := ci.BuildClusterSchema(); err != nil {
+		l.results.Errorf(valuesSchemaFile, "%s", err.Error())
+		return
+	}
+	lint := func(file string, s *spec.Schema, values map[string]interface{}) {
+		if s == nil {
+			return
+		}
+		errCount := l.results.ErrorCount()
+		for _, name := range s.Required {
+			prop, ok := s.Properties[name]
+			switch {
+			case !ok:
+				l.results.Errorf(file, "required property %s is not defined in the properties", name)
+			case len(prop.Type) == 0:
+				l.results.Errorf(file, "type is required for the required property %s", name)
+			}
+		}
+		for name, prop := range s.Properties {
+			l.lintSchemaProperty(file, name, &prop)
+		}
+		if l.results.ErrorCount() > errCount {
+			return
+		}
+		if values == nil {
+			values = map[string]interface{}{}
+		}
+		if err := flags.BuildFlagsBySchema(&cobra.Command{}, s); err != nil {
+			l.results.Errorf(file, "failed to build the flags of 'kbcli cluster create': %s", err.Error())
+			return
+		}
+		if err := cluster.ValidateValues(&cluster.ChartInfo{Schema: s}, values); err != nil {
+			l.results.Errorf(file, "the default values do not match the schema: %s", err.Error())
+		}
+	}
+	lint(valuesSchemaFile, ci.Schema, c.Values)
+	if ci.SubSchema != nil {
+		sub := c.Dependencies()[0]
+		lint(fmt.Sprintf("charts/%s/%s", sub.Name(), valuesSchemaFile), ci.SubSchema, sub.Values)
+	}
+}
+
+// lintSchemaProperty checks the array properties have the items, which are required to build the flags
+func (l *chartLinter) lintSchemaProperty(file, name string, prop *spec.Schema) {
+	switch {
+	case prop.Type.Contains("array"):
+		if prop.Items == nil || prop.Items.Schema == nil {
+			l.results.Errorf(file, "items is required for the array property %s", name)
+			return
+		}
+		l.lintSchemaProperty(file, name+".items", prop.Items.Schema)
+	case prop.Type.Contains("object"):
+		for subName, p := range prop.Properties {
+			l.lintSchemaProperty(file, name+"."+subName, &p)
+		}
+	}
+}
+
+func (l *chartLinter) loadObjects(manifests map[string]string) error {
+	l.actionSets = map[string]bool{}
+	l.paramsDefs = map[string]bool{}
+	l.configMaps = map[string]bool{}
+	keys := make([]string, 0, len(manifests))
+	for k := range manifests {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+	for _, k := range keys {
+		data := []byte(manifests[k])
+		obj := metav1.PartialObjectMetadata{}
+		if err := yaml.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		var (
+			target interface{}
+			err    error
+		)
+		switch obj.Kind {
+		case types.KindClusterDef:
+			cd := &kbappsv1.ClusterDefinition{}
+			l.clusterDefs, target = append(l.clusterDefs, cd), cd
+		case types.KindShardingDef:
+			sd := &kbappsv1.ShardingDefinition{}
+			l.shardingDefs, target = append(l.shardingDefs, sd), sd
+		case types.KindComponentDef:
+			compDef := &kbappsv1.ComponentDefinition{}
+			l.compDefs, target = append(l.compDefs, compDef), compDef
+		case types.KindComponentVersion:
+			cmpv := &kbappsv1.ComponentVersion{}
+			l.compVersions, target = append(l.compVersions, cmpv), cmpv
+		case types.KindBackupPolicyTemplate:
+			bpt := &dpv1alpha1.BackupPolicyTemplate{}
+			l.bpts, target = append(l.bpts, bpt), bpt
+		case types.KindParameterConfigRender:
+			pcr := &parametersv1alpha1.ParamConfigRenderer{}
+			l.configRenderers, target = append(l.configRenderers, pcr), pcr
+		case types.KindActionSet:
+			l.actionSets[obj.Name] = true
+		case types.KindParametersDef:
+			l.paramsDefs[obj.Name] = true
+		case types.KindConfigMap:
+			l.configMaps[obj.Name] = true
+		}
+		if target == nil {
+			continue
+		}
+		if err = yaml.Unmarshal(data, target); err != nil {
+			return fmt.Errorf("invalid %s %s: %s", obj.Kind, obj.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// matchedCompDefs returns the names of the ComponentDefinitions matched by the name prefix or regex pattern
+func (l *chartLinter) matchedCompDefs(pattern string) []string {
+	var names []string
+	for _, compDef := range l.compDefs {
+		if cluster.CompatibleComponentDefs([]string{pattern}, compDef.Name) {
+			names = append(names, compDef.Name)
+		}
+	}
+	return names
+}
+
+func (l *chartLinter) lintClusterDefinitions() {
+	for _, cd := range l.clusterDefs {
+		object := fmt.Sprintf("%s/%s", types.KindClusterDef, cd.Name)
+		for _, t := range cd.Spec.Topologies {
+			names := map[string]bool{}
+			for _, comp := range t.Components {
+				names[comp.Name] = true
+				if len(l.matchedCompDefs(comp.CompDef)) == 0 {
+					l.results.Errorf(object, "component %s of topology %s references ComponentDefinition %s which is not found in the chart", comp.Name, t.Name, comp.CompDef)
+				}
+			}
+			for _, sharding := range t.Shardings {
+				names[sharding.Name] = true
+				if !l.shardingDefExists(sharding.ShardingDef) {
+					l.results.Errorf(object, "sharding %s of topology %s references ShardingDefinition %s which is not found in the chart", sharding.Name, t.Name, sharding.ShardingDef)
+				}
+			}
+			if t.Orders == nil {
+				continue
+			}
+			for _, order := range [][]string{t.Orders.Provision, t.Orders.Terminate, t.Orders.Update} {
+				for _, stage := range order {
+					for _, name := range strings.Split(stage, ",") {
+						if name = strings.TrimSpace(name); name != "" && !names[name] {
+							l.results.Errorf(object, "the orders of topology %s reference %s which is not a component or sharding of the topology", t.Name, name)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func (l *chartLinter) shardingDefExists(pattern string) bool {
+	for _, sd := range l.shardingDefs {
+		if cluster.CompatibleComponentDefs([]string{pattern}, sd.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *chartLinter) lintShardingDefinitions() {
+	for _, sd := range l.shardingDefs {
+		if len(l.matchedCompDefs(sd.Spec.Template.CompDef)) == 0 {
+			l.results.Errorf(fmt.Sprintf("%s/%s", types.KindShardingDef, sd.Name),
+				"the template references ComponentDefinition %s which is not found in the chart", sd.Spec.Template.CompDef)
+		}
+	}
+}
+
+func (l *chartLinter) lintComponentVersions() {
+	covered := map[string]bool{}
+	for _, cmpv := range l.compVersions {
+		object := fmt.Sprintf("%s/%s", types.KindComponentVersion, cmpv.Name)
+		releases := map[string]bool{}
+		for _, r := range cmpv.Spec.Releases {
+			releases[r.Name] = true
+		}
+		for i, rule := range cmpv.Spec.CompatibilityRules {
+			for _, pattern := range rule.CompDefs {
+				matched := l.matchedCompDefs(pattern)
+				if len(matched) == 0 {
+					l.results.Errorf(object, "compatibility rule %d references ComponentDefinition %s which is not found in the chart", i, pattern)
+				}
+				for _, name := range matched {
+					covered[name] = true
+				}
+			}
+			for _, r := range rule.Releases {
+				if !releases[r] {
+					l.results.Errorf(object, "compatibility rule %d references release %s which is not defined in the releases", i, r)
+				}
+			}
+		}
+	}
+	if len(l.compVersions) == 0 {
+		return
+	}
+	for _, compDef := range l.compDefs {
+		if !covered[compDef.Name] {
+			l.results.Warnf(fmt.Sprintf("%s/%s", types.KindComponentDef, compDef.Name), "it is not matched by any compatibility rule of the ComponentVersions")
+		}
+	}
+}
+
+func (l *chartLinter) lintComponentDefinitions() {
+	for _, compDef := range l.compDefs {
+		object := fmt.Sprintf("%s/%s", types.KindComponentDef, compDef.Name)
+		for _, tpl := range compDef.Spec.Configs {
+			if tpl.Template != "" && !l.configMaps[tpl.Template] {
+				l.results.Errorf(object, "config template %s references ConfigMap %s which is not found in the chart", tpl.Name, tpl.Template)
+			}
+		}
+		for _, tpl := range compDef.Spec.Scripts {
+			if tpl.Template != "" && !l.configMaps[tpl.Template] {
+				l.results.Errorf(object, "script template %s references ConfigMap %s which is not found in the chart", tpl.Name, tpl.Template)
+			}
+		}
+	}
+}
+
+func (l *chartLinter) lintBackupPolicyTemplates() {
+	for _, bpt := range l.bpts {
+		object := fmt.Sprintf("%s/%s", types.KindBackupPolicyTemplate, bpt.Name)
+		for _, pattern := range bpt.Spec.CompDefs {
+			if len(l.matchedCompDefs(pattern)) == 0 {
+				l.results.Errorf(object, "it references ComponentDefinition %s which is not found in the chart", pattern)
+			}
+		}
+		for _, method := range bpt.Spec.BackupMethods {
+			if method.ActionSetName != "" && !l.actionSets[method.ActionSetName] {
+				l.results.Errorf(object, "backup method %s references ActionSet %s which is not found in the chart", method.Name, method.ActionSetName)
+			}
+		}
+	}
+}
+
+func (l *chartLinter) lintParamConfigRenderers() {
+	referenced := map[string]bool{}
+	for _, pcr := range l.configRenderers {
+		object := fmt.Sprintf("%s/%s", types.KindParameterConfigRender, pcr.Name)
+		for _, name := range pcr.Spec.ParametersDefs {
+			referenced[name] = true
+			if !l.paramsDefs[name] {
+				l.results.Errorf(object, "it references ParametersDefinition %s which is not found in the chart", name)
+			}
+		}
+		var compDef *kbappsv1.ComponentDefinition
+		for i := range l.compDefs {
+			if l.compDefs[i].Name == pcr.Spec.ComponentDef {
+				compDef = l.compDefs[i]
+				break
+			}
+		}
+		if compDef == nil {
+			l.results.Errorf(object, "it references ComponentDefinition %s which is not found in the chart", pcr.Spec.ComponentDef)
+			continue
+		}
+		for _, config := range pcr.Spec.Configs {
+			if config.TemplateName == "" {
+				continue
+			}
+			found := false
+			for _, tpl := range compDef.Spec.Configs {
+				if tpl.Name == config.TemplateName {
+					found = true
+					break
+				}
+			}
+			if !found {
+				l.results.Errorf(object, "config %s references template %s which is not a config template of ComponentDefinition %s", config.Name, config.TemplateName, compDef.Name)
+			}
+		}
+	}
+	var unused []string
+	for name := range l.paramsDefs {
+		if !referenced[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	for _, name := range unused {
+		l.results.Warnf(fmt.Sprintf("%s/%s", types.KindParametersDef, name), "it is not referenced by any ParamConfigRenderer")
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/releaseutil"
+
+	"github.com/apecloud/kbcli/pkg/util/lint"
+)
+
+var _ = Describe("addon lint test", func() {
+	const manifest = `---
+apiVersion: apps.kubeblocks.io/v1
+kind: ClusterDefinition
+metadata:
+  name: mysql
+spec:
+  topologies:
+  - name: replication
+    components:
+    - name: mysql
+      compDef: mysql-8.0
+    - name: proxy
+      compDef: proxysql-
+    orders:
+      provision:
+      - mysql,proxy
+      - orchestrator
+---
+apiVersion: apps.kubeblocks.io/v1
+kind: ComponentDefinition
+metadata:
+  name: mysql-8.0-1.0.0
+spec:
+  configs:
+  - name: mysql-config
+    template: mysql-config-template
+  scripts:
+  - name: mysql-scripts
+    template: mysql-scripts
+---
+apiVersion: apps.kubeblocks.io/v1
+kind: ComponentVersion
+metadata:
+  name: mysql
+spec:
+  compatibilityRules:
+  - compDefs:
+    - ^mysql-8.0
+    releases:
+    - 8.0.30
+    - 8.0.31
+  releases:
+  - name: 8.0.30
+    serviceVersion: 8.0.30
+---
+apiVersion: dataprotection.kubeblocks.io/v1alpha1
+kind: BackupPolicyTemplate
+metadata:
+  name: mysql-backup-policy-template
+spec:
+  compDefs:
+  - mysql-8.0
+  backupMethods:
+  - name: xtrabackup
+    actionSetName: mysql-xtrabackup
+  - name: volume-snapshot
+    actionSetName: mysql-snapshot
+---
+apiVersion: dataprotection.kubeblocks.io/v1alpha1
+kind: ActionSet
+metadata:
+  name: mysql-xtrabackup
+---
+apiVersion: parameters.kubeblocks.io/v1alpha1
+kind: ParametersDefinition
+metadata:
+  name: mysql-8.0-pd
+---
+apiVersion: parameters.kubeblocks.io/v1alpha1
+kind: ParametersDefinition
+metadata:
+  name: mysql-unused-pd
+---
+apiVersion: parameters.kubeblocks.io/v1alpha1
+kind: ParamConfigRenderer
+metadata:
+  name: mysql-8.0-pcr
+spec:
+  componentDef: mysql-8.0-1.0.0
+  parametersDefs:
+  - mysql-8.0-pd
+  - mysql-8.0-missing-pd
+  configs:
+  - name: my.cnf
+    templateName: mysql-config
+  - name: proxysql.cnf
+    templateName: proxysql-config
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: mysql-config-template
+`
+
+	It("lint cross-references", func() {
+		l := &chartLinter{}
+		Expect(l.loadObjects(releaseutil.SplitManifests(manifest))).Should(Succeed())
+		Expect(l.clusterDefs).Should(HaveLen(1))
+		Expect(l.compDefs).Should(HaveLen(1))
+		Expect(l.actionSets).Should(HaveKey("mysql-xtrabackup"))
+
+		l.lintClusterDefinitions()
+		l.lintComponentVersions()
+		l.lintComponentDefinitions()
+		l.lintBackupPolicyTemplates()
+		l.lintParamConfigRenderers()
+		var messages []string
+		for _, r := range l.results {
+			if r.Level == lint.LevelError {
+				messages = append(messages, r.Target+": "+r.Message)
+			}
+		}
+		Expect(messages).Should(ConsistOf(
+			ContainSubstring("component proxy of topology replication references ComponentDefinition proxysql-"),
+			ContainSubstring("reference orchestrator which is not a component"),
+			ContainSubstring("references release 8.0.31"),
+			ContainSubstring("script template mysql-scripts references ConfigMap mysql-scripts"),
+			ContainSubstring("backup method volume-snapshot references ActionSet mysql-snapshot"),
+			ContainSubstring("ParametersDefinition mysql-8.0-missing-pd"),
+			ContainSubstring("config proxysql.cnf references template proxysql-config"),
+		))
+		Expect(l.results).Should(ContainElement(lint.Result{
+			Level:   lint.LevelWarning,
+			Target:  "ParametersDefinition/mysql-unused-pd",
+			Message: "it is not referenced by any ParamConfigRenderer",
+		}))
+	})
+
+	It("lint values schema", func() {
+		newChart := func(schema string, values map[string]interface{}) *chart.Chart {
+			return &chart.Chart{
+				Metadata: &chart.Metadata{Name: "mysql-cluster", Version: "1.0.0", APIVersion: chart.APIVersionV2},
+				Schema:   []byte(schema),
+				Values:   values,
+			}
+		}
+
+		l := &chartLinter{}
+		l.lintSchema(newChart(`{"type":"object","properties":{"replicas":{"type":"integer","minimum":1}}}`,
+			map[string]interface{}{"replicas": 1}))
+		Expect(l.results).Should(BeEmpty())
+
+		l = &chartLinter{}
+		l.lintSchema(newChart(`{"type":"object","required":["mode"],"properties":{"replicas":{"type":"integer"}}}`, nil))
+		Expect(l.results).Should(HaveLen(1))
+		Expect(l.results[0].Message).Should(ContainSubstring("required property mode is not defined"))
+
+		l = &chartLinter{}
+		l.lintSchema(newChart(`{"type":"object","properties":{"nodes":{"type":"array"}}}`, nil))
+		Expect(l.results).Should(HaveLen(1))
+		Expect(l.results[0].Message).Should(ContainSubstring("items is required for the array property nodes"))
+
+		l = &chartLinter{}
+		l.lintSchema(newChart(`{"type":"object","properties":{"nodes":{"type":"array","items":{"type":"array","items":{"type":"string"}}}}}`, nil))
+		Expect(l.results).Should(HaveLen(1))
+		Expect(l.results[0].Message).Should(ContainSubstring("failed to build the flags"))
+
+		l = &chartLinter{}
+		l.lintSchema(newChart(`{"type":"object","properties":{"replicas":{"type":"integer","minimum":1}}}`,
+			map[string]interface{}{"replicas": 0}))
+		Expect(l.results).Should(HaveLen(1))
+		Expect(l.results[0].Message).Should(ContainSubstring("the default values do not match the schema"))
+	})
+})
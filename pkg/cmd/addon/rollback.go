@@ -0,0 +1,241 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	cmdcluster "github.com/apecloud/kbcli/pkg/cmd/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/helm"
+	"github.com/apecloud/kbcli/pkg/util/prompt"
+)
+
+var addonRollbackExample = templates.Examples(`
+	# roll back an addon to the previous revision
+	kbcli addon rollback apecloud-mysql
+
+	# roll back an addon to the specified revision
+	kbcli addon rollback apecloud-mysql --revision 2
+
+	# show the release history of an addon without rolling back
+	kbcli addon rollback apecloud-mysql --history
+`)
+
+type rollbackOption struct {
+	*installOption
+
+	// the revision to roll back to, 0 means the previous revision
+	revision int
+	// only show the release history
+	history     bool
+	autoApprove bool
+
+	helmCfg  *helm.Config
+	releases []*release.Release
+	current  *release.Release
+	target   *release.Release
+}
+
+func newRollbackCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &rollbackOption{installOption: newInstallOption(f, streams)}
+	cmd := &cobra.Command{
+		Use:               "rollback NAME",
+		Short:             "Roll back an addon to a previous helm revision",
+		Args:              cobra.ExactArgs(1),
+		Example:           addonRollbackExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, o.GVR),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.name = args[0]
+			util.CheckErr(o.complete())
+			printReleaseHistory(o.Out, o.releases)
+			if o.history {
+				return
+			}
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().IntVar(&o.revision, "revision", 0, "the revision to roll back to, use the previous revision by default")
+	cmd.Flags().BoolVar(&o.history, "history", false, "only show the release history of the addon")
+	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "skip interactive approval before rolling back the addon")
+	cmd.Flags().StringVar(&o.index, "index", types.DefaultIndexName, "specify the addon index to find the addon of the rolled back version, use 'kubeblocks' by default")
+	cmd.Flags().StringVar(&o.path, "path", "", "specify the local path contains addon CRs and needs to be specified when operating offline")
+	cmd.Flags().StringVar(&o.clusterChartRepo, "cluster-chart-repo", types.ClusterChartsRepoURL, "specify the helm repo or OCI registry (oci://) of cluster chart, use the url of 'kubeblocks-addons' by default")
+	return cmd
+}
+
+func (o *rollbackOption) complete() error {
+	if err := o.baseOption.complete(); err != nil {
+		return err
+	}
+	namespace, err := util.GetKubeBlocksNamespace(o.Client, "")
+	if err != nil {
+		return err
+	}
+	o.helmCfg = helm.NewConfig(namespace, "", "", klog.V(1).Enabled())
+	if o.releases, err = helm.GetReleaseHistory(o.helmCfg, util.BuildAddonReleaseName(o.name)); err != nil {
+		return fmt.Errorf("failed to get the release history of addon %s: %w", o.name, err)
+	}
+	return nil
+}
+
+func (o *rollbackOption) validate() error {
+	var err error
+	if o.current, o.target, err = selectRollbackRevision(o.releases, o.revision); err != nil {
+		return err
+	}
+	// the addon is patched to the rolled back version, otherwise the addon controller will upgrade the release again,
+	// so the addon of the version must be found and trusted before the release is rolled back
+	o.version = chartVersion(o.target)
+	if err = o.findAddon(); err != nil {
+		return fmt.Errorf("failed to find addon %s-%s in the index: %w", o.name, o.version, err)
+	}
+	if err = o.verifyAddon(); err != nil {
+		return err
+	}
+	clusters, err := cluster.GetCompDefClusters(o.Dynamic)
+	if err != nil {
+		return err
+	}
+	changes, err := diffReleaseObjects(o.current.Manifest, o.target.Manifest)
+	if err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("Roll back addon %s from revision %d (%s) to revision %d (%s).", o.name,
+		o.current.Version, chartVersion(o.current), o.target.Version, chartVersion(o.target))
+	if warning := newerCompDefsWarning(changes, clusters); warning != "" {
+		msg += "\n" + warning
+	}
+	if o.autoApprove {
+		fmt.Fprintln(o.Out, msg)
+		return nil
+	}
+	return prompt.Confirm(nil, o.In, msg, "Please type 'Yes/yes' to confirm your operation:")
+}
+
+func (o *rollbackOption) run() error {
+	if err := helm.Rollback(o.helmCfg, util.BuildAddonReleaseName(o.name), o.target.Version, false, 0); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "addon %s is rolled back to revision %d\n", o.name, o.target.Version)
+
+	data, err := json.Marshal(o.addon)
+	if err != nil {
+		return err
+	}
+	if _, err = o.Dynamic.Resource(o.GVR).Patch(context.Background(), o.name, ktypes.MergePatchType, data, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+
+	// register the cluster chart of the rolled back version if it has been registered
+	for _, item := range cluster.GlobalClusterChartConfig {
+		if item.Name != cluster.ClusterType(o.name) || item.ChartName == fmt.Sprintf("%s-cluster-%s.tgz", o.name, o.version) {
+			continue
+		}
+		if err := cmdcluster.RegisterClusterChart(o.Factory, o.IOStreams, "", o.name, o.version, o.clusterChartRepo); err != nil {
+			printer.Warning(o.Out, "failed to register the cluster chart %s-cluster-%s: %s\n", o.name, o.version, err.Error())
+		}
+	}
+	return nil
+}
+
+// selectRollbackRevision returns the current release and the release of the revision to roll back to,
+// the revision before the current one is used if the revision is 0.
+func selectRollbackRevision(releases []*release.Release, revision int) (*release.Release, *release.Release, error) {
+	if len(releases) == 0 {
+		return nil, nil, fmt.Errorf("no release history found")
+	}
+	current := releases[len(releases)-1]
+	if revision == 0 {
+		if len(releases) < 2 {
+			return nil, nil, fmt.Errorf("no previous revision to roll back to")
+		}
+		return current, releases[len(releases)-2], nil
+	}
+	if revision == current.Version {
+		return nil, nil, fmt.Errorf("revision %d is the current revision", revision)
+	}
+	for _, r := range releases {
+		if r.Version == revision {
+			return current, r, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("revision %d not found in the release history", revision)
+}
+
+// newerCompDefsWarning returns the warning message if the running clusters use the ComponentDefinitions
+// which only exist in the newer revision.
+func newerCompDefsWarning(changes []objectChange, clusters map[string][]string) string {
+	var lines []string
+	for _, c := range changes {
+		if c.kind != types.KindComponentDef || c.mode != helm.Removed || len(clusters[c.name]) == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", c.name, strings.Join(clusters[c.name], ", ")))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return printer.BoldYellow("Warning: the following clusters use the ComponentDefinitions which only exist in the newer revision:") +
+		"\n" + strings.Join(lines, "\n")
+}
+
+func chartVersion(r *release.Release) string {
+	if r.Chart == nil || r.Chart.Metadata == nil {
+		return ""
+	}
+	return r.Chart.Metadata.Version
+}
+
+func printReleaseHistory(out io.Writer, releases []*release.Release) {
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION", "DESCRIPTION")
+	for _, r := range releases {
+		var chart, appVersion, updated, status, description string
+		if r.Chart != nil && r.Chart.Metadata != nil {
+			chart = fmt.Sprintf("%s-%s", r.Chart.Metadata.Name, r.Chart.Metadata.Version)
+			appVersion = r.Chart.Metadata.AppVersion
+		}
+		if r.Info != nil {
+			updated = util.TimeTimeFormat(r.Info.LastDeployed.Time)
+			status = r.Info.Status.String()
+			description = r.Info.Description
+		}
+		tbl.AddRow(r.Version, updated, status, chart, appVersion, description)
+	}
+	tbl.Print()
+}
@@ -0,0 +1,83 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util/helm"
+)
+
+var _ = Describe("addon rollback test", func() {
+	newRelease := func(revision int, version string) *release.Release {
+		return &release.Release{
+			Name:    "kb-addon-mysql",
+			Version: revision,
+			Info:    &release.Info{Status: release.StatusSuperseded, Description: "Upgrade complete"},
+			Chart:   &chart.Chart{Metadata: &chart.Metadata{Name: "mysql", Version: version, AppVersion: "8.0.30"}},
+		}
+	}
+
+	It("select rollback revision", func() {
+		releases := []*release.Release{newRelease(1, "0.9.0"), newRelease(2, "1.0.0"), newRelease(3, "1.0.1")}
+		current, target, err := selectRollbackRevision(releases, 0)
+		Expect(err).Should(Succeed())
+		Expect(current.Version).Should(Equal(3))
+		Expect(target.Version).Should(Equal(2))
+		Expect(chartVersion(target)).Should(Equal("1.0.0"))
+
+		_, target, err = selectRollbackRevision(releases, 1)
+		Expect(err).Should(Succeed())
+		Expect(chartVersion(target)).Should(Equal("0.9.0"))
+
+		_, _, err = selectRollbackRevision(releases, 3)
+		Expect(err).Should(HaveOccurred())
+		_, _, err = selectRollbackRevision(releases, 4)
+		Expect(err).Should(HaveOccurred())
+		_, _, err = selectRollbackRevision(releases[:1], 0)
+		Expect(err).Should(HaveOccurred())
+		_, _, err = selectRollbackRevision(nil, 0)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("warn the ComponentDefinitions only in the newer revision", func() {
+		changes := []objectChange{
+			{name: "mysql-8.0-1.0.1", kind: types.KindComponentDef, mode: helm.Removed},
+			{name: "mysql-8.0-1.0.0", kind: types.KindComponentDef, mode: helm.Added},
+		}
+		Expect(newerCompDefsWarning(changes, nil)).Should(BeEmpty())
+		Expect(newerCompDefsWarning(changes, map[string][]string{
+			"mysql-8.0-1.0.1": {"default/mycluster(mysql)"},
+		})).Should(ContainSubstring("mysql-8.0-1.0.1: default/mycluster(mysql)"))
+	})
+
+	It("print release history", func() {
+		out := &bytes.Buffer{}
+		printReleaseHistory(out, []*release.Release{newRelease(1, "0.9.0"), newRelease(2, "1.0.0")})
+		Expect(out.String()).Should(ContainSubstring("mysql-0.9.0"))
+		Expect(out.String()).Should(ContainSubstring("Upgrade complete"))
+	})
+})
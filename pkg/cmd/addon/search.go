@@ -58,6 +58,8 @@ type searchResult struct {
 	index       index
 	addon       *extensionsv1alpha1.Addon
 	isInstalled bool
+	// the file of the addon in the index
+	path string
 }
 
 type searchOpts struct {
@@ -193,7 +195,7 @@ func searchAddon(name string, indexDir string, theIndex string) ([]searchResult,
 					return filepath.SkipDir
 				}
 				if name == "" || addon.Name == name {
-					res = append(res, searchResult{i, addon, false})
+					res = append(res, searchResult{i, addon, false, path})
 				}
 			}
 			return nil
@@ -0,0 +1,587 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/prompt"
+)
+
+// helmReleaseNameAnnotation is set on the addons installed by the KubeBlocks chart, they are never pruned
+const helmReleaseNameAnnotation = "meta.helm.sh/release-name"
+
+var addonSyncExample = templates.Examples(`
+	# show the plan to sync the installed addons with the manifest
+	kbcli addon sync -f addons.yaml --dry-run
+
+	# sync the installed addons with the manifest
+	kbcli addon sync -f addons.yaml
+
+	# sync the installed addons with the manifest and uninstall the addons not listed in it
+	kbcli addon sync -f addons.yaml --prune
+
+	# an example of the addons manifest, the version is resolved to the latest one if not specified
+	# and the resolved version is pinned in the lockfile 'addons.lock.yaml'
+	addons:
+	- name: apecloud-mysql
+	  version: 1.0.0
+	  values:
+	  - image.registry=registry.example.com
+	- name: redis
+	  index: my-index
+	  enabled: false
+	- name: kafka
+	  dependsOn:
+	  - zookeeper
+	- name: zookeeper
+`)
+
+// addonManifest is the declarative addon set synced by 'kbcli addon sync'
+type addonManifest struct {
+	Addons []addonManifestItem `json:"addons"`
+}
+
+type addonManifestItem struct {
+	Name string `json:"name"`
+	// the index to find the addon, use 'kubeblocks' by default
+	Index string `json:"index,omitempty"`
+	// the addon version, use the locked version or the latest version by default
+	Version string `json:"version,omitempty"`
+	// the helm values to set in addition to the install values of the addon
+	Values []string `json:"values,omitempty"`
+	// whether the addon is enabled, true by default
+	Enabled *bool `json:"enabled,omitempty"`
+	// the addons which must be installed before this addon
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+func (item *addonManifestItem) enabled() bool {
+	return item.Enabled == nil || *item.Enabled
+}
+
+// addonLockfile records the resolved versions and chart digests of the addons
+type addonLockfile struct {
+	Addons []addonLockItem `json:"addons"`
+}
+
+type addonLockItem struct {
+	Name    string `json:"name"`
+	Index   string `json:"index"`
+	Version string `json:"version"`
+	Chart   string `json:"chart"`
+	// the sha256 digest of the chart, it is empty if the chart is stored in the charts image
+	Digest string `json:"digest,omitempty"`
+}
+
+type syncAction string
+
+const (
+	syncInstall   syncAction = "install"
+	syncUpgrade   syncAction = "upgrade"
+	syncEnable    syncAction = "enable"
+	syncDisable   syncAction = "disable"
+	syncUninstall syncAction = "uninstall"
+)
+
+type syncStep struct {
+	action         syncAction
+	name           string
+	currentVersion string
+	targetVersion  string
+	item           *addonManifestItem
+}
+
+type syncOption struct {
+	baseOption
+
+	file        string
+	lockfile    string
+	prune       bool
+	dryRun      bool
+	force       bool
+	autoApprove bool
+	path        string
+
+	manifest *addonManifest
+	lock     *addonLockfile
+	// the addons resolved from the indexes, the key is the addon name
+	resolved  map[string]*extensionsv1alpha1.Addon
+	installed map[string]*extensionsv1alpha1.Addon
+	plan      []syncStep
+}
+
+func newSyncCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &syncOption{
+		baseOption: baseOption{
+			Factory:   f,
+			IOStreams: streams,
+			GVR:       types.AddonGVR(),
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "sync",
+		Short:   "Sync the installed addons with a declarative addons manifest",
+		Args:    cobra.NoArgs,
+		Example: addonSyncExample,
+		PersistentPreRun: func(_ *cobra.Command, _ []string) {
+			util.CheckErr(addDefaultIndex())
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete())
+			printSyncPlan(o.Out, o.plan)
+			if o.dryRun {
+				return
+			}
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "the addons manifest to sync")
+	cmd.Flags().StringVar(&o.lockfile, "lockfile", "", "the lockfile of the resolved addon versions and chart digests, use '<file>.lock.yaml' by default")
+	cmd.Flags().BoolVar(&o.prune, "prune", false, "uninstall the addons which are not listed in the manifest, the addons installed with KubeBlocks are never pruned")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "only print the plan without applying it")
+	cmd.Flags().BoolVar(&o.force, "force", false, "ignore the KubeBlocks version check of the addons")
+	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "skip interactive approval before applying the plan")
+	cmd.Flags().StringVar(&o.path, "path", "", "specify the local path contains addon CRs and needs to be specified when operating offline")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func (o *syncOption) complete() error {
+	var err error
+	if o.manifest, err = loadAddonManifest(o.file); err != nil {
+		return err
+	}
+	if o.lockfile == "" {
+		o.lockfile = strings.TrimSuffix(o.file, filepath.Ext(o.file)) + ".lock.yaml"
+	}
+	if o.lock, err = loadAddonLockfile(o.lockfile); err != nil {
+		return err
+	}
+	if err = o.baseOption.complete(); err != nil {
+		return err
+	}
+	if err = o.resolve(); err != nil {
+		return err
+	}
+	if o.installed, err = o.listInstalledAddons(); err != nil {
+		return err
+	}
+	items, err := sortAddonManifestItems(o.manifest.Addons)
+	if err != nil {
+		return err
+	}
+	o.plan = buildSyncPlan(items, o.resolved, o.installed, o.prune)
+	return nil
+}
+
+// resolve finds the addons of the manifest from the indexes and verifies the chart digests with the lockfile
+func (o *syncOption) resolve() error {
+	dir, err := os.MkdirTemp("", "kbcli-addon-sync-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	o.resolved = map[string]*extensionsv1alpha1.Addon{}
+	var lock addonLockfile
+	for i := range o.manifest.Addons {
+		item := &o.manifest.Addons[i]
+		locked := o.lock.find(item.Name)
+		opt := newInstallOption(o.Factory, o.IOStreams)
+		opt.name = item.Name
+		opt.path = o.path
+		if item.Index != "" {
+			opt.index = item.Index
+		}
+		opt.version = item.Version
+		// pin the version to the locked one if the version is not specified
+		if opt.version == "" && locked != nil && locked.Index == opt.index {
+			opt.version = locked.Version
+		}
+		if err = opt.findAddon(); err != nil {
+			return err
+		}
+		if err = opt.verifyAddon(); err != nil {
+			return err
+		}
+		lockItem, err := lockAddon(opt.addon, opt.index, dir)
+		if err != nil {
+			return err
+		}
+		if locked != nil && locked.Version == lockItem.Version && locked.Digest != lockItem.Digest {
+			return fmt.Errorf("the chart digest of addon %s-%s is %s, which does not match %s in the lockfile %s",
+				item.Name, lockItem.Version, lockItem.Digest, locked.Digest, o.lockfile)
+		}
+		lock.Addons = append(lock.Addons, *lockItem)
+		if opt.addon.Spec.Helm != nil {
+			opt.addon.Spec.Helm.InstallValues.SetValues = append(opt.addon.Spec.Helm.InstallValues.SetValues, item.Values...)
+		}
+		o.resolved[item.Name] = opt.addon
+	}
+	o.lock = &lock
+	return nil
+}
+
+// lockAddon resolves the chart digest of the addon
+func lockAddon(addon *extensionsv1alpha1.Addon, index, dir string) (*addonLockItem, error) {
+	item := &addonLockItem{
+		Name:    addon.Name,
+		Index:   index,
+		Version: getAddonVersion(addon),
+	}
+	if addon.Spec.Helm == nil {
+		return item, nil
+	}
+	item.Chart = addon.Spec.Helm.ChartLocationURL
+	if checkAddonChart(addon) != nil {
+		return item, nil
+	}
+	chartPath, err := downloadAddonChart(addon, dir)
+	if err != nil {
+		return nil, err
+	}
+	if item.Digest, err = fileDigest(chartPath); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (o *syncOption) listInstalledAddons() (map[string]*extensionsv1alpha1.Addon, error) {
+	objs, err := o.Dynamic.Resource(o.GVR).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	installed := map[string]*extensionsv1alpha1.Addon{}
+	for _, obj := range objs.Items {
+		addon := &extensionsv1alpha1.Addon{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, addon); err != nil {
+			return nil, err
+		}
+		installed[addon.Name] = addon
+	}
+	return installed, nil
+}
+
+func (o *syncOption) validate() error {
+	// the lockfile is still written if the addons are up to date
+	if o.autoApprove || len(o.plan) == 0 {
+		return nil
+	}
+	return prompt.Confirm(nil, o.In, "", "Please type 'Yes/yes' to apply the plan:")
+}
+
+func (o *syncOption) run() error {
+	for _, step := range o.plan {
+		var err error
+		switch step.action {
+		case syncInstall:
+			err = o.install(step)
+		case syncUpgrade:
+			err = o.upgrade(step)
+		case syncEnable, syncDisable:
+			err = o.setEnabled(step.name, step.action == syncEnable)
+		case syncUninstall:
+			uo := newUninstallOption(o.Factory, o.IOStreams)
+			uo.names = []string{step.name}
+			uo.baseOption.Dynamic = o.Dynamic
+			uo.baseOption.Client = o.Client
+			if err = uo.checkBeforeUninstall(); err == nil {
+				err = uo.Run()
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s addon %s: %w", step.action, step.name, err)
+		}
+	}
+	if err := writeAddonLockfile(o.lockfile, o.lock); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "addons are synced, the resolved versions are locked in %s\n", o.lockfile)
+	return nil
+}
+
+func (o *syncOption) install(step syncStep) error {
+	opt := newInstallOption(o.Factory, o.IOStreams)
+	opt.baseOption = o.baseOption
+	opt.name = step.name
+	opt.version = step.targetVersion
+	opt.force = o.force
+	opt.addon = o.resolved[step.name]
+	if err := opt.Validate(); err != nil {
+		return err
+	}
+	if err := opt.process09ClusterDefAndComponentVersions(); err != nil {
+		return err
+	}
+	if err := opt.Run(o.Factory, o.IOStreams); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "addon %s installed successfully\n", step.name)
+	return nil
+}
+
+func (o *syncOption) upgrade(step syncStep) error {
+	uo := newUpgradeOption(o.Factory, o.IOStreams)
+	uo.baseOption = o.baseOption
+	uo.name = step.name
+	uo.version = step.targetVersion
+	uo.currentVersion = step.currentVersion
+	uo.force = o.force
+	uo.addon = o.resolved[step.name]
+	if err := uo.Validate(); err != nil {
+		return err
+	}
+	if strings.HasPrefix(uo.currentVersion, "0.9") {
+		if err := uo.process09ClusterDefAndComponentVersions(); err != nil {
+			return err
+		}
+	}
+	return uo.Run(o.Factory, o.IOStreams)
+}
+
+func (o *syncOption) setEnabled(name string, enabled bool) error {
+	install := map[string]interface{}{"enabled": false}
+	if enabled {
+		installSpec := extensionsv1alpha1.AddonInstallSpec{
+			Enabled:              true,
+			AddonInstallSpecItem: extensionsv1alpha1.NewAddonInstallSpecItem(),
+		}
+		b, err := json.Marshal(&installSpec)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(b, &install); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"install": install}})
+	if err != nil {
+		return err
+	}
+	if _, err = o.Dynamic.Resource(o.GVR).Patch(context.Background(), name, ktypes.MergePatchType, data, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+	if enabled {
+		fmt.Fprintf(o.Out, "addon %s enabled\n", name)
+	} else {
+		fmt.Fprintf(o.Out, "addon %s disabled\n", name)
+	}
+	return nil
+}
+
+func loadAddonManifest(file string) (*addonManifest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &addonManifest{}
+	if err = yaml.UnmarshalStrict(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse the addons manifest %s: %w", file, err)
+	}
+	names := map[string]bool{}
+	for _, item := range manifest.Addons {
+		if item.Name == "" {
+			return nil, fmt.Errorf("the name of addon is required in the addons manifest %s", file)
+		}
+		if names[item.Name] {
+			return nil, fmt.Errorf("addon %s is duplicated in the addons manifest %s", item.Name, file)
+		}
+		names[item.Name] = true
+	}
+	return manifest, nil
+}
+
+func loadAddonLockfile(file string) (*addonLockfile, error) {
+	lock := &addonLockfile{}
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse the lockfile %s: %w", file, err)
+	}
+	return lock, nil
+}
+
+func writeAddonLockfile(file string, lock *addonLockfile) error {
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+func (l *addonLockfile) find(name string) *addonLockItem {
+	for i := range l.Addons {
+		if l.Addons[i].Name == name {
+			return &l.Addons[i]
+		}
+	}
+	return nil
+}
+
+// sortAddonManifestItems sorts the addons in dependency order, the order in the manifest is kept
+// for the addons without dependencies between them.
+func sortAddonManifestItems(items []addonManifestItem) ([]*addonManifestItem, error) {
+	index := map[string]*addonManifestItem{}
+	for i := range items {
+		index[items[i].Name] = &items[i]
+	}
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var (
+		sorted []*addonManifestItem
+		visit  func(item *addonManifestItem, path []string) error
+	)
+	visit = func(item *addonManifestItem, path []string) error {
+		switch state[item.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("circular addon dependencies: %s", strings.Join(append(path, item.Name), " -> "))
+		}
+		state[item.Name] = visiting
+		for _, dep := range item.DependsOn {
+			depItem, ok := index[dep]
+			if !ok {
+				return fmt.Errorf("addon %s depends on %s, which is not listed in the addons manifest", item.Name, dep)
+			}
+			if err := visit(depItem, append(path, item.Name)); err != nil {
+				return err
+			}
+		}
+		state[item.Name] = visited
+		sorted = append(sorted, item)
+		return nil
+	}
+	for i := range items {
+		if err := visit(&items[i], nil); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// buildSyncPlan computes the steps to sync the installed addons with the manifest. The disabled and pruned
+// addons are handled first in the reverse dependency order, then the others in the dependency order.
+func buildSyncPlan(items []*addonManifestItem, resolved, installed map[string]*extensionsv1alpha1.Addon, prune bool) []syncStep {
+	var removes, applies []syncStep
+	listed := map[string]bool{}
+	for _, item := range items {
+		listed[item.Name] = true
+		target := resolved[item.Name]
+		targetVersion := getAddonVersion(target)
+		current, ok := installed[item.Name]
+		if !ok {
+			applies = append(applies, syncStep{action: syncInstall, name: item.Name, targetVersion: targetVersion, item: item})
+			if !item.enabled() {
+				applies = append(applies, syncStep{action: syncDisable, name: item.Name, targetVersion: targetVersion, item: item})
+			}
+			continue
+		}
+		currentVersion := getAddonVersion(current)
+		if currentVersion != targetVersion || addonValuesChanged(current, target) {
+			applies = append(applies, syncStep{action: syncUpgrade, name: item.Name, currentVersion: currentVersion, targetVersion: targetVersion, item: item})
+		}
+		switch enabled := isAddonEnabled(current); {
+		case item.enabled() && !enabled:
+			applies = append(applies, syncStep{action: syncEnable, name: item.Name, currentVersion: currentVersion, targetVersion: targetVersion, item: item})
+		case !item.enabled() && enabled:
+			removes = append([]syncStep{{action: syncDisable, name: item.Name, currentVersion: currentVersion, targetVersion: targetVersion, item: item}}, removes...)
+		}
+	}
+	if prune {
+		var names []string
+		for name, addon := range installed {
+			if !listed[name] && addon.Annotations[helmReleaseNameAnnotation] == "" {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		// the unlisted addons may depend on the listed ones, uninstall them first
+		var uninstalls []syncStep
+		for _, name := range names {
+			uninstalls = append(uninstalls, syncStep{action: syncUninstall, name: name, currentVersion: getAddonVersion(installed[name])})
+		}
+		removes = append(uninstalls, removes...)
+	}
+	return append(removes, applies...)
+}
+
+func isAddonEnabled(addon *extensionsv1alpha1.Addon) bool {
+	if addon.Spec.InstallSpec != nil {
+		return addon.Spec.InstallSpec.GetEnabled()
+	}
+	return addon.Spec.Installable != nil && addon.Spec.Installable.AutoInstall
+}
+
+// addonValuesChanged checks whether the install values of the installed addon differ from the target addon,
+// which includes the values of the manifest, so that the values added to or removed from the manifest are synced.
+func addonValuesChanged(current, target *extensionsv1alpha1.Addon) bool {
+	var currentValues, targetValues []string
+	if current.Spec.Helm != nil {
+		currentValues = current.Spec.Helm.InstallValues.SetValues
+	}
+	if target != nil && target.Spec.Helm != nil {
+		targetValues = target.Spec.Helm.InstallValues.SetValues
+	}
+	return !slices.Equal(currentValues, targetValues)
+}
+
+func printSyncPlan(out io.Writer, plan []syncStep) {
+	if len(plan) == 0 {
+		fmt.Fprintln(out, "addons are up to date, nothing to do")
+		return
+	}
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("ACTION", "ADDON", "CURRENT VERSION", "TARGET VERSION")
+	for _, step := range plan {
+		tbl.AddRow(step.action, step.name, step.currentVersion, step.targetVersion)
+	}
+	tbl.Print()
+}
@@ -0,0 +1,181 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("addon sync test", func() {
+	newAddon := func(name, version string, enabled bool, values ...string) *extensionsv1alpha1.Addon {
+		addon := testing.FakeAddon(name)
+		addon.Labels = map[string]string{types.AddonVersionLabelKey: version}
+		addon.Spec.InstallSpec = &extensionsv1alpha1.AddonInstallSpec{Enabled: enabled}
+		addon.Spec.Helm = &extensionsv1alpha1.HelmTypeInstallSpec{
+			InstallValues: extensionsv1alpha1.HelmInstallValues{SetValues: values},
+		}
+		return addon
+	}
+
+	writeManifest := func(content string) string {
+		file := filepath.Join(GinkgoT().TempDir(), "addons.yaml")
+		Expect(os.WriteFile(file, []byte(content), 0644)).Should(Succeed())
+		return file
+	}
+
+	It("load addons manifest", func() {
+		manifest, err := loadAddonManifest(writeManifest(`
+addons:
+- name: mysql
+  version: 1.0.0
+  values:
+  - image.registry=registry.example.com
+- name: redis
+  enabled: false
+`))
+		Expect(err).Should(Succeed())
+		Expect(manifest.Addons).Should(HaveLen(2))
+		Expect(manifest.Addons[0].enabled()).Should(BeTrue())
+		Expect(manifest.Addons[0].Values).Should(Equal([]string{"image.registry=registry.example.com"}))
+		Expect(manifest.Addons[1].enabled()).Should(BeFalse())
+
+		_, err = loadAddonManifest(writeManifest("addons:\n- name: mysql\n- name: mysql\n"))
+		Expect(err).Should(MatchError(ContainSubstring("duplicated")))
+		_, err = loadAddonManifest(writeManifest("addons:\n- name: mysql\n  unknown: true\n"))
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("sort addons in dependency order", func() {
+		items := []addonManifestItem{
+			{Name: "kafka", DependsOn: []string{"zookeeper"}},
+			{Name: "mysql"},
+			{Name: "zookeeper"},
+		}
+		sorted, err := sortAddonManifestItems(items)
+		Expect(err).Should(Succeed())
+		var names []string
+		for _, item := range sorted {
+			names = append(names, item.Name)
+		}
+		Expect(names).Should(Equal([]string{"zookeeper", "kafka", "mysql"}))
+
+		items[2].DependsOn = []string{"kafka"}
+		_, err = sortAddonManifestItems(items)
+		Expect(err).Should(MatchError(ContainSubstring("circular")))
+
+		_, err = sortAddonManifestItems([]addonManifestItem{{Name: "kafka", DependsOn: []string{"zookeeper"}}})
+		Expect(err).Should(MatchError(ContainSubstring("not listed")))
+	})
+
+	It("build sync plan", func() {
+		disabled := false
+		items, err := sortAddonManifestItems([]addonManifestItem{
+			{Name: "mysql", Values: []string{"image.registry=registry.example.com"}},
+			{Name: "redis", Enabled: &disabled},
+			{Name: "kafka", DependsOn: []string{"zookeeper"}},
+			{Name: "zookeeper"},
+			{Name: "etcd"},
+			{Name: "mongodb", Enabled: &disabled},
+		})
+		Expect(err).Should(Succeed())
+		resolved := map[string]*extensionsv1alpha1.Addon{
+			"mysql":     newAddon("mysql", "1.0.0", true, "image.registry=registry.example.com"),
+			"redis":     newAddon("redis", "1.0.0", true),
+			"kafka":     newAddon("kafka", "1.0.0", true),
+			"zookeeper": newAddon("zookeeper", "1.0.0", true),
+			"etcd":      newAddon("etcd", "1.0.0", true),
+			"mongodb":   newAddon("mongodb", "1.0.0", true),
+		}
+		builtin := newAddon("snapshot-controller", "1.0.0", true)
+		builtin.Annotations = map[string]string{helmReleaseNameAnnotation: "kubeblocks"}
+		installed := map[string]*extensionsv1alpha1.Addon{
+			"mysql":               newAddon("mysql", "1.0.0", true),
+			"redis":               newAddon("redis", "1.0.0", true),
+			"kafka":               newAddon("kafka", "0.9.0", true),
+			"etcd":                newAddon("etcd", "1.0.0", false),
+			"postgresql":          newAddon("postgresql", "1.0.0", true),
+			"snapshot-controller": builtin,
+		}
+
+		plan := buildSyncPlan(items, resolved, installed, false)
+		var steps []string
+		for _, step := range plan {
+			steps = append(steps, string(step.action)+" "+step.name)
+		}
+		Expect(steps).Should(Equal([]string{
+			"disable redis",
+			"upgrade mysql",
+			"install zookeeper",
+			"upgrade kafka",
+			"enable etcd",
+			"install mongodb",
+			"disable mongodb",
+		}))
+
+		installed["mysql"] = newAddon("mysql", "1.0.0", true, "image.registry=registry.example.com")
+		plan = buildSyncPlan(items, resolved, installed, true)
+		Expect(plan[0].action).Should(Equal(syncUninstall))
+		Expect(plan[0].name).Should(Equal("postgresql"))
+		for _, step := range plan {
+			Expect(step.name).ShouldNot(Equal("snapshot-controller"))
+			Expect(step.name).ShouldNot(Equal("mysql"))
+		}
+
+		By("the values removed from the manifest are synced")
+		resolved["mysql"] = newAddon("mysql", "1.0.0", true)
+		plan = buildSyncPlan(items, resolved, installed, false)
+		steps = nil
+		for _, step := range plan {
+			steps = append(steps, string(step.action)+" "+step.name)
+		}
+		Expect(steps).Should(ContainElement("upgrade mysql"))
+
+		out := &bytes.Buffer{}
+		printSyncPlan(out, plan)
+		Expect(out.String()).Should(ContainSubstring("uninstall"))
+		out.Reset()
+		printSyncPlan(out, nil)
+		Expect(out.String()).Should(ContainSubstring("nothing to do"))
+	})
+
+	It("read and write lockfile", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "addons.lock.yaml")
+		lock, err := loadAddonLockfile(file)
+		Expect(err).Should(Succeed())
+		Expect(lock.Addons).Should(BeEmpty())
+
+		lock.Addons = append(lock.Addons, addonLockItem{Name: "mysql", Index: types.DefaultIndexName, Version: "1.0.0", Digest: "sha256:abc"})
+		Expect(writeAddonLockfile(file, lock)).Should(Succeed())
+		lock, err = loadAddonLockfile(file)
+		Expect(err).Should(Succeed())
+		Expect(lock.find("mysql").Digest).Should(Equal("sha256:abc"))
+		Expect(lock.find("redis")).Should(BeNil())
+	})
+})
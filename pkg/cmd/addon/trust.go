@@ -0,0 +1,410 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+const (
+	trustPolicyNone    = "none"
+	trustPolicyWarn    = "warn"
+	trustPolicyEnforce = "enforce"
+
+	// signatureSuffix is the suffix of the detached signature file
+	signatureSuffix = ".sig"
+	// trustedKeySuffix is the suffix of the public key files in the trust store
+	trustedKeySuffix = ".pub"
+)
+
+var addonIndexTrustAddExample = templates.Examples(`
+	# trust the public key to verify the addon index entries and charts
+	kbcli addon index trust add cosign.pub
+
+	# trust the public key with a specified name
+	kbcli addon index trust add ./keys/cosign.pub --name my-team
+
+	# refuse to install or upgrade the unsigned or tampered addons, add the following line to ~/.kbcli/config.yaml
+	ADDON_TRUST_POLICY: enforce
+`)
+
+type trustedKey struct {
+	name string
+	key  crypto.PublicKey
+}
+
+func newIndexTrustCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trust",
+		Short: "Manage the public keys trusted to verify the addon index entries and charts",
+		Long: `Manage the public keys trusted to verify the addon index entries and charts.
+
+The index entries are signed by detached ed25519 or cosign-compatible signatures, the signature of an index
+entry is the file with the '.sig' suffix next to it. The chart of an addon is pinned by the '` + types.AddonChartDigestAnnotationKey + `'
+annotation of the signed index entry unless it is stored in the charts image, the chart in a bundle created by
+'kbcli addon pack' is checked against the pinned digest.
+
+The signatures are verified by 'kbcli addon install' and 'kbcli addon upgrade' according to the ADDON_TRUST_POLICY
+in the kbcli config: 'none' (the default) skips the verification, 'warn' prints a warning for the unsigned or
+tampered addons, and 'enforce' refuses to install or upgrade them.`,
+		Args: cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		newIndexTrustAddCmd(streams),
+		newIndexTrustListCmd(streams),
+		newIndexTrustDeleteCmd(streams),
+	)
+	return cmd
+}
+
+func newIndexTrustAddCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	var name string
+	cmd := &cobra.Command{
+		Use:     "add KEY",
+		Short:   "Trust a PEM encoded ed25519 or ECDSA public key",
+		Example: addonIndexTrustAddExample,
+		Args:    cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			util.CheckErr(addTrustedKey(streams.Out, args[0], name))
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "the name of the trusted key, use the file name of the key by default")
+	return cmd
+}
+
+func newIndexTrustListCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the trusted public keys",
+		Args:  cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			util.CheckErr(listTrustedKeys(streams.Out))
+		},
+	}
+}
+
+func newIndexTrustDeleteCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete NAME",
+		Short: "Delete a trusted public key",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			util.CheckErr(deleteTrustedKey(streams.Out, args[0]))
+		},
+	}
+}
+
+// getTrustDir returns the dir of the trust store, it is created if not exists
+func getTrustDir() (string, error) {
+	home, err := util.GetCliHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, types.AddonTrustDir)
+	if err = os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("error when create addon trust directory: %w", err)
+	}
+	return dir, nil
+}
+
+func addTrustedKey(out io.Writer, file, name string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if _, err = parsePublicKey(data); err != nil {
+		return fmt.Errorf("invalid public key %s: %w", file, err)
+	}
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+	if !IsValidIndexName(name) {
+		return fmt.Errorf("invalid key name %s, use --name to specify a valid one", name)
+	}
+	dir, err := getTrustDir()
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(dir, name+trustedKeySuffix)
+	if _, err = os.Stat(target); err == nil {
+		return fmt.Errorf("trusted key %s already exists", name)
+	}
+	if err = os.WriteFile(target, data, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "public key %s is trusted\n", name)
+	return nil
+}
+
+func listTrustedKeys(out io.Writer) error {
+	keys, err := loadTrustedKeys()
+	if err != nil {
+		return err
+	}
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("NAME", "TYPE", "FINGERPRINT")
+	for _, k := range keys {
+		der, err := x509.MarshalPKIXPublicKey(k.key)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(der)
+		tbl.AddRow(k.name, keyType(k.key), "SHA256:"+hex.EncodeToString(sum[:]))
+	}
+	tbl.Print()
+	return nil
+}
+
+func deleteTrustedKey(out io.Writer, name string) error {
+	dir, err := getTrustDir()
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(filepath.Join(dir, name+trustedKeySuffix)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("trusted key %s does not exist", name)
+		}
+		return err
+	}
+	fmt.Fprintf(out, "trusted key %s has been deleted\n", name)
+	return nil
+}
+
+func loadTrustedKeys() ([]trustedKey, error) {
+	dir, err := getTrustDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []trustedKey
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), trustedKeySuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		key, err := parsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted key %s: %w", e.Name(), err)
+		}
+		keys = append(keys, trustedKey{name: strings.TrimSuffix(e.Name(), trustedKeySuffix), key: key})
+	}
+	return keys, nil
+}
+
+// parsePublicKey parses the PEM encoded PKIX public key, only ed25519 and ECDSA keys are supported
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, only ed25519 and ECDSA keys are supported", key)
+	}
+}
+
+func keyType(key crypto.PublicKey) string {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return "ed25519"
+	case *ecdsa.PublicKey:
+		return "ecdsa-" + k.Curve.Params().Name
+	default:
+		return fmt.Sprintf("%T", key)
+	}
+}
+
+// verifySignature verifies the detached signature of the data with the trusted keys and returns the name
+// of the key which signs it. The signature is base64 encoded as cosign does, or the raw bytes.
+func verifySignature(keys []trustedKey, data, sig []byte) (string, error) {
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig))); err == nil {
+		sig = decoded
+	}
+	digest := sha256.Sum256(data)
+	for _, k := range keys {
+		switch key := k.key.(type) {
+		case ed25519.PublicKey:
+			if ed25519.Verify(key, data, sig) {
+				return k.name, nil
+			}
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(key, digest[:], sig) {
+				return k.name, nil
+			}
+		}
+	}
+	return "", errors.New("the signature is not signed by any trusted key")
+}
+
+// readSignedFile reads the file and its detached signature, the signature is nil if it does not exist.
+func readSignedFile(file string) ([]byte, []byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	sig, err := os.ReadFile(file + signatureSuffix)
+	if os.IsNotExist(err) {
+		return data, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, sig, nil
+}
+
+func getTrustPolicy() (string, error) {
+	policy := strings.ToLower(viper.GetString(types.CfgKeyAddonTrustPolicy))
+	switch policy {
+	case "", trustPolicyNone:
+		return trustPolicyNone, nil
+	case trustPolicyWarn, trustPolicyEnforce:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid %s %q, it should be one of %s, %s and %s", types.CfgKeyAddonTrustPolicy, policy,
+			trustPolicyNone, trustPolicyWarn, trustPolicyEnforce)
+	}
+}
+
+// verifyAddon verifies the signatures of the addon index entry and its chart according to the trust policy
+func (o *installOption) verifyAddon() error {
+	policy, err := getTrustPolicy()
+	if err != nil || policy == trustPolicyNone {
+		return err
+	}
+	keys, err := loadTrustedKeys()
+	if err != nil {
+		return err
+	}
+	if err = o.verifyAddonSignatures(keys); err == nil {
+		return nil
+	}
+	if policy == trustPolicyEnforce {
+		return fmt.Errorf("%w, the addon is refused by the trust policy %q", err, policy)
+	}
+	printer.Warning(o.Out, "%s\n", err.Error())
+	return nil
+}
+
+// verifyAddonSignatures verifies the addon from the index with the chart downloaded from its chart location,
+// or from the bundle with the chart in it
+func (o *installOption) verifyAddonSignatures(keys []trustedKey) error {
+	if o.bundle != nil {
+		if err := verifyAddonEntry(keys, o.addon, o.bundle.entry, o.bundle.entrySig); err != nil {
+			return err
+		}
+		return verifyAddonChart(o.addon, o.bundle.metadata.ChartDigest)
+	}
+	entry, sig, err := readSignedFile(o.addonPath)
+	if err != nil {
+		return err
+	}
+	if err = verifyAddonEntry(keys, o.addon, entry, sig); err != nil {
+		return err
+	}
+	var chartDigest string
+	if o.addon.Spec.Helm != nil && checkAddonChart(o.addon) == nil {
+		if chartDigest, err = downloadAddonChartDigest(o.addon); err != nil {
+			return err
+		}
+	}
+	return verifyAddonChart(o.addon, chartDigest)
+}
+
+// verifyAddonEntry verifies the index entry of the addon with its signature
+func verifyAddonEntry(keys []trustedKey, addon *extensionsv1alpha1.Addon, entry, sig []byte) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("no trusted key found to verify addon %s, run 'kbcli addon index trust add KEY' to add one", addon.Name)
+	}
+	if sig == nil {
+		return fmt.Errorf("the index entry of addon %s is not signed", addon.Name)
+	}
+	if _, err := verifySignature(keys, entry, sig); err != nil {
+		return fmt.Errorf("failed to verify the index entry of addon %s: %w", addon.Name, err)
+	}
+	return nil
+}
+
+// verifyAddonChart compares the digest of the chart to install with the digest pinned in the signed index entry.
+// The chart stored in the charts image can not be downloaded, so it can not be verified.
+func verifyAddonChart(addon *extensionsv1alpha1.Addon, chartDigest string) error {
+	if addon.Spec.Helm == nil {
+		return nil
+	}
+	if err := checkAddonChart(addon); err != nil {
+		return fmt.Errorf("%w, so it can not be verified", err)
+	}
+	digest := addon.Annotations[types.AddonChartDigestAnnotationKey]
+	if digest == "" {
+		return fmt.Errorf("the index entry of addon %s does not pin the chart digest by the annotation %s", addon.Name, types.AddonChartDigestAnnotationKey)
+	}
+	if chartDigest != digest {
+		return fmt.Errorf("the chart digest of addon %s is %s, which does not match %s in the index entry", addon.Name, chartDigest, digest)
+	}
+	return nil
+}
+
+// downloadAddonChartDigest downloads the chart of the addon to a temporary directory and returns its digest
+func downloadAddonChartDigest(addon *extensionsv1alpha1.Addon) (string, error) {
+	dir, err := os.MkdirTemp("", "kbcli-addon-verify-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	chartPath, err := downloadAddonChart(addon, dir)
+	if err != nil {
+		return "", err
+	}
+	return fileDigest(chartPath)
+}
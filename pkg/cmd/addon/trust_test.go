@@ -0,0 +1,172 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("addon trust test", func() {
+	var (
+		dir string
+		out *bytes.Buffer
+	)
+
+	writePublicKey := func(name string, key interface{}) string {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		Expect(err).Should(Succeed())
+		file := filepath.Join(dir, name)
+		Expect(os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644)).Should(Succeed())
+		return file
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		out = &bytes.Buffer{}
+		GinkgoT().Setenv(types.CliHomeEnv, filepath.Join(dir, "home"))
+	})
+
+	It("manage trusted keys", func() {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).Should(Succeed())
+		file := writePublicKey("publisher.pub", pub)
+		Expect(addTrustedKey(out, file, "")).Should(Succeed())
+		Expect(addTrustedKey(out, file, "")).Should(MatchError(ContainSubstring("already exists")))
+		Expect(addTrustedKey(out, filepath.Join(dir, "not-exist.pub"), "")).ShouldNot(Succeed())
+
+		invalid := filepath.Join(dir, "invalid.pub")
+		Expect(os.WriteFile(invalid, []byte("not a key"), 0644)).Should(Succeed())
+		Expect(addTrustedKey(out, invalid, "")).Should(MatchError(ContainSubstring("invalid public key")))
+
+		keys, err := loadTrustedKeys()
+		Expect(err).Should(Succeed())
+		Expect(keys).Should(HaveLen(1))
+		Expect(keys[0].name).Should(Equal("publisher"))
+
+		out.Reset()
+		Expect(listTrustedKeys(out)).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("ed25519"))
+
+		Expect(deleteTrustedKey(out, "publisher")).Should(Succeed())
+		Expect(deleteTrustedKey(out, "publisher")).Should(MatchError(ContainSubstring("does not exist")))
+	})
+
+	It("verify signatures", func() {
+		edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).Should(Succeed())
+		ecPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).Should(Succeed())
+		Expect(addTrustedKey(out, writePublicKey("ed.pub", edPub), "")).Should(Succeed())
+		Expect(addTrustedKey(out, writePublicKey("ec.pub", &ecPriv.PublicKey), "")).Should(Succeed())
+		keys, err := loadTrustedKeys()
+		Expect(err).Should(Succeed())
+
+		data := []byte("apiVersion: extensions.kubeblocks.io/v1alpha1\nkind: Addon\n")
+		name, err := verifySignature(keys, data, []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(edPriv, data))))
+		Expect(err).Should(Succeed())
+		Expect(name).Should(Equal("ed"))
+
+		digest := sha256.Sum256(data)
+		ecSig, err := ecdsa.SignASN1(rand.Reader, ecPriv, digest[:])
+		Expect(err).Should(Succeed())
+		name, err = verifySignature(keys, data, ecSig)
+		Expect(err).Should(Succeed())
+		Expect(name).Should(Equal("ec"))
+
+		_, err = verifySignature(keys, append(data, '#'), ecSig)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("verify addon index entry", func() {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).Should(Succeed())
+		addon := &extensionsv1alpha1.Addon{}
+		addon.Name = "mysql"
+		addon.Spec.Helm = &extensionsv1alpha1.HelmTypeInstallSpec{
+			ChartLocationURL: "file:///mysql-1.0.0.tgz",
+			ChartsImage:      "apecloud/kubeblocks-charts:1.0.0",
+		}
+		data := []byte("kind: Addon\nmetadata:\n  name: mysql\n")
+		sig := ed25519.Sign(priv, data)
+
+		Expect(verifyAddonEntry(nil, addon, data, sig)).Should(MatchError(ContainSubstring("no trusted key")))
+		Expect(addTrustedKey(out, writePublicKey("publisher.pub", pub), "")).Should(Succeed())
+		keys, err := loadTrustedKeys()
+		Expect(err).Should(Succeed())
+		Expect(verifyAddonEntry(keys, addon, data, nil)).Should(MatchError(ContainSubstring("is not signed")))
+		Expect(verifyAddonEntry(keys, addon, data, sig)).Should(Succeed())
+		tampered := []byte(string(data) + "  namespace: tampered\n")
+		Expect(verifyAddonEntry(keys, addon, tampered, sig)).Should(MatchError(ContainSubstring("not signed by any trusted key")))
+
+		By("the chart stored in the charts image can not be verified")
+		Expect(verifyAddonChart(addon, "")).Should(MatchError(ContainSubstring("can not be verified")))
+
+		By("the chart downloaded from the chart location must match the digest pinned by the entry")
+		addon.Spec.Helm.ChartLocationURL = "https://example.com/mysql-1.0.0.tgz"
+		Expect(verifyAddonChart(addon, "sha256:abc")).Should(MatchError(ContainSubstring("does not pin the chart digest")))
+		addon.Annotations = map[string]string{types.AddonChartDigestAnnotationKey: "sha256:abc"}
+		Expect(verifyAddonChart(addon, "sha256:abc")).Should(Succeed())
+		Expect(verifyAddonChart(addon, "sha256:def")).Should(MatchError(ContainSubstring("does not match")))
+
+		By("the addon not installed by a helm chart has no chart to verify")
+		addon.Spec.Helm = nil
+		Expect(verifyAddonChart(addon, "")).Should(Succeed())
+
+		By("read the signed index entry")
+		entry := filepath.Join(dir, "mysql.yaml")
+		Expect(os.WriteFile(entry, data, 0644)).Should(Succeed())
+		_, entrySig, err := readSignedFile(entry)
+		Expect(err).Should(Succeed())
+		Expect(entrySig).Should(BeNil())
+		Expect(os.WriteFile(entry+signatureSuffix, sig, 0644)).Should(Succeed())
+		_, entrySig, err = readSignedFile(entry)
+		Expect(err).Should(Succeed())
+		Expect(entrySig).Should(Equal(sig))
+	})
+
+	It("trust policy", func() {
+		defer viper.Set(types.CfgKeyAddonTrustPolicy, "")
+		viper.Set(types.CfgKeyAddonTrustPolicy, "")
+		Expect(getTrustPolicy()).Should(Equal(trustPolicyNone))
+		viper.Set(types.CfgKeyAddonTrustPolicy, "Enforce")
+		Expect(getTrustPolicy()).Should(Equal(trustPolicyEnforce))
+		viper.Set(types.CfgKeyAddonTrustPolicy, "strict")
+		_, err := getTrustPolicy()
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -60,6 +60,12 @@ var addonUpgradeExample = templates.Examples(`
 
 	# non-inplace upgrade an addon with a specified addon name
 	kbcli addon upgrade apecloud-mysql --inplace=false --name apecloud-mysql-0.7.0
+
+	# preview the changes of upgrading an addon, including the field-level changes of the objects
+	kbcli addon upgrade apecloud-mysql --version 0.7.0 --dry-run --diff
+
+	# upgrade an addon and pull the images from another registry
+	kbcli addon upgrade apecloud-mysql --version 0.7.0 --set image.registry=registry.example.com
 `)
 
 // upgradeOption storage the info to upgrade an addon
@@ -74,6 +80,10 @@ type upgradeOption struct {
 	// rename is the new version addon name need to set by user when inplace is false, it also will be used as resourceNamePrefix of an addon with multiple version.
 	// If it's not be specified by user, use `addon-version` by default
 	rename string
+	// preview the upgrade without applying it
+	dryRun bool
+	// show the field-level changes of the objects when previewing the upgrade
+	diff bool
 }
 
 func newUpgradeOption(f cmdutil.Factory, streams genericiooptions.IOStreams) *upgradeOption {
@@ -97,6 +107,10 @@ func newUpgradeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 			o.name = args[0]
 			util.CheckErr(o.Complete())
 			util.CheckErr(o.Validate())
+			if o.dryRun {
+				util.CheckErr(o.preview())
+				return
+			}
 			if strings.HasPrefix(o.currentVersion, "0.9") {
 				util.CheckErr(o.process09ClusterDefAndComponentVersions())
 			}
@@ -109,12 +123,21 @@ func newUpgradeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 	cmd.Flags().BoolVar(&o.inplace, "inplace", true, "when inplace is false, it will retain the existing addon and reinstall the new version of the addon, otherwise the upgrade will be in-place. The default is true.")
 	cmd.Flags().StringVar(&o.rename, "name", "", "name is the new version addon name need to set by user when inplace is false, it also will be used as resourceNamePrefix of an addon with multiple version.")
 	cmd.Flags().StringVar(&o.clusterChartVersion, "cluster-chart-version", "", "specify the cluster chart version, use the same version as the addon by default")
-	cmd.Flags().StringVar(&o.clusterChartRepo, "cluster-chart-repo", types.ClusterChartsRepoURL, "specify the repo of cluster chart, use the url of 'kubeblocks-addons' by default")
+	cmd.Flags().StringVar(&o.clusterChartRepo, "cluster-chart-repo", types.ClusterChartsRepoURL, "specify the helm repo or OCI registry (oci://) of cluster chart, use the url of 'kubeblocks-addons' by default")
 	cmd.Flags().StringVar(&o.path, "path", "", "specify the local path contains addon CRs and needs to be specified when operating offline")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "preview the changes of the objects and the affected clusters without upgrading the addon")
+	cmd.Flags().BoolVar(&o.diff, "diff", false, "show the field-level changes of the objects, it only works with --dry-run")
+	cmd.Flags().StringArrayVar(&o.setValues, "set", nil, "set helm values of the addon on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	return cmd
 }
 
 func (o *upgradeOption) Complete() error {
+	if o.diff && !o.dryRun {
+		return fmt.Errorf("--diff can only be used with --dry-run")
+	}
+	if o.dryRun && !o.inplace {
+		return fmt.Errorf("--dry-run only supports the in-place upgrade")
+	}
 	if err := o.installOption.Complete(); err != nil {
 		return err
 	}
@@ -152,10 +175,7 @@ func (o *upgradeOption) Validate() error {
 
 func (o *upgradeOption) Run(f cmdutil.Factory, streams genericiooptions.IOStreams) error {
 	if !o.inplace {
-		if o.addon.Spec.Helm.InstallValues.SetValues != nil {
-			o.addon.Spec.Helm.InstallValues.SetValues = append(o.addon.Spec.Helm.InstallValues.SetValues, fmt.Sprintf("%s=%s", types.AddonResourceNamePrefix, o.rename))
-		}
-		o.addon.Spec.Helm.InstallValues.SetValues = []string{fmt.Sprintf("%s=%s", types.AddonResourceNamePrefix, o.rename)}
+		o.addon.Spec.Helm.InstallValues.SetValues = append(o.addon.Spec.Helm.InstallValues.SetValues, fmt.Sprintf("%s=%s", types.AddonResourceNamePrefix, o.rename))
 		o.addon.Name = o.rename
 		err := o.installOption.Run(f, streams)
 		if err == nil {
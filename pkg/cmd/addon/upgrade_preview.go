@@ -0,0 +1,269 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/helm"
+)
+
+// highlightedKinds are the kinds whose changes may affect the running clusters
+var highlightedKinds = map[string]bool{
+	types.KindComponentDef:         true,
+	types.KindParametersDef:        true,
+	types.KindBackupPolicyTemplate: true,
+}
+
+// objectChange is the change of an object between the installed release and the upgrade target.
+type objectChange struct {
+	name string
+	kind string
+	mode helm.Mode
+}
+
+// preview renders the installed chart and the target chart with the current user values, and shows the
+// changes without upgrading the addon. Both charts are rendered in the same way as the release in the
+// KubeBlocks namespace, so the changes only come from the charts and the install values of the addon.
+func (o *upgradeOption) preview() error {
+	namespace, err := util.GetKubeBlocksNamespace(o.Client, "")
+	if err != nil {
+		return err
+	}
+	releaseName := util.BuildAddonReleaseName(o.name)
+	installed, err := helm.GetHelmRelease(helm.NewConfig(namespace, "", "", klog.V(1).Enabled()), releaseName)
+	if err != nil {
+		return fmt.Errorf("failed to get the release %s of addon %s: %w", releaseName, o.name, err)
+	}
+	if installed.Chart == nil {
+		return fmt.Errorf("the chart of release %s is not found", releaseName)
+	}
+	if err = checkAddonChart(o.addon); err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "kbcli-addon-upgrade-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	chartPath, err := downloadAddonChart(o.addon, dir)
+	if err != nil {
+		return err
+	}
+	targetChart, err := loader.Load(chartPath)
+	if err != nil {
+		return err
+	}
+
+	kubeVersion, err := util.GetK8sVersion(o.Client.Discovery())
+	if err != nil || kubeVersion == "" {
+		kubeVersion = helm.FakeKubeVersion
+	}
+	render := func(c *chart.Chart, setValues []string) (*release.Release, error) {
+		values, err := copyValues(installed.Config)
+		if err != nil {
+			return nil, err
+		}
+		manifests, err := renderChart(c, namespace, releaseName, kubeVersion, values, setValues)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render chart %s-%s: %w", c.Name(), c.Metadata.Version, err)
+		}
+		return &release.Release{Name: releaseName, Namespace: namespace, Manifest: joinManifests(manifests)}, nil
+	}
+	// the install values of the installed addon are already in the user values of the release
+	current, err := render(installed.Chart, nil)
+	if err != nil {
+		return err
+	}
+	target, err := render(targetChart, o.addon.Spec.Helm.InstallValues.SetValues)
+	if err != nil {
+		return err
+	}
+
+	changes, err := diffReleaseObjects(current.Manifest, target.Manifest)
+	if err != nil {
+		return err
+	}
+	clusters, err := cluster.GetCompDefClusters(o.Dynamic)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "Preview the upgrade of addon %s from %s to %s, no changes will be applied.\n\n", o.name, o.currentVersion, o.version)
+	printObjectChanges(o.Out, changes)
+	if err = helm.OutputDiff(current, target, o.currentVersion, o.version, o.Out, o.diff); err != nil {
+		return err
+	}
+	printReplacedCompDefClusters(o.Out, changes, clusters)
+	return nil
+}
+
+// copyValues deep copies the helm values, the values are modified in place when rendering a chart.
+func copyValues(values map[string]interface{}) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func joinManifests(manifests map[string]string) string {
+	keys := make([]string, 0, len(manifests))
+	for k := range manifests {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString("---\n")
+		b.WriteString(strings.TrimSpace(manifests[k]))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// diffReleaseObjects compares the objects of two manifests, the highlighted kinds are sorted first.
+// The objects are compared after parsing, so the differences of the format are ignored.
+func diffReleaseObjects(manifestA, manifestB string) ([]objectChange, error) {
+	parse := func(manifest string) (map[string]*helm.MappingResult, error) {
+		res := map[string]*helm.MappingResult{}
+		for _, m := range releaseutil.SplitManifests(manifest) {
+			r, err := helm.ParseContent(m)
+			if err != nil {
+				return nil, err
+			}
+			if r != nil {
+				res[r.Name] = r
+			}
+		}
+		return res, nil
+	}
+	objsA, err := parse(manifestA)
+	if err != nil {
+		return nil, err
+	}
+	objsB, err := parse(manifestB)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []objectChange
+	newChange := func(r *helm.MappingResult, mode helm.Mode) objectChange {
+		return objectChange{name: strings.Split(r.Name, ",")[0], kind: r.Kind, mode: mode}
+	}
+	for key, a := range objsA {
+		b, ok := objsB[key]
+		switch {
+		case !ok:
+			changes = append(changes, newChange(a, helm.Removed))
+		case !equalObjects(a.Content, b.Content):
+			changes = append(changes, newChange(a, helm.Modified))
+		}
+	}
+	for key, b := range objsB {
+		if _, ok := objsA[key]; !ok {
+			changes = append(changes, newChange(b, helm.Added))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		hi, hj := highlightedKinds[changes[i].kind], highlightedKinds[changes[j].kind]
+		if hi != hj {
+			return hi
+		}
+		if changes[i].kind != changes[j].kind {
+			return changes[i].kind < changes[j].kind
+		}
+		return changes[i].name < changes[j].name
+	})
+	return changes, nil
+}
+
+func equalObjects(a, b string) bool {
+	var objA, objB interface{}
+	if yaml.Unmarshal([]byte(a), &objA) != nil || yaml.Unmarshal([]byte(b), &objB) != nil {
+		return a == b
+	}
+	return reflect.DeepEqual(objA, objB)
+}
+
+func printObjectChanges(out io.Writer, changes []objectChange) {
+	if len(changes) == 0 {
+		fmt.Fprintf(out, "No objects will be changed.\n\n")
+		return
+	}
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("KIND", "NAME", "CHANGE")
+	for _, c := range changes {
+		mode := string(c.mode)
+		switch c.mode {
+		case helm.Added:
+			mode = printer.BoldGreen(mode)
+		case helm.Removed:
+			mode = printer.BoldRed(mode)
+		}
+		kind := c.kind
+		if highlightedKinds[kind] {
+			kind = printer.BoldYellow(kind)
+		}
+		tbl.AddRow(kind, c.name, mode)
+	}
+	tbl.Print()
+	printer.PrintBlankLine(out)
+}
+
+// printReplacedCompDefClusters prints the clusters whose ComponentDefinitions will be removed or modified by the upgrade.
+func printReplacedCompDefClusters(out io.Writer, changes []objectChange, clusters map[string][]string) {
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("COMPONENT-DEFINITION", "CHANGE", "CLUSTERS")
+	for _, c := range changes {
+		if c.kind != types.KindComponentDef || c.mode == helm.Added || len(clusters[c.name]) == 0 {
+			continue
+		}
+		tbl.AddRow(c.name, string(c.mode), strings.Join(clusters[c.name], "\n"))
+	}
+	if tbl.Tbl.Length() == 0 {
+		fmt.Fprintf(out, "No running clusters use the replaced ComponentDefinitions.\n")
+		return
+	}
+	fmt.Fprintf(out, "%s\n", printer.BoldYellow("Warning: the ComponentDefinitions of the following clusters will be replaced:"))
+	tbl.Print()
+}
@@ -0,0 +1,129 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package addon
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util/helm"
+)
+
+var _ = Describe("addon upgrade preview test", func() {
+	const (
+		currentManifest = `---
+apiVersion: apps.kubeblocks.io/v1
+kind: ComponentDefinition
+metadata:
+  name: mysql-8.0-0.9.0
+spec:
+  serviceVersion: 8.0.30
+---
+apiVersion: dataprotection.kubeblocks.io/v1alpha1
+kind: BackupPolicyTemplate
+metadata:
+  name: mysql-backup-policy-template
+spec:
+  backupMethods:
+  - name: xtrabackup
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: mysql-scripts
+data:
+  setup.sh: echo 0.9.0
+`
+		targetManifest = `---
+apiVersion: apps.kubeblocks.io/v1
+kind: ComponentDefinition
+metadata:
+  name: mysql-8.0-1.0.0
+spec:
+  serviceVersion: 8.0.30
+---
+apiVersion: dataprotection.kubeblocks.io/v1alpha1
+kind: BackupPolicyTemplate
+metadata:
+  name: mysql-backup-policy-template
+spec:
+  backupMethods:
+  - name: xtrabackup
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: mysql-scripts
+data:
+  setup.sh: echo 1.0.0
+`
+	)
+
+	It("validate flags", func() {
+		streams, _, _, _ := genericiooptions.NewTestIOStreams()
+		o := newUpgradeOption(nil, streams)
+		o.diff = true
+		Expect(o.Complete()).Should(MatchError(ContainSubstring("--dry-run")))
+		o.dryRun = true
+		o.inplace = false
+		Expect(o.Complete()).Should(MatchError(ContainSubstring("in-place")))
+	})
+
+	It("diff release objects", func() {
+		changes, err := diffReleaseObjects(currentManifest, targetManifest)
+		Expect(err).Should(Succeed())
+		Expect(changes).Should(Equal([]objectChange{
+			{name: "mysql-8.0-0.9.0", kind: types.KindComponentDef, mode: helm.Removed},
+			{name: "mysql-8.0-1.0.0", kind: types.KindComponentDef, mode: helm.Added},
+			{name: "mysql-scripts", kind: types.KindConfigMap, mode: helm.Modified},
+		}))
+
+		changes, err = diffReleaseObjects(currentManifest, currentManifest)
+		Expect(err).Should(Succeed())
+		Expect(changes).Should(BeEmpty())
+
+		By("the differences of the format are ignored")
+		Expect(equalObjects("a: 1\nb: [x]\n", "# comment\nb:\n- x\na: 1\n")).Should(BeTrue())
+		Expect(equalObjects("a: 1\n", "a: 2\n")).Should(BeFalse())
+	})
+
+	It("print changes and affected clusters", func() {
+		changes, err := diffReleaseObjects(currentManifest, targetManifest)
+		Expect(err).Should(Succeed())
+		out := &bytes.Buffer{}
+		printObjectChanges(out, changes)
+		Expect(out.String()).Should(ContainSubstring("mysql-scripts"))
+
+		out.Reset()
+		printReplacedCompDefClusters(out, changes, map[string][]string{
+			"mysql-8.0-0.9.0": {"default/mycluster(mysql)"},
+		})
+		Expect(out.String()).Should(ContainSubstring("default/mycluster(mysql)"))
+		Expect(out.String()).ShouldNot(ContainSubstring("mysql-8.0-1.0.0"))
+
+		out.Reset()
+		printReplacedCompDefClusters(out, changes, nil)
+		Expect(out.String()).Should(ContainSubstring("No running clusters"))
+	})
+})
@@ -86,14 +86,15 @@ func init() {
 func NewDefaultCliCmd() *cobra.Command {
 	cmd := NewCliCmd()
 
-	pluginHandler := kccmd.NewDefaultPluginHandler(plugin.ValidPluginFilenamePrefixes)
-
 	if len(os.Args) > 1 {
 		cmdPathPieces := os.Args[1:]
+		// the plugins get the runtime context resolved from the command line
+		pluginHandler := plugin.NewContextPluginHandler(kccmd.NewDefaultPluginHandler(plugin.ValidPluginFilenamePrefixes), cmdPathPieces)
 
 		// only look for suitable extension executables if
 		// the specified command does not exist
-		if _, _, err := cmd.Find(cmdPathPieces); err != nil {
+		c, args, err := cmd.Find(cmdPathPieces)
+		if err != nil {
 			var cmdName string
 			for _, arg := range cmdPathPieces {
 				if !strings.HasPrefix(arg, "-") {
@@ -111,6 +112,12 @@ func NewDefaultCliCmd() *cobra.Command {
 					os.Exit(1)
 				}
 			}
+		} else if c.Name() == "cluster" && c.Parent() == cmd && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			// 'kbcli cluster <plugin> NAME' runs the cluster plugin 'kbcli-cluster-<plugin>'
+			if err := kccmd.HandlePluginCommand(pluginHandler, cmdPathPieces, false); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
 		}
 	}
 
@@ -221,6 +228,7 @@ func initConfig() {
 	viper.SetDefault(types.CfgKeyClusterDefaultMemory, "1Gi")
 
 	viper.SetDefault(types.CfgKeyHelmRepoURL, "")
+	viper.SetDefault(types.CfgKeyAddonTrustPolicy, "none")
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
 		klog.V(2).Infof("Using config file: %s", viper.ConfigFileUsed())
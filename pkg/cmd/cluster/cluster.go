@@ -47,6 +47,9 @@ func NewClusterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 				NewLabelCmd(f, streams),
 				NewDeleteCmd(f, streams),
 				newRegisterCmd(f, streams),
+				newUnregisterCmd(streams),
+				newListTypesCmd(streams),
+				NewFleetCmd(f, streams),
 			},
 		},
 		{
@@ -58,6 +61,7 @@ func NewClusterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 				NewRestartCmd(f, streams),
 				NewRebuildInstanceCmd(f, streams),
 				NewUpgradeCmd(f, streams),
+				NewUpgradePlanCmd(f, streams),
 				NewVolumeExpansionCmd(f, streams),
 				NewVerticalScalingCmd(f, streams),
 				NewScaleOutCmd(f, streams),
@@ -100,6 +104,7 @@ func NewClusterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 			Commands: []*cobra.Command{
 				NewLogsCmd(f, streams),
 				NewListLogsCmd(f, streams),
+				NewTopologyCmd(f, streams),
 			},
 		},
 		{
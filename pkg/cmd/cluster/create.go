@@ -24,6 +24,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -44,11 +45,20 @@ var clusterCreateExample = templates.Examples(`
 
 	# Edit cluster yaml before creation.
 	kbcli cluster create mycluster --edit
+
+	# Create a cluster interactively, the cluster type is prompted if not specified
+	kbcli cluster create --interactive postgresql
+
+	# Create a cluster with the values file saved by the interactive mode
+	kbcli cluster create postgresql my-cluster -f values.yaml
 `)
 
 type CreateOptions struct {
 	Cmd *cobra.Command `json:"-"`
 
+	// Interactive prompts the cluster type and the values
+	Interactive bool `json:"-"`
+
 	action.CreateOptions `json:"-"`
 }
 
@@ -59,6 +69,10 @@ func NewCreateCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.
 		Short:   "Create a cluster.",
 		Example: clusterCreateExample,
 		Run: func(cmd *cobra.Command, args []string) {
+			if o.Interactive {
+				cmdutil.CheckErr(o.runInteractive(cmd))
+				return
+			}
 			if len(args) == 0 {
 				fmt.Fprintf(o.Out, "A ClusterType shoule be specified to ")
 				_ = cmd.Help()
@@ -68,6 +82,8 @@ func NewCreateCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.
 		},
 	}
 
+	cmd.Flags().BoolVar(&o.Interactive, "interactive", false, "Prompt the cluster type and the cluster values")
+
 	// add all subcommands for supported cluster type
 	cmd.AddCommand(buildCreateSubCmds(&o.CreateOptions)...)
 
@@ -85,6 +101,37 @@ func NewCreateOptions(f cmdutil.Factory, streams genericiooptions.IOStreams) *Cr
 	return o
 }
 
+// runInteractive prompts the cluster type and runs the interactive sub command of the type
+func (o *CreateOptions) runInteractive(cmd *cobra.Command) error {
+	var clusterTypes []string
+	for _, c := range cmd.Commands() {
+		clusterTypes = append(clusterTypes, c.Name())
+	}
+	if len(clusterTypes) == 0 {
+		return fmt.Errorf("no cluster type is registered")
+	}
+	w := newCreateWizard(o.In, o.Out)
+	t, err := w.ask(fmt.Sprintf("Cluster type [%s]:", strings.Join(clusterTypes, ", ")), "", func(s string) error {
+		if !slices.Contains(clusterTypes, s) {
+			return fmt.Errorf("unsupported cluster type %s", s)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sub, _, err := cmd.Find([]string{t})
+	if err != nil {
+		return err
+	}
+	if err = sub.Flags().Set("interactive", "true"); err != nil {
+		return err
+	}
+	sub.PreRun(sub, nil)
+	sub.Run(sub, nil)
+	return nil
+}
+
 // MultipleSourceComponents gets component data from multiple source, such as stdin, URI and local file
 func MultipleSourceComponents(fileName string, in io.Reader) ([]byte, error) {
 	var data io.Reader
@@ -0,0 +1,240 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+// mergeValueSources merges the values from the existing cluster, the profile and the values file in order,
+// the values of the flags explicitly set win over all of them.
+func (o *CreateSubCmdsOptions) mergeValueSources(cmd *cobra.Command) error {
+	if o.FromCluster == "" && o.Profile == "" && o.ValuesFile == "" {
+		return nil
+	}
+	if o.FromCluster != "" {
+		values, err := o.getValuesFromCluster(cmd)
+		if err != nil {
+			return err
+		}
+		o.Values = mergeValues(o.Values, values)
+	}
+	if o.Profile != "" {
+		values, err := cluster.LoadProfile(o.ChartInfo.Chart, o.ClusterType, o.Profile)
+		if err != nil {
+			return err
+		}
+		o.Values = mergeValues(o.Values, values)
+	}
+	if o.ValuesFile != "" {
+		values, err := loadValuesFile(o.ValuesFile, o.In)
+		if err != nil {
+			return err
+		}
+		o.Values = mergeValues(o.Values, values)
+	}
+	o.Values = mergeValues(o.Values, getChangedValuesFromFlags(cmd.LocalNonPersistentFlags()))
+	return nil
+}
+
+// getValuesFromCluster derives the values from the spec of the existing cluster, the values not defined
+// in the chart schema are dropped. The scheduling policy and the backup settings are copied to the options
+// unless they are specified by the flags.
+func (o *CreateSubCmdsOptions) getValuesFromCluster(cmd *cobra.Command) (map[string]interface{}, error) {
+	obj, err := o.Dynamic.Resource(types.ClusterGVR()).Namespace(o.Namespace).Get(context.TODO(), o.FromCluster, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", o.FromCluster, err)
+	}
+
+	values, comp := buildValuesFromCluster(obj)
+	if backup, ok, _ := unstructured.NestedMap(obj.Object, "spec", "backup"); ok {
+		o.backup = backup
+	}
+	if comp != nil {
+		if err = o.setSchedulingPolicyFromComponent(cmd, comp); err != nil {
+			return nil, err
+		}
+	}
+	return filterValuesBySchema(o.Out, o.ChartInfo, values), nil
+}
+
+// buildValuesFromCluster builds the common values of the cluster charts from the cluster spec,
+// the resources and storage are read from the first component or the sharding template.
+func buildValuesFromCluster(obj *unstructured.Unstructured) (map[string]interface{}, map[string]interface{}) {
+	values := map[string]interface{}{}
+	if policy, ok, _ := unstructured.NestedString(obj.Object, "spec", "terminationPolicy"); ok {
+		values["terminationPolicy"] = policy
+	}
+	if topology, ok, _ := unstructured.NestedString(obj.Object, "spec", "topology"); ok {
+		values["topology"] = topology
+		values["mode"] = topology
+	}
+
+	var comp map[string]interface{}
+	if comps, ok, _ := unstructured.NestedSlice(obj.Object, "spec", "componentSpecs"); ok && len(comps) > 0 {
+		comp, _ = comps[0].(map[string]interface{})
+	} else if shardings, ok, _ := unstructured.NestedSlice(obj.Object, "spec", "shardings"); ok && len(shardings) > 0 {
+		sharding, _ := shardings[0].(map[string]interface{})
+		if shards, ok, _ := unstructured.NestedInt64(sharding, "shards"); ok {
+			values["shards"] = shards
+		}
+		comp, _, _ = unstructured.NestedMap(sharding, "template")
+	}
+	if comp == nil {
+		return values, nil
+	}
+
+	if version, ok, _ := unstructured.NestedString(comp, "serviceVersion"); ok {
+		values["version"] = version
+	}
+	if replicas, ok, _ := unstructured.NestedInt64(comp, "replicas"); ok {
+		values["replicas"] = replicas
+	}
+	if cpu, ok := quantityValue(comp, "resources", "limits", "cpu"); ok {
+		values["cpu"] = cpu.AsApproximateFloat64()
+	}
+	if memory, ok := quantityValue(comp, "resources", "limits", "memory"); ok {
+		values["memory"] = memory.AsApproximateFloat64() / (1 << 30)
+	}
+	if vcts, ok, _ := unstructured.NestedSlice(comp, "volumeClaimTemplates"); ok && len(vcts) > 0 {
+		vct, _ := vcts[0].(map[string]interface{})
+		if storage, ok := quantityValue(vct, "spec", "resources", "requests", "storage"); ok {
+			values["storage"] = storage.AsApproximateFloat64() / (1 << 30)
+		}
+		if storageClass, ok, _ := unstructured.NestedString(vct, "spec", "storageClassName"); ok {
+			values["storageClassName"] = storageClass
+		}
+	}
+	return values, comp
+}
+
+func quantityValue(obj map[string]interface{}, fields ...string) (resource.Quantity, bool) {
+	str, ok, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	if !ok {
+		return resource.Quantity{}, false
+	}
+	q, err := resource.ParseQuantity(fmt.Sprintf("%v", str))
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+	return q, true
+}
+
+// filterValuesBySchema drops the values which are not defined or invalid in the chart schema,
+// a warning is printed for each invalid value so that the user can set it by the flags.
+func filterValuesBySchema(out io.Writer, c *cluster.ChartInfo, values map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	keys := maps.Keys(values)
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := values[k]
+		var invalidErr error
+		for _, s := range []*spec.Schema{c.Schema, c.SubSchema} {
+			if s == nil {
+				continue
+			}
+			prop, ok := s.Properties[k]
+			if !ok {
+				continue
+			}
+			if err := cluster.ValidateValue(&prop, v); err != nil {
+				invalidErr = err
+				continue
+			}
+			res[k] = v
+		}
+		if _, ok := res[k]; !ok && invalidErr != nil {
+			printer.Warning(out, "skip the value %s=%v of the existing cluster: %v\n", k, v, invalidErr)
+		}
+	}
+	return res
+}
+
+// setSchedulingPolicyFromComponent copies the tolerations, node labels and pod anti-affinity of the component
+// to the options if they are not specified by the flags.
+func (o *CreateSubCmdsOptions) setSchedulingPolicyFromComponent(cmd *cobra.Command, comp map[string]interface{}) error {
+	policy, ok, _ := unstructured.NestedMap(comp, "schedulingPolicy")
+	if !ok {
+		return nil
+	}
+	changed := func(name string) bool {
+		f := cmd.Flags().Lookup(name)
+		return f != nil && f.Changed
+	}
+	if nodeLabels, ok, _ := unstructured.NestedStringMap(policy, "nodeSelector"); ok && !changed("node-labels") {
+		o.NodeLabels = nodeLabels
+	}
+	if tolerations, ok, _ := unstructured.NestedSlice(policy, "tolerations"); ok && !changed("tolerations") {
+		o.Tolerations = nil
+		for _, t := range tolerations {
+			m, _ := t.(map[string]interface{})
+			toleration := corev1.Toleration{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &toleration); err != nil {
+				return err
+			}
+			o.Tolerations = append(o.Tolerations, toleration)
+		}
+	}
+	antiAffinity, ok, _ := unstructured.NestedMap(policy, "affinity", "podAntiAffinity")
+	if !ok {
+		return nil
+	}
+	terms, required, _ := unstructured.NestedSlice(antiAffinity, "requiredDuringSchedulingIgnoredDuringExecution")
+	if !changed("pod-anti-affinity") {
+		o.PodAntiAffinity = "Preferred"
+		if required {
+			o.PodAntiAffinity = "Required"
+		}
+	}
+	if changed("topology-keys") {
+		return nil
+	}
+	if !required {
+		preferred, _, _ := unstructured.NestedSlice(antiAffinity, "preferredDuringSchedulingIgnoredDuringExecution")
+		for _, p := range preferred {
+			if term, ok, _ := unstructured.NestedMap(p.(map[string]interface{}), "podAffinityTerm"); ok {
+				terms = append(terms, term)
+			}
+		}
+	}
+	o.TopologyKeys = nil
+	for _, term := range terms {
+		if key, ok, _ := unstructured.NestedString(term.(map[string]interface{}), "topologyKey"); ok && key != "" {
+			o.TopologyKeys = append(o.TopologyKeys, key)
+		}
+	}
+	return nil
+}
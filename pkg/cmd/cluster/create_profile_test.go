@@ -0,0 +1,129 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+)
+
+var _ = Describe("create cluster from profile and existing cluster", func() {
+	const clusterYAML = `
+apiVersion: apps.kubeblocks.io/v1
+kind: Cluster
+metadata:
+  name: mycluster
+spec:
+  terminationPolicy: WipeOut
+  topology: replication
+  backup:
+    enabled: true
+    method: xtrabackup
+  componentSpecs:
+  - name: mysql
+    serviceVersion: 8.0.33
+    replicas: 2
+    resources:
+      limits:
+        cpu: 500m
+        memory: 1Gi
+    volumeClaimTemplates:
+    - name: data
+      spec:
+        storageClassName: fast
+        resources:
+          requests:
+            storage: 20Gi
+    schedulingPolicy:
+      nodeSelector:
+        disk: ssd
+      tolerations:
+      - key: dedicated
+        operator: Exists
+        effect: NoSchedule
+      affinity:
+        podAntiAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution:
+          - topologyKey: kubernetes.io/hostname
+`
+
+	var obj *unstructured.Unstructured
+	BeforeEach(func() {
+		data, err := yaml.YAMLToJSON([]byte(clusterYAML))
+		Expect(err).Should(Succeed())
+		obj = &unstructured.Unstructured{}
+		Expect(obj.UnmarshalJSON(data)).Should(Succeed())
+	})
+
+	It("build values from the cluster", func() {
+		values, comp := buildValuesFromCluster(obj)
+		Expect(comp).ShouldNot(BeNil())
+		Expect(values).Should(Equal(map[string]interface{}{
+			"terminationPolicy": "WipeOut",
+			"topology":          "replication",
+			"mode":              "replication",
+			"version":           "8.0.33",
+			"replicas":          int64(2),
+			"cpu":               0.5,
+			"memory":            float64(1),
+			"storage":           float64(20),
+			"storageClassName":  "fast",
+		}))
+
+		By("filter the values by the schema")
+		schema := &spec.Schema{}
+		Expect(json.Unmarshal([]byte(`{"type": "object", "properties": {
+			"replicas": {"type": "integer", "maximum": 1},
+			"cpu": {"type": "number"},
+			"mode": {"type": "string", "enum": ["standalone", "replication"]}
+		}}`), schema)).Should(Succeed())
+		out := &bytes.Buffer{}
+		Expect(filterValuesBySchema(out, &cluster.ChartInfo{Schema: schema}, values)).Should(Equal(map[string]interface{}{
+			"cpu":  0.5,
+			"mode": "replication",
+		}))
+		Expect(out.String()).Should(ContainSubstring("skip the value replicas=2 of the existing cluster"))
+	})
+
+	It("set the scheduling policy unless the flags are set", func() {
+		_, comp := buildValuesFromCluster(obj)
+		cmd := &cobra.Command{}
+		cmd.Flags().StringToString("node-labels", nil, "")
+		cmd.Flags().String("pod-anti-affinity", "Preferred", "")
+		Expect(cmd.Flags().Set("node-labels", "zone=a")).Should(Succeed())
+
+		o := &CreateSubCmdsOptions{NodeLabels: map[string]string{"zone": "a"}}
+		Expect(o.setSchedulingPolicyFromComponent(cmd, comp)).Should(Succeed())
+		Expect(o.NodeLabels).Should(Equal(map[string]string{"zone": "a"}))
+		Expect(o.PodAntiAffinity).Should(Equal("Required"))
+		Expect(o.TopologyKeys).Should(Equal([]string{"kubernetes.io/hostname"}))
+		Expect(o.Tolerations).Should(HaveLen(1))
+		Expect(o.Tolerations[0].Key).Should(Equal("dedicated"))
+	})
+})
@@ -27,6 +27,7 @@ import (
 	"regexp"
 
 	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -69,6 +70,24 @@ type CreateSubCmdsOptions struct {
 	// SkipSchemaValidation is used to skip the schema validation of the helm chart.
 	SkipSchemaValidation bool `json:"-"`
 
+	// ValuesFile is the file of the values to render the chart, the values of the flags explicitly set win.
+	ValuesFile string `json:"-"`
+
+	// Interactive prompts the values by walking the chart schema.
+	Interactive bool `json:"-"`
+
+	// Profile is the name of the profile whose values are used to render the chart.
+	Profile string `json:"-"`
+
+	// FromCluster is the name of the existing cluster whose spec is used to derive the values.
+	FromCluster string `json:"-"`
+
+	// Labels are added to the cluster object.
+	Labels map[string]string `json:"-"`
+
+	// backup is the backup settings copied from the existing cluster
+	backup map[string]interface{}
+
 	*action.CreateOptions
 }
 
@@ -101,8 +120,18 @@ func buildCreateSubCmds(createOptions *action.CreateOptions) []*cobra.Command {
 			Use:     t.String() + " NAME",
 			Short:   fmt.Sprintf("Create a %s cluster.", t),
 			Example: buildCreateSubCmdsExamples(t),
+			PreRun: func(cmd *cobra.Command, args []string) {
+				// the required values are prompted or read from the values file, profile or existing cluster
+				if o.Interactive || o.ValuesFile != "" || o.Profile != "" || o.FromCluster != "" {
+					markFlagsOptional(cmd)
+				}
+			},
 			Run: func(cmd *cobra.Command, args []string) {
 				o.Args = args
+				if o.Interactive {
+					cmdutil.CheckErr(o.RunWizard(cmd))
+					return
+				}
 				cmdutil.CheckErr(o.CreateOptions.Complete())
 				cmdutil.CheckErr(o.Complete(cmd))
 				cmdutil.CheckErr(o.Validate())
@@ -119,6 +148,14 @@ func buildCreateSubCmds(createOptions *action.CreateOptions) []*cobra.Command {
 		cmd.Flags().StringVar(&o.DryRun, "dry-run", "none", `Must be "client", or "server". If with client strategy, only print the object that would be sent, and no data is actually sent. If with server strategy, submit the server-side request, but no data is persistent.`)
 		cmd.Flags().Lookup("dry-run").NoOptDefVal = "unchanged"
 		printer.AddOutputFlagForCreate(cmd, &o.Format, false)
+		cmd.Flags().StringVarP(&o.ValuesFile, "file", "f", "", "Specify the values file to create the cluster, the flags explicitly set override the values in the file")
+		cmd.Flags().BoolVar(&o.Interactive, "interactive", false, "Prompt the cluster values by walking the cluster chart schema")
+		cmd.Flags().StringVar(&o.Profile, "profile", "", fmt.Sprintf("Specify the profile of the values, the profiles are read from the kbcli home dir '%s/%s' and the cluster chart", types.CliClusterProfiles, t))
+		cmd.Flags().StringVar(&o.FromCluster, "from-cluster", "", "Specify an existing cluster to derive the values, the flags explicitly set override the values")
+		_ = cmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return cluster.ListProfiles(o.ChartInfo.Chart, o.ClusterType), cobra.ShellCompDirectiveNoFileComp
+		})
+		_ = cmd.RegisterFlagCompletionFunc("from-cluster", util.ResourceNameCompletionFunc(o.Factory, types.ClusterGVR()))
 
 		// TODO: support enable logs when the api is ready.
 		// TODO: support set backup config?
@@ -171,6 +208,9 @@ func (o *CreateSubCmdsOptions) Complete(cmd *cobra.Command) error {
 	// get values from flags
 	if cmd != nil {
 		o.Values = getValuesFromFlags(cmd.LocalNonPersistentFlags())
+		if err = o.mergeValueSources(cmd); err != nil {
+			return err
+		}
 	}
 
 	// get all the rendered objects
@@ -237,12 +277,8 @@ func (o *CreateSubCmdsOptions) Complete(cmd *cobra.Command) error {
 }
 
 func (o *CreateSubCmdsOptions) Validate() error {
-	matched, _ := regexp.MatchString(`^[a-z]([-a-z0-9]*[a-z0-9])?$`, o.Name)
-	if !matched {
-		return fmt.Errorf("cluster name must begin with a letter and can only contain lowercase letters, numbers, and '-'")
-	}
-	if len(o.Name) > 16 {
-		return fmt.Errorf("cluster name should be less than 16 characters")
+	if err := validateClusterName(o.Name); err != nil {
+		return err
 	}
 	if o.Tenancy != "SharedNode" && o.Tenancy != "DedicatedNode" {
 		return fmt.Errorf("tenancy must be one of: (SharedNode, DedicatedNode)")
@@ -274,6 +310,26 @@ func (o *CreateSubCmdsOptions) Validate() error {
 	return cluster.ValidateValues(o.ChartInfo, o.Values)
 }
 
+func validateClusterName(name string) error {
+	matched, _ := regexp.MatchString(`^[a-z]([-a-z0-9]*[a-z0-9])?$`, name)
+	if !matched {
+		return fmt.Errorf("cluster name must begin with a letter and can only contain lowercase letters, numbers, and '-'")
+	}
+	if len(name) > 16 {
+		return fmt.Errorf("cluster name should be less than 16 characters")
+	}
+	return nil
+}
+
+// markFlagsOptional unmarks the required flags built from the chart schema
+func markFlagsOptional(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *flag.Flag) {
+		if _, ok := f.Annotations[cobra.BashCompOneRequiredFlag]; ok {
+			_ = cmd.Flags().SetAnnotation(f.Name, cobra.BashCompOneRequiredFlag, []string{"false"})
+		}
+	})
+}
+
 func (o *CreateSubCmdsOptions) Run() error {
 
 	objs, err := o.getObjectsInfo()
@@ -318,6 +374,22 @@ func (o *CreateSubCmdsOptions) Run() error {
 		}
 	}
 
+	if len(o.Labels) > 0 {
+		labels := clusterObj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range o.Labels {
+			labels[k] = v
+		}
+		clusterObj.SetLabels(labels)
+	}
+
+	// the backup settings copied from the existing cluster
+	if _, ok := spec["backup"]; !ok && spec != nil && o.backup != nil {
+		spec["backup"] = o.backup
+	}
+
 	// only edits the cluster object, other dependency objects are created directly
 	if o.EditBeforeCreate {
 		customEdit := action.NewCustomEditOptions(o.Factory, o.IOStreams, "create")
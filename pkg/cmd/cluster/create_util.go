@@ -20,6 +20,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package cluster
 
 import (
+	"fmt"
+	"io"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -64,9 +66,18 @@ func addCreateFlags(cmd *cobra.Command, f cmdutil.Factory, c *cluster.ChartInfo,
 
 // getValuesFromFlags gets the values from the flags, these values are used to render a cluster.
 func getValuesFromFlags(fs *flag.FlagSet) map[string]interface{} {
+	return getFlagsValues(fs, false)
+}
+
+// getChangedValuesFromFlags gets the values from the flags explicitly set in the command line.
+func getChangedValuesFromFlags(fs *flag.FlagSet) map[string]interface{} {
+	return getFlagsValues(fs, true)
+}
+
+func getFlagsValues(fs *flag.FlagSet, changedOnly bool) map[string]interface{} {
 	values := make(map[string]interface{}, 0)
 	fs.VisitAll(func(f *flag.Flag) {
-		if f.Name == "help" {
+		if f.Name == "help" || (changedOnly && !f.Changed) {
 			return
 		}
 		var val interface{}
@@ -94,6 +105,36 @@ func getValuesFromFlags(fs *flag.FlagSet) map[string]interface{} {
 	return flattenToNestedMap(values)
 }
 
+// loadValuesFile loads the values from the file, URL or stdin.
+func loadValuesFile(fileName string, in io.Reader) (map[string]interface{}, error) {
+	data, err := MultipleSourceComponents(fileName, in)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	if err = yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse values file %s: %w", fileName, err)
+	}
+	return values, nil
+}
+
+// mergeValues merges the src values into dst recursively, the values in src win.
+func mergeValues(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = map[string]interface{}{}
+	}
+	for k, v := range src {
+		if srcMap, ok := v.(map[string]interface{}); ok {
+			if dstMap, ok := dst[k].(map[string]interface{}); ok {
+				dst[k] = mergeValues(dstMap, srcMap)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
 // flattenToNestedMap takes a flat map with keys that can contain dots to represent nesting,
 // and returns a nested map structure.
 func flattenToNestedMap(flatMap map[string]interface{}) map[string]interface{} {
@@ -146,6 +187,12 @@ func buildCreateSubCmdsExamples(t cluster.ClusterType) string {
 
 	# Create a cluster with the specified cpu, memory and storage
 	kbcli cluster create {{ .ClusterType }} --cpu 1 --memory 2 --storage 10
+
+	# Create a cluster with the values of a profile, the flags explicitly set override the profile
+	kbcli cluster create {{ .ClusterType }} --profile small --replicas 3
+
+	# Create a cluster with the values derived from an existing cluster
+	kbcli cluster create {{ .ClusterType }} --from-cluster mycluster
 `
 
 	var builder strings.Builder
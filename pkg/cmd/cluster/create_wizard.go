@@ -0,0 +1,351 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/stoewer/go-strcase"
+	"golang.org/x/exp/slices"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/util/prompt"
+)
+
+// wizardField is a property of the cluster chart schema prompted by the create wizard
+type wizardField struct {
+	// key is the path of the property in the values, such as "replicas" or "proxy.replicas"
+	key string
+	// flagName is the name of the flag built from the property
+	flagName string
+	// group is the top level object property the field belongs to, it's empty for the top level fields
+	group    string
+	required bool
+	schema   *spec.Schema
+}
+
+// createWizard prompts the cluster values one by one
+type createWizard struct {
+	out io.Writer
+	// ask prompts the question with the default answer and returns the validated answer
+	ask func(label string, def string, validate func(string) error) (string, error)
+}
+
+func newCreateWizard(in io.Reader, out io.Writer) *createWizard {
+	return &createWizard{
+		out: out,
+		ask: func(label string, def string, validate func(string) error) (string, error) {
+			p := prompt.NewPrompt(label, validate, in)
+			p.Default = def
+			return p.Run()
+		},
+	}
+}
+
+// RunWizard prompts the values by walking the chart schema, shows the rendered manifests,
+// saves the answers as a values file and creates the cluster.
+func (o *CreateSubCmdsOptions) RunWizard(cmd *cobra.Command) error {
+	w := newCreateWizard(o.In, o.Out)
+	if err := o.CreateOptions.Complete(); err != nil {
+		return err
+	}
+	if o.Name == "" {
+		name, err := w.ask("Cluster name (leave empty to generate one):", "", func(s string) error {
+			if s == "" {
+				return nil
+			}
+			return validateClusterName(s)
+		})
+		if err != nil {
+			return err
+		}
+		o.Name = name
+	}
+
+	answers, err := w.run(collectWizardFields(o.ChartInfo), cmd.Flags())
+	if err != nil {
+		return err
+	}
+	// the flags values are used for the properties not answered
+	o.Values = mergeValues(getValuesFromFlags(cmd.LocalNonPersistentFlags()), answers)
+	if err = o.Complete(nil); err != nil {
+		return err
+	}
+	if err = o.Validate(); err != nil {
+		return err
+	}
+
+	objs, err := o.getObjectsInfo()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(o.Out, "\nThe cluster manifests to create:")
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.obj.Object)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "---\n%s", data)
+	}
+
+	file, err := w.ask("Save the answers to a values file (leave empty to skip):", "", nil)
+	if err != nil {
+		return err
+	}
+	if file != "" {
+		if err = writeValuesFile(file, answers); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "The answers are saved to %s, run \"kbcli cluster create %s NAME -f %s\" to reuse them\n", file, o.ClusterType, file)
+	}
+
+	confirmed, err := w.confirm("Create the cluster?")
+	if err != nil || !confirmed {
+		return err
+	}
+	return o.Run()
+}
+
+// run prompts the required fields first, then asks whether to configure each group of the optional fields.
+func (w *createWizard) run(fields []*wizardField, fs *flag.FlagSet) (map[string]interface{}, error) {
+	answers := map[string]interface{}{}
+	var group *string
+	skipGroup := false
+	for _, f := range fields {
+		if !f.required && (group == nil || *group != f.group) {
+			group = &f.group
+			name := f.group
+			if name == "" {
+				name = "general"
+			}
+			confirmed, err := w.confirm(fmt.Sprintf("Configure the optional %s settings?", name))
+			if err != nil {
+				return nil, err
+			}
+			skipGroup = !confirmed
+		}
+		if !f.required && skipGroup {
+			continue
+		}
+
+		answer, err := w.ask(f.label(), f.defaultAnswer(fs), func(s string) error {
+			_, err := f.parse(s)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		value, _ := f.parse(answer)
+		if value != nil {
+			setNestedValue(answers, strings.Split(f.key, "."), value)
+		}
+	}
+	return answers, nil
+}
+
+func (w *createWizard) confirm(label string) (bool, error) {
+	answer, err := w.ask(label+" (y/N):", "", func(s string) error {
+		switch strings.ToLower(s) {
+		case "", "y", "yes", "n", "no":
+			return nil
+		}
+		return fmt.Errorf("please answer y or n")
+	})
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+// collectWizardFields walks the cluster chart schemas and returns the fields sorted by the
+// required fields first, and then the optional fields grouped by the top level object property.
+func collectWizardFields(c *cluster.ChartInfo) []*wizardField {
+	var fields []*wizardField
+	keys := map[string]bool{}
+	var walk func(s *spec.Schema, prefix, flagPrefix, group string, required bool)
+	walk = func(s *spec.Schema, prefix, flagPrefix, group string, required bool) {
+		for name := range s.Properties {
+			prop := s.Properties[name]
+			key, flagName := name, strcase.KebabCase(name)
+			if prefix != "" {
+				key = prefix + "." + name
+				flagName = strcase.KebabCase(flagPrefix + "." + name)
+			}
+			if keys[key] {
+				continue
+			}
+			isRequired := required && slices.Contains(s.Required, name)
+			switch schemaType(&prop) {
+			case "null":
+				continue
+			case "object":
+				subGroup := group
+				if subGroup == "" {
+					subGroup = name
+				}
+				walk(&prop, key, flagName, subGroup, isRequired)
+				continue
+			case "array":
+				// only the arrays of scalar values are prompted
+				if prop.Items == nil || prop.Items.Schema == nil {
+					continue
+				}
+				if t := schemaType(prop.Items.Schema); t == "object" || t == "array" {
+					continue
+				}
+			}
+			keys[key] = true
+			fields = append(fields, &wizardField{
+				key:      key,
+				flagName: flagName,
+				group:    group,
+				required: isRequired,
+				schema:   &prop,
+			})
+		}
+	}
+	for _, s := range []*spec.Schema{c.Schema, c.SubSchema} {
+		if s != nil {
+			walk(s, "", "", "", true)
+		}
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.required != b.required {
+			return a.required
+		}
+		if a.group != b.group {
+			return a.group < b.group
+		}
+		return a.key < b.key
+	})
+	return fields
+}
+
+func schemaType(s *spec.Schema) string {
+	if len(s.Type) == 0 {
+		return "string"
+	}
+	return s.Type[0]
+}
+
+func (f *wizardField) label() string {
+	label := f.key
+	if f.schema.Description != "" {
+		label += " (" + f.schema.Description + ")"
+	}
+	var enums []string
+	for _, e := range f.schema.Enum {
+		enums = append(enums, fmt.Sprintf("%v", e))
+	}
+	if len(enums) > 0 {
+		label += " [" + strings.Join(enums, ", ") + "]"
+	}
+	return label + ":"
+}
+
+// defaultAnswer returns the default value of the flag which may be reset for the engine,
+// or the default value in the schema.
+func (f *wizardField) defaultAnswer(fs *flag.FlagSet) string {
+	if schemaType(f.schema) != "array" && fs != nil {
+		if fl := fs.Lookup(f.flagName); fl != nil {
+			return fl.DefValue
+		}
+	}
+	switch d := f.schema.Default.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		var items []string
+		for _, i := range d {
+			items = append(items, fmt.Sprintf("%v", i))
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprintf("%v", d)
+	}
+}
+
+// parse converts the answer to the value of the schema type and validates it against the schema,
+// nil is returned for the empty answer of the optional field.
+func (f *wizardField) parse(answer string) (interface{}, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		if f.required {
+			return nil, fmt.Errorf("%s is required", f.key)
+		}
+		return nil, nil
+	}
+
+	var (
+		value interface{}
+		err   error
+	)
+	if schemaType(f.schema) == "array" {
+		var items []interface{}
+		for _, s := range strings.Split(answer, ",") {
+			item, err := parseScalar(f.schema.Items.Schema, strings.TrimSpace(s))
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of %s: %v", f.key, err)
+			}
+			items = append(items, item)
+		}
+		value = items
+	} else if value, err = parseScalar(f.schema, answer); err != nil {
+		return nil, fmt.Errorf("invalid value of %s: %v", f.key, err)
+	}
+
+	if err = cluster.ValidateValue(f.schema, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func parseScalar(s *spec.Schema, answer string) (interface{}, error) {
+	switch schemaType(s) {
+	case "integer":
+		return strconv.Atoi(answer)
+	case "number":
+		return strconv.ParseFloat(answer, 64)
+	case "boolean":
+		return strconv.ParseBool(answer)
+	default:
+		return answer, nil
+	}
+}
+
+func writeValuesFile(file string, values map[string]interface{}) error {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
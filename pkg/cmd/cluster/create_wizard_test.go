@@ -0,0 +1,140 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+)
+
+var _ = Describe("create cluster wizard", func() {
+	const schemaJSON = `{
+	"type": "object",
+	"required": ["version", "mode"],
+	"properties": {
+		"version": {"type": "string", "description": "cluster version"},
+		"mode": {"type": "string", "enum": ["standalone", "replication"], "default": "standalone"},
+		"replicas": {"type": "integer", "minimum": 1, "maximum": 5, "default": 1},
+		"storageClasses": {"type": "array", "items": {"type": "string"}},
+		"proxy": {
+			"type": "object",
+			"properties": {
+				"enabled": {"type": "boolean"},
+				"cpu": {"type": "number", "default": 0.5}
+			}
+		}
+	}
+}`
+
+	var chartInfo *cluster.ChartInfo
+	BeforeEach(func() {
+		schema := &spec.Schema{}
+		Expect(json.Unmarshal([]byte(schemaJSON), schema)).Should(Succeed())
+		chartInfo = &cluster.ChartInfo{Schema: schema}
+	})
+
+	It("collect the fields with the required ones first", func() {
+		fields := collectWizardFields(chartInfo)
+		var keys []string
+		for _, f := range fields {
+			keys = append(keys, f.key)
+		}
+		Expect(keys).Should(Equal([]string{"mode", "version", "replicas", "storageClasses", "proxy.cpu", "proxy.enabled"}))
+		Expect(fields[0].required).Should(BeTrue())
+		Expect(fields[4].group).Should(Equal("proxy"))
+		Expect(fields[4].flagName).Should(Equal("proxy.cpu"))
+	})
+
+	It("parse and validate the answers", func() {
+		fields := map[string]*wizardField{}
+		for _, f := range collectWizardFields(chartInfo) {
+			fields[f.key] = f
+		}
+		_, err := fields["version"].parse("")
+		Expect(err).Should(HaveOccurred())
+		_, err = fields["mode"].parse("cluster")
+		Expect(err).Should(HaveOccurred())
+		_, err = fields["replicas"].parse("10")
+		Expect(err).Should(HaveOccurred())
+		v, err := fields["replicas"].parse("3")
+		Expect(err).Should(Succeed())
+		Expect(v).Should(Equal(3))
+		v, err = fields["storageClasses"].parse("a, b")
+		Expect(err).Should(Succeed())
+		Expect(v).Should(Equal([]interface{}{"a", "b"}))
+		v, err = fields["proxy.enabled"].parse("")
+		Expect(err).Should(Succeed())
+		Expect(v).Should(BeNil())
+		Expect(fields["proxy.cpu"].defaultAnswer(nil)).Should(Equal("0.5"))
+	})
+
+	It("run the wizard and skip the optional groups", func() {
+		answers := map[string]string{
+			"version":        "8.0",
+			"mode":           "replication",
+			"general":        "y",
+			"replicas":       "3",
+			"proxy":          "n",
+			"storageClasses": "",
+		}
+		w := &createWizard{
+			ask: func(label string, def string, validate func(string) error) (string, error) {
+				for k, v := range answers {
+					if strings.HasPrefix(label, k) || strings.Contains(label, "optional "+k+" ") {
+						return v, validate(v)
+					}
+				}
+				Fail("unexpected question " + label)
+				return "", nil
+			},
+		}
+		values, err := w.run(collectWizardFields(chartInfo), nil)
+		Expect(err).Should(Succeed())
+		Expect(values).Should(Equal(map[string]interface{}{
+			"version":  "8.0",
+			"mode":     "replication",
+			"replicas": 3,
+		}))
+
+		file := filepath.Join(GinkgoT().TempDir(), "values.yaml")
+		Expect(writeValuesFile(file, values)).Should(Succeed())
+		loaded, err := loadValuesFile(file, os.Stdin)
+		Expect(err).Should(Succeed())
+		Expect(loaded["mode"]).Should(Equal("replication"))
+	})
+
+	It("merge values", func() {
+		dst := map[string]interface{}{"cpu": 1, "proxy": map[string]interface{}{"cpu": 1, "memory": 1}}
+		src := map[string]interface{}{"memory": 2, "proxy": map[string]interface{}{"cpu": 2}}
+		Expect(mergeValues(dst, src)).Should(Equal(map[string]interface{}{
+			"cpu":    1,
+			"memory": 2,
+			"proxy":  map[string]interface{}{"cpu": 2, "memory": 1},
+		}))
+	})
+})
@@ -0,0 +1,312 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stoewer/go-strcase"
+	"golang.org/x/exp/slices"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/util/prompt"
+)
+
+const (
+	schemaTypeString  = "string"
+	schemaTypeInteger = "integer"
+	schemaTypeNumber  = "number"
+	schemaTypeBoolean = "boolean"
+	schemaTypeArray   = "array"
+	schemaTypeObject  = "object"
+)
+
+// unmarkRequiredFlags removes the required annotation of the flags, the required parameters
+// will be validated by the parameters schema after merging the values of the params file,
+// the interactive inputs and the flags.
+func unmarkRequiredFlags(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		delete(f.Annotations, cobra.BashCompOneRequiredFlag)
+	})
+}
+
+// loadParamsFile loads the parameters from a YAML or JSON file, "-" means reading from stdin.
+func (o *CustomOperations) loadParamsFile() (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if o.ParamsFile == "" {
+		return values, nil
+	}
+	data, err := MultipleSourceComponents(o.ParamsFile, o.In)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the params file %s: %s", o.ParamsFile, err.Error())
+	}
+	if err = yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse the params file %s: %s", o.ParamsFile, err.Error())
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	for name := range values {
+		if _, ok := o.SchemaProperties.Properties[name]; !ok {
+			return nil, fmt.Errorf(`unknown parameter "%s" in the params file, the OpsDefinition "%s" only supports: %s`,
+				name, o.OpsDefinitionName, strings.Join(sortedSchemaProperties(o.SchemaProperties), ", "))
+		}
+	}
+	return values, nil
+}
+
+// promptParams prompts the parameters which are not specified by the params file or flags one by one.
+func (o *CustomOperations) promptParams(values map[string]interface{}) error {
+	for _, name := range sortedSchemaProperties(o.SchemaProperties) {
+		if _, ok := values[name]; ok {
+			continue
+		}
+		value, err := promptSchemaValue(name, o.SchemaProperties.Properties[name],
+			slices.Contains(o.SchemaProperties.Required, name), o.In)
+		if err != nil {
+			return err
+		}
+		if value != nil {
+			values[name] = value
+		}
+	}
+	return nil
+}
+
+// promptSchemaValue prompts the value of the property, the nested properties of an object are prompted field by field.
+func promptSchemaValue(name string, prop apiextensionsv1.JSONSchemaProps, required bool, in io.Reader) (interface{}, error) {
+	if prop.Type == schemaTypeObject && len(prop.Properties) > 0 {
+		if !required {
+			confirmed, err := prompt.NewPrompt(fmt.Sprintf("Do you want to set %s? [y/N]:", name), nil, in).Run()
+			if err != nil {
+				return nil, err
+			}
+			if !strings.EqualFold(confirmed, "y") && !strings.EqualFold(confirmed, "yes") {
+				return nil, nil
+			}
+		}
+		obj := map[string]interface{}{}
+		for _, subName := range sortedSchemaProperties(&prop) {
+			value, err := promptSchemaValue(name+"."+subName, prop.Properties[subName], slices.Contains(prop.Required, subName), in)
+			if err != nil {
+				return nil, err
+			}
+			if value != nil {
+				obj[subName] = value
+			}
+		}
+		return obj, nil
+	}
+
+	defaultValue, err := schemaDefaultValue(prop)
+	if err != nil {
+		return nil, err
+	}
+	input, err := prompt.NewPrompt(buildPromptLabel(name, prop, required), func(input string) error {
+		if input == "" {
+			if required && defaultValue == nil {
+				return fmt.Errorf("%s is required", name)
+			}
+			return nil
+		}
+		_, err := parseSchemaValue(prop, input)
+		return err
+	}, in).Run()
+	if err != nil {
+		return nil, err
+	}
+	if input == "" {
+		return defaultValue, nil
+	}
+	return parseSchemaValue(prop, input)
+}
+
+func buildPromptLabel(name string, prop apiextensionsv1.JSONSchemaProps, required bool) string {
+	label := strings.Builder{}
+	label.WriteString(name)
+	if required {
+		label.WriteString("*")
+	}
+	if prop.Description != "" {
+		label.WriteString(fmt.Sprintf(" (%s)", prop.Description))
+	}
+	if len(prop.Enum) > 0 {
+		label.WriteString(fmt.Sprintf(" [%s]", strings.Join(enumValues(prop), "|")))
+	}
+	switch prop.Type {
+	case schemaTypeArray:
+		label.WriteString(" (separate the values with comma or input a JSON array)")
+	case schemaTypeObject:
+		label.WriteString(" (input a JSON object)")
+	}
+	if prop.Default != nil {
+		label.WriteString(fmt.Sprintf(" (default: %s)", strings.Trim(string(prop.Default.Raw), `"`)))
+	}
+	label.WriteString(":")
+	return label.String()
+}
+
+func enumValues(prop apiextensionsv1.JSONSchemaProps) []string {
+	var enums []string
+	for _, e := range prop.Enum {
+		enums = append(enums, strings.Trim(string(e.Raw), `"`))
+	}
+	return enums
+}
+
+func schemaDefaultValue(prop apiextensionsv1.JSONSchemaProps) (interface{}, error) {
+	if prop.Default == nil {
+		return nil, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(prop.Default.Raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// parseSchemaValue converts the input string to the value of the property type and checks the enum.
+func parseSchemaValue(prop apiextensionsv1.JSONSchemaProps, input string) (interface{}, error) {
+	var (
+		value interface{}
+		err   error
+	)
+	input = strings.TrimSpace(input)
+	switch prop.Type {
+	case schemaTypeInteger:
+		value, err = strconv.ParseInt(input, 10, 64)
+	case schemaTypeNumber:
+		value, err = strconv.ParseFloat(input, 64)
+	case schemaTypeBoolean:
+		value, err = strconv.ParseBool(input)
+	case schemaTypeObject:
+		obj := map[string]interface{}{}
+		err = json.Unmarshal([]byte(input), &obj)
+		value = obj
+	case schemaTypeArray:
+		var items []interface{}
+		if strings.HasPrefix(input, "[") {
+			err = json.Unmarshal([]byte(input), &items)
+		} else {
+			itemProp := apiextensionsv1.JSONSchemaProps{Type: schemaTypeString}
+			if prop.Items != nil && prop.Items.Schema != nil {
+				itemProp = *prop.Items.Schema
+			}
+			for _, s := range strings.Split(input, ",") {
+				item, itemErr := parseSchemaValue(itemProp, s)
+				if itemErr != nil {
+					return nil, itemErr
+				}
+				items = append(items, item)
+			}
+		}
+		value = items
+	default:
+		value = input
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value \"%s\"", prop.Type, input)
+	}
+	if len(prop.Enum) > 0 {
+		raw, _ := json.Marshal(value)
+		for _, e := range prop.Enum {
+			if string(e.Raw) == string(raw) {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid value \"%s\", legal values: %s", input, strings.Join(enumValues(prop), ", "))
+	}
+	return value, nil
+}
+
+// paramsToOpsParameters converts the parameter values to the OpsRequest parameters,
+// the values of array and object types are encoded to JSON.
+func paramsToOpsParameters(values map[string]interface{}) ([]opsv1alpha1.Parameter, error) {
+	params := make([]opsv1alpha1.Parameter, 0)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var value string
+		switch v := values[name].(type) {
+		case string:
+			value = v
+		case map[string]interface{}, []interface{}:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			value = string(b)
+		default:
+			value = fmt.Sprintf("%v", v)
+		}
+		params = append(params, opsv1alpha1.Parameter{Name: name, Value: value})
+	}
+	return params, nil
+}
+
+// normalizeParams converts the values to the JSON compatible types for the schema validation.
+func normalizeParams(values map[string]interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{}
+	if err = json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// sortedSchemaProperties returns the property names, the required ones are in the front.
+func sortedSchemaProperties(schema *apiextensionsv1.JSONSchemaProps) []string {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, rj := slices.Contains(schema.Required, names[i]), slices.Contains(schema.Required, names[j])
+		if ri != rj {
+			return ri
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// paramFlagName returns the flag name of the parameter.
+func paramFlagName(name string) string {
+	if name == "component" {
+		return fmt.Sprintf("%s-fork", strcase.KebabCase(name))
+	}
+	return strcase.KebabCase(name)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+)
+
+var _ = Describe("custom ops params", func() {
+	var (
+		in     *bytes.Buffer
+		o      *CustomOperations
+		cmd    *cobra.Command
+		schema *apiextensionsv1.JSONSchemaProps
+	)
+
+	BeforeEach(func() {
+		var streams genericiooptions.IOStreams
+		streams, in, _, _ = genericiooptions.NewTestIOStreams()
+		schema = &apiextensionsv1.JSONSchemaProps{
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"topic": {Type: "string", Description: "the topic name"},
+				"type": {Type: "string", Enum: []apiextensionsv1.JSON{
+					{Raw: []byte(`"create"`)}, {Raw: []byte(`"delete"`)},
+				}},
+				"partition": {Type: "integer", Default: &apiextensionsv1.JSON{Raw: []byte(`1`)}},
+				"configs": {Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"retention": {Type: "string"},
+				}},
+				"brokers": {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+					Schema: &apiextensionsv1.JSONSchemaProps{Type: "integer"},
+				}},
+			},
+			Required: []string{"topic", "type"},
+		}
+		o = &CustomOperations{
+			OperationsOptions: newBaseOperationsOptions(nil, streams, opsv1alpha1.CustomType, false),
+			OpsDefinitionName: "kafka-topic",
+			SchemaProperties:  schema,
+		}
+		cmd = &cobra.Command{}
+		cmd.Flags().String("topic", "", "")
+		cmd.Flags().String("type", "", "")
+		cmd.Flags().Int("partition", 1, "")
+	})
+
+	It("parse schema value", func() {
+		v, err := parseSchemaValue(schema.Properties["partition"], "3")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(v).Should(Equal(int64(3)))
+		_, err = parseSchemaValue(schema.Properties["partition"], "three")
+		Expect(err).Should(HaveOccurred())
+
+		v, err = parseSchemaValue(schema.Properties["brokers"], "1,2")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(v).Should(Equal([]interface{}{int64(1), int64(2)}))
+		v, err = parseSchemaValue(schema.Properties["brokers"], "[1, 2]")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(v).Should(HaveLen(2))
+
+		_, err = parseSchemaValue(schema.Properties["type"], "update")
+		Expect(err).Should(MatchError(ContainSubstring("create, delete")))
+		v, err = parseSchemaValue(schema.Properties["type"], "create")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(v).Should(Equal("create"))
+	})
+
+	It("convert params to ops parameters", func() {
+		params, err := paramsToOpsParameters(map[string]interface{}{
+			"topic":     "test",
+			"partition": int64(3),
+			"configs":   map[string]interface{}{"retention": "1h"},
+			"brokers":   []interface{}{1, 2},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(params).Should(Equal([]opsv1alpha1.Parameter{
+			{Name: "brokers", Value: "[1,2]"},
+			{Name: "configs", Value: `{"retention":"1h"}`},
+			{Name: "partition", Value: "3"},
+			{Name: "topic", Value: "test"},
+		}))
+	})
+
+	It("complete params from the params file", func() {
+		By("read the params from stdin, the flags take precedence over the file")
+		in.WriteString("topic: from-file\ntype: create\nconfigs:\n  retention: 1h\n")
+		o.ParamsFile = "-"
+		Expect(cmd.Flags().Set("topic", "from-flag")).Should(Succeed())
+		Expect(o.completeCustomSpec(cmd)).Should(Succeed())
+		Expect(o.Params).Should(ContainElements(
+			opsv1alpha1.Parameter{Name: "topic", Value: "from-flag"},
+			opsv1alpha1.Parameter{Name: "type", Value: "create"},
+			opsv1alpha1.Parameter{Name: "configs", Value: `{"retention":"1h"}`},
+		))
+
+		By("unknown param in the params file")
+		paramsFile := filepath.Join(GinkgoT().TempDir(), "params.yaml")
+		Expect(os.WriteFile(paramsFile, []byte("topic: test\nunknown: test\n"), 0644)).Should(Succeed())
+		o.ParamsFile = paramsFile
+		Expect(o.completeCustomSpec(cmd)).Should(MatchError(ContainSubstring(`unknown parameter "unknown"`)))
+
+		By("the required param is missing")
+		Expect(os.WriteFile(paramsFile, []byte("partition: 3\n"), 0644)).Should(Succeed())
+		Expect(o.completeCustomSpec(&cobra.Command{})).Should(HaveOccurred())
+
+		By("interactive can not be used with stdin")
+		o.ParamsFile = "-"
+		o.Interactive = true
+		Expect(o.completeCustomSpec(cmd)).Should(HaveOccurred())
+	})
+
+	It("prompt schema value", func() {
+		in.WriteString("5\n")
+		v, err := promptSchemaValue("partition", schema.Properties["partition"], false, in)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(v).Should(Equal(int64(5)))
+
+		Expect(buildPromptLabel("type", schema.Properties["type"], true)).Should(Equal("type* [create|delete]:"))
+		Expect(buildPromptLabel("partition", schema.Properties["partition"], false)).Should(Equal("partition (default: 1):"))
+		Expect(sortedSchemaProperties(schema)).Should(Equal([]string{"topic", "type", "brokers", "configs", "partition"}))
+	})
+
+	It("unmark required flags", func() {
+		Expect(cmd.MarkFlagRequired("topic")).Should(Succeed())
+		unmarkRequiredFlags(cmd)
+		Expect(cmd.Flag("topic").Annotations).ShouldNot(HaveKey(cobra.BashCompOneRequiredFlag))
+	})
+})
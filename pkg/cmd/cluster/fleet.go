@@ -0,0 +1,460 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	appsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+
+	"github.com/apecloud/kbcli/pkg/action"
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/prompt"
+)
+
+var (
+	fleetApplyExample = templates.Examples(`
+	# Create the missing clusters in the fleet file
+	kbcli cluster fleet apply -f fleet.yaml
+
+	# Create the clusters with at most 10 clusters created at the same time
+	kbcli cluster fleet apply -f fleet.yaml --concurrency 10
+
+	# An example of the fleet file
+	name: integration
+	clusters:
+	- name: mysql-1
+	  type: apecloud-mysql
+	  namespace: test
+	  labels:
+	    team: db
+	  values:
+	    replicas: 3
+	- name: pg-1
+	  type: postgresql`)
+
+	fleetStatusExample = templates.Examples(`
+	# Show the status of the clusters in the fleet file
+	kbcli cluster fleet status -f fleet.yaml
+
+	# Show the status of the clusters in the fleet integration
+	kbcli cluster fleet status integration`)
+
+	fleetDeleteExample = templates.Examples(`
+	# Delete the clusters in the fleet file
+	kbcli cluster fleet delete -f fleet.yaml
+
+	# Delete the clusters in the fleet integration without confirmation
+	kbcli cluster fleet delete integration --auto-approve`)
+)
+
+// fleet is a set of clusters created from one file, the clusters are labeled with the fleet name
+type fleet struct {
+	// Name is the fleet name, it's used as the value of the fleet label
+	Name     string         `json:"name"`
+	Clusters []fleetCluster `json:"clusters"`
+}
+
+type fleetCluster struct {
+	Name string              `json:"name"`
+	Type cluster.ClusterType `json:"type"`
+	// Namespace is the namespace of the cluster, the current namespace is used if it's empty
+	Namespace string                 `json:"namespace,omitempty"`
+	Labels    map[string]string      `json:"labels,omitempty"`
+	Values    map[string]interface{} `json:"values,omitempty"`
+}
+
+// fleetResult is the result of applying or deleting a cluster
+type fleetResult struct {
+	namespace string
+	name      string
+	status    string
+	message   string
+}
+
+const (
+	fleetStatusCreated   = "Created"
+	fleetStatusUnchanged = "Unchanged"
+	fleetStatusDeleted   = "Deleted"
+	fleetStatusFailed    = "Failed"
+	fleetStatusMissing   = "Missing"
+	fleetStatusSkipped   = "Skipped"
+)
+
+type fleetOptions struct {
+	Factory cmdutil.Factory
+	genericiooptions.IOStreams
+
+	file        string
+	name        string
+	concurrency int
+	autoApprove bool
+
+	namespace string
+	dynamic   dynamic.Interface
+	fleet     *fleet
+}
+
+func NewFleetCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fleet",
+		Short: "Create, check and delete a fleet of clusters described in one file.",
+	}
+	cmd.AddCommand(
+		newFleetApplyCmd(f, streams),
+		newFleetStatusCmd(f, streams),
+		newFleetDeleteCmd(f, streams),
+	)
+	return cmd
+}
+
+func newFleetOptions(f cmdutil.Factory, streams genericiooptions.IOStreams) *fleetOptions {
+	return &fleetOptions{
+		Factory:     f,
+		IOStreams:   streams,
+		concurrency: 5,
+	}
+}
+
+func newFleetApplyCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := newFleetOptions(f, streams)
+	cmd := &cobra.Command{
+		Use:     "apply -f FILE",
+		Short:   "Create the missing clusters in the fleet file.",
+		Example: fleetApplyExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.complete(args))
+			cmdutil.CheckErr(o.validate())
+			cmdutil.CheckErr(o.apply())
+		},
+	}
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "Specify the fleet file, support a local file, URL or '-' for stdin")
+	cmd.Flags().IntVar(&o.concurrency, "concurrency", o.concurrency, "Specify the maximum number of clusters created at the same time")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func newFleetStatusCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := newFleetOptions(f, streams)
+	cmd := &cobra.Command{
+		Use:     "status [NAME]",
+		Short:   "Show the status of the clusters in the fleet.",
+		Example: fleetStatusExample,
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.complete(args))
+			cmdutil.CheckErr(o.validate())
+			cmdutil.CheckErr(o.status())
+		},
+	}
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "Specify the fleet file, the clusters in the file but not created are shown as missing")
+	return cmd
+}
+
+func newFleetDeleteCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := newFleetOptions(f, streams)
+	cmd := &cobra.Command{
+		Use:     "delete [NAME]",
+		Short:   "Delete the clusters in the fleet.",
+		Example: fleetDeleteExample,
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.complete(args))
+			cmdutil.CheckErr(o.validate())
+			cmdutil.CheckErr(o.delete())
+		},
+	}
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "Specify the fleet file to read the fleet name")
+	cmd.Flags().IntVar(&o.concurrency, "concurrency", o.concurrency, "Specify the maximum number of clusters deleted at the same time")
+	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before deleting the clusters")
+	return cmd
+}
+
+func (o *fleetOptions) complete(args []string) error {
+	var err error
+	if o.file != "" {
+		if o.fleet, err = loadFleet(o.file, o.In); err != nil {
+			return err
+		}
+		o.name = o.fleet.Name
+	}
+	if len(args) > 0 {
+		o.name = args[0]
+	}
+	if o.namespace, _, err = o.Factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	o.dynamic, err = o.Factory.DynamicClient()
+	return err
+}
+
+func (o *fleetOptions) validate() error {
+	if o.name == "" {
+		return fmt.Errorf("the fleet name must be specified by the argument or the fleet file")
+	}
+	if errs := validation.IsValidLabelValue(o.name); len(errs) > 0 {
+		return fmt.Errorf("invalid fleet name %s: %s", o.name, strings.Join(errs, "; "))
+	}
+	if o.concurrency < 1 {
+		return fmt.Errorf("--concurrency must be greater than 0")
+	}
+	if o.fleet != nil {
+		return validateFleet(o.fleet)
+	}
+	return nil
+}
+
+func loadFleet(file string, in io.Reader) (*fleet, error) {
+	data, err := MultipleSourceComponents(file, in)
+	if err != nil {
+		return nil, err
+	}
+	f := &fleet{}
+	if err = yaml.UnmarshalStrict(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse fleet file %s: %w", file, err)
+	}
+	return f, nil
+}
+
+func validateFleet(f *fleet) error {
+	names := map[string]bool{}
+	for _, c := range f.Clusters {
+		if err := validateClusterName(c.Name); err != nil {
+			return fmt.Errorf("invalid cluster %s: %w", c.Name, err)
+		}
+		if _, ok := cluster.ClusterTypeCharts[c.Type]; !ok {
+			return fmt.Errorf("the type %s of cluster %s is not supported, run \"kbcli cluster list-types\" to list the supported types", c.Type, c.Name)
+		}
+		key := c.Namespace + "/" + c.Name
+		if names[key] {
+			return fmt.Errorf("cluster %s is duplicated", c.Name)
+		}
+		names[key] = true
+	}
+	return nil
+}
+
+// apply creates the missing clusters in parallel, the existing clusters are not changed.
+func (o *fleetOptions) apply() error {
+	results := make([]fleetResult, len(o.fleet.Clusters))
+	var failed int32
+	g := errgroup.Group{}
+	g.SetLimit(o.concurrency)
+	for i := range o.fleet.Clusters {
+		g.Go(func() error {
+			results[i] = o.applyCluster(&o.fleet.Clusters[i])
+			if results[i].status == fleetStatusFailed {
+				atomic.AddInt32(&failed, 1)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	printFleetResults(o.Out, results)
+	if failed > 0 {
+		return fmt.Errorf("failed to create %d of %d clusters in fleet %s", failed, len(results), o.name)
+	}
+	return nil
+}
+
+func (o *fleetOptions) applyCluster(c *fleetCluster) fleetResult {
+	res := fleetResult{namespace: c.Namespace, name: c.Name}
+	if res.namespace == "" {
+		res.namespace = o.namespace
+	}
+	_, err := o.dynamic.Resource(types.ClusterGVR()).Namespace(res.namespace).Get(context.TODO(), c.Name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		res.status, res.message = fleetStatusUnchanged, "cluster already exists"
+	case !apierrors.IsNotFound(err):
+		res.status, res.message = fleetStatusFailed, err.Error()
+	default:
+		if err = o.createCluster(c, res.namespace); err != nil {
+			res.status, res.message = fleetStatusFailed, err.Error()
+		} else {
+			res.status = fleetStatusCreated
+		}
+	}
+	return res
+}
+
+// createCluster renders the cluster chart with the default values of the create flags and the values of
+// the fleet cluster, and creates the cluster labeled with the fleet name.
+func (o *fleetOptions) createCluster(c *fleetCluster, namespace string) error {
+	createOptions := &action.CreateOptions{
+		Factory:   o.Factory,
+		Namespace: namespace,
+		Args:      []string{c.Name},
+		GVR:       types.ClusterGVR(),
+		Quiet:     true,
+		IOStreams: genericiooptions.IOStreams{In: o.In, Out: io.Discard, ErrOut: io.Discard},
+	}
+	if err := createOptions.Complete(); err != nil {
+		return err
+	}
+	sub, err := NewSubCmdsOptions(createOptions, c.Type)
+	if err != nil {
+		return err
+	}
+
+	// the default values are the same as the flags of the create sub command
+	cmd := &cobra.Command{}
+	if err = addCreateFlags(cmd, o.Factory, sub.ChartInfo, c.Type.String()); err != nil {
+		return err
+	}
+	sub.Values = mergeValues(getValuesFromFlags(cmd.Flags()), c.Values)
+	sub.Labels = map[string]string{}
+	for k, v := range c.Labels {
+		sub.Labels[k] = v
+	}
+	sub.Labels[types.FleetLabelKey] = o.name
+
+	if err = sub.Complete(nil); err != nil {
+		return err
+	}
+	if err = sub.Validate(); err != nil {
+		return err
+	}
+	return sub.Run()
+}
+
+// listClusters lists the clusters with the fleet label in all namespaces
+func (o *fleetOptions) listClusters() ([]unstructured.Unstructured, error) {
+	list, err := o.dynamic.Resource(types.ClusterGVR()).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", types.FleetLabelKey, o.name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func (o *fleetOptions) status() error {
+	clusters, err := o.listClusters()
+	if err != nil {
+		return err
+	}
+	if len(clusters) == 0 && o.fleet == nil {
+		fmt.Fprintf(o.Out, "No clusters found in fleet %s\n", o.name)
+		return nil
+	}
+
+	found := map[string]bool{}
+	tbl := printer.NewTablePrinter(o.Out)
+	tbl.SetHeader("NAMESPACE", "NAME", "STATUS", "CREATED-TIME")
+	for _, c := range clusters {
+		found[c.GetNamespace()+"/"+c.GetName()] = true
+		phase, _, _ := unstructured.NestedString(c.Object, "status", "phase")
+		createdTime := c.GetCreationTimestamp()
+		tbl.AddRow(c.GetNamespace(), c.GetName(), phase, util.TimeFormat(&createdTime))
+	}
+	if o.fleet != nil {
+		for _, c := range o.fleet.Clusters {
+			namespace := c.Namespace
+			if namespace == "" {
+				namespace = o.namespace
+			}
+			if !found[namespace+"/"+c.Name] {
+				tbl.AddRow(namespace, c.Name, fleetStatusMissing, "")
+			}
+		}
+	}
+	tbl.Print()
+	return nil
+}
+
+func (o *fleetOptions) delete() error {
+	clusters, err := o.listClusters()
+	if err != nil {
+		return err
+	}
+	if len(clusters) == 0 {
+		fmt.Fprintf(o.Out, "No clusters found in fleet %s\n", o.name)
+		return nil
+	}
+	if !o.autoApprove {
+		var names []string
+		for _, c := range clusters {
+			names = append(names, c.GetNamespace()+"/"+c.GetName())
+		}
+		msg := fmt.Sprintf("The clusters of fleet %s will be deleted:\n  %s", o.name, strings.Join(names, "\n  "))
+		if err = prompt.Confirm([]string{o.name}, o.In, msg, "Please type the fleet name to confirm:"); err != nil {
+			return err
+		}
+	}
+
+	results := make([]fleetResult, len(clusters))
+	var failed int32
+	g := errgroup.Group{}
+	g.SetLimit(o.concurrency)
+	for i := range clusters {
+		g.Go(func() error {
+			c := clusters[i]
+			results[i] = fleetResult{namespace: c.GetNamespace(), name: c.GetName(), status: fleetStatusDeleted}
+			// the same as 'kbcli cluster delete', the protected clusters are never deleted
+			if policy, _, _ := unstructured.NestedString(c.Object, "spec", "terminationPolicy"); policy == string(appsv1.DoNotTerminate) {
+				results[i].status, results[i].message = fleetStatusSkipped, fmt.Sprintf("protected by termination policy %s", policy)
+				return nil
+			}
+			if err := o.dynamic.Resource(types.ClusterGVR()).Namespace(c.GetNamespace()).Delete(context.TODO(), c.GetName(), metav1.DeleteOptions{}); err != nil {
+				results[i].status, results[i].message = fleetStatusFailed, err.Error()
+				atomic.AddInt32(&failed, 1)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	printFleetResults(o.Out, results)
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of %d clusters in fleet %s", failed, len(results), o.name)
+	}
+	return nil
+}
+
+func printFleetResults(out io.Writer, results []fleetResult) {
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("NAMESPACE", "NAME", "STATUS", "MESSAGE")
+	for _, r := range results {
+		tbl.AddRow(r.namespace, r.name, r.status, r.message)
+	}
+	tbl.Print()
+}
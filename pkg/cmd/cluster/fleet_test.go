@@ -0,0 +1,135 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("cluster fleet", func() {
+	const fleetYAML = `
+name: integration
+clusters:
+- name: mysql-1
+  type: apecloud-mysql
+  labels:
+    team: db
+  values:
+    replicas: 3
+- name: mysql-2
+  type: apecloud-mysql
+  namespace: test
+`
+	var (
+		tf        *cmdtesting.TestFactory
+		streams   genericiooptions.IOStreams
+		out       *bytes.Buffer
+		fleetFile string
+	)
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericiooptions.NewTestIOStreams()
+		tf = testing.NewTestFactory(testing.Namespace)
+		managed := testing.FakeCluster("mysql-1", testing.Namespace)
+		managed.Labels = map[string]string{types.FleetLabelKey: "integration"}
+		protected := testing.FakeCluster("mysql-3", testing.Namespace)
+		protected.Labels = map[string]string{types.FleetLabelKey: "integration"}
+		protected.Spec.TerminationPolicy = kbappsv1.DoNotTerminate
+		other := testing.FakeCluster("other", testing.Namespace)
+		tf.FakeDynamicClient = testing.FakeDynamicClient(managed, protected, other)
+
+		fleetFile = filepath.Join(GinkgoT().TempDir(), "fleet.yaml")
+		Expect(os.WriteFile(fleetFile, []byte(fleetYAML), 0644)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	It("command", func() {
+		cmd := NewFleetCmd(tf, streams)
+		Expect(cmd).ShouldNot(BeNil())
+		Expect(cmd.Commands()).Should(HaveLen(3))
+	})
+
+	It("load and validate the fleet file", func() {
+		o := newFleetOptions(tf, streams)
+		o.file = fleetFile
+		Expect(o.complete(nil)).Should(Succeed())
+		Expect(o.name).Should(Equal("integration"))
+		Expect(o.fleet.Clusters).Should(HaveLen(2))
+		Expect(o.fleet.Clusters[0].Values).Should(HaveKeyWithValue("replicas", float64(3)))
+		Expect(o.validate()).Should(Succeed())
+
+		o.fleet.Clusters = append(o.fleet.Clusters, o.fleet.Clusters[0])
+		Expect(o.validate()).Should(HaveOccurred())
+		o.fleet.Clusters[2] = fleetCluster{Name: "pg", Type: "not-exist"}
+		Expect(o.validate()).Should(HaveOccurred())
+
+		o.name = "invalid name"
+		Expect(o.validate()).Should(HaveOccurred())
+	})
+
+	It("show the status of the fleet", func() {
+		o := newFleetOptions(tf, streams)
+		o.file = fleetFile
+		Expect(o.complete(nil)).Should(Succeed())
+		Expect(o.status()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("mysql-1"))
+		Expect(out.String()).Should(ContainSubstring(fleetStatusMissing))
+		Expect(out.String()).ShouldNot(ContainSubstring("other"))
+	})
+
+	It("apply the fleet with the existing cluster unchanged", func() {
+		o := newFleetOptions(tf, streams)
+		o.file = fleetFile
+		Expect(o.complete(nil)).Should(Succeed())
+		res := o.applyCluster(&o.fleet.Clusters[0])
+		Expect(res.status).Should(Equal(fleetStatusUnchanged))
+	})
+
+	It("delete the clusters in the fleet", func() {
+		o := newFleetOptions(tf, streams)
+		o.autoApprove = true
+		Expect(o.complete([]string{"integration"})).Should(Succeed())
+		Expect(o.validate()).Should(Succeed())
+		Expect(o.delete()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring(fleetStatusDeleted))
+		Expect(out.String()).Should(ContainSubstring(fleetStatusSkipped))
+
+		By("the protected cluster is kept")
+		clusters, err := o.listClusters()
+		Expect(err).Should(Succeed())
+		Expect(clusters).Should(HaveLen(1))
+		Expect(clusters[0].GetName()).Should(Equal("mysql-3"))
+	})
+})
@@ -79,6 +79,7 @@ type OperationsOptions struct {
 
 	ComponentDefinitionName string `json:"componentDefinitionName"`
 	ServiceVersion          string `json:"serviceVersion"`
+	LatestPatch             bool   `json:"-"`
 
 	// VerticalScaling options
 	CPU    string `json:"cpu"`
@@ -212,9 +213,20 @@ func (o *OperationsOptions) CompleteComponentsFlag() error {
 }
 
 func (o *OperationsOptions) validateUpgrade(cluster *appsv1.Cluster) error {
+	if o.LatestPatch {
+		if o.ServiceVersion != "nil" {
+			return fmt.Errorf("--latest-patch can not be used with --service-version")
+		}
+		if err := o.completeLatestPatch(cluster); err != nil {
+			return err
+		}
+	}
 	if o.ComponentDefinitionName == "nil" && o.ServiceVersion == "nil" {
 		return fmt.Errorf("missing component-def or service-version")
 	}
+	if err := o.validateServiceVersion(cluster); err != nil {
+		return err
+	}
 	validateCompSpec := func(comSpec appsv1.ClusterComponentSpec, compName string) error {
 		if (o.ComponentDefinitionName == "nil" || o.ComponentDefinitionName == comSpec.ComponentDef) &&
 			(o.ServiceVersion == "nil" || o.ServiceVersion == comSpec.ServiceVersion) {
@@ -225,6 +237,82 @@ func (o *OperationsOptions) validateUpgrade(cluster *appsv1.Cluster) error {
 	return o.handleComponentOps(cluster, validateCompSpec)
 }
 
+// completeLatestPatch sets the service version to the newest patch version which is compatible with the components.
+func (o *OperationsOptions) completeLatestPatch(cluster *appsv1.Cluster) error {
+	if len(o.ComponentNames) == 0 {
+		return fmt.Errorf(`missing components, please specify the "--components" flag for the cluster`)
+	}
+	plans, err := buildUpgradePlans(o.Dynamic, cluster, o.ComponentNames)
+	if err != nil {
+		return err
+	}
+	for _, plan := range plans {
+		target := latestPatchTarget(plan)
+		if target == nil {
+			return fmt.Errorf(`no newer patch version of service version "%s" found for component "%s"`, plan.ServiceVersion, plan.Name)
+		}
+		if o.ServiceVersion != "nil" && o.ServiceVersion != target.ServiceVersion {
+			return fmt.Errorf("the latest patch versions of the components are different, please upgrade them separately")
+		}
+		o.ServiceVersion = target.ServiceVersion
+		if !target.CompDefChanged {
+			continue
+		}
+		switch o.ComponentDefinitionName {
+		case "nil":
+			o.ComponentDefinitionName = target.ComponentDef
+		case target.ComponentDef:
+		default:
+			return fmt.Errorf(`service version "%s" requires the component definition "%s" for component "%s"`,
+				target.ServiceVersion, target.ComponentDef, plan.Name)
+		}
+	}
+	fmt.Fprintf(o.Out, "Upgrade components %s to the latest patch version %s\n", strings.Join(o.ComponentNames, ","), o.ServiceVersion)
+	return nil
+}
+
+// validateServiceVersion checks the service version is provided by the ComponentVersions compatible with the components,
+// or with the target ComponentDefinition if it is specified. The check is skipped if no compatible ComponentVersion is found.
+func (o *OperationsOptions) validateServiceVersion(cluster *appsv1.Cluster) error {
+	if o.ServiceVersion == "nil" || len(o.ComponentNames) == 0 {
+		return nil
+	}
+	if o.ComponentDefinitionName != "nil" {
+		cmpvs, err := listComponentVersions(o.Dynamic)
+		if err != nil {
+			return err
+		}
+		versions := compDefServiceVersions(cmpvs, o.ComponentDefinitionName)
+		if len(versions) == 0 || slices.Contains(versions, o.ServiceVersion) {
+			return nil
+		}
+		return fmt.Errorf(`service version "%s" is not compatible with component definition "%s", available versions: %s`,
+			o.ServiceVersion, o.ComponentDefinitionName, strings.Join(versions, ","))
+	}
+	plans, err := buildUpgradePlans(o.Dynamic, cluster, o.ComponentNames)
+	if err != nil {
+		return err
+	}
+	for _, plan := range plans {
+		if len(plan.Targets) == 0 || plan.ServiceVersion == o.ServiceVersion {
+			continue
+		}
+		var versions []string
+		found := false
+		for _, t := range plan.Targets {
+			versions = append(versions, t.ServiceVersion)
+			if t.ServiceVersion == o.ServiceVersion {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf(`service version "%s" is not compatible with component "%s", available versions: %s, run "kbcli cluster upgrade-plan %s" for details`,
+				o.ServiceVersion, plan.Name, strings.Join(slices.Compact(versions), ","), cluster.Name)
+		}
+	}
+	return nil
+}
+
 func (o *OperationsOptions) handleComponentOps(cluster *appsv1.Cluster, handleF func(compSpec appsv1.ClusterComponentSpec, compName string) error) error {
 	for _, v := range cluster.Spec.ComponentSpecs {
 		if !slices.Contains(o.ComponentNames, v.Name) {
@@ -647,6 +735,9 @@ var upgradeExample = templates.Examples(`
 
 		# upgrade the component with new component definition and specified service version
 		kbcli cluster upgrade mycluster --component-def=8.0.30 --service-version=8.0.30  --components my-comp
+
+		# upgrade the component to the newest compatible patch version
+		kbcli cluster upgrade mycluster --latest-patch --components my-comp
 `)
 
 // NewUpgradeCmd creates an upgrade command
@@ -670,6 +761,7 @@ func NewUpgradeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra
 	o.addCommonFlags(cmd, f)
 	cmd.Flags().StringVar(&o.ComponentDefinitionName, compDefFlag, "nil", "Referring to the ComponentDefinition")
 	cmd.Flags().StringVar(&o.ServiceVersion, serviceVersionFlag, "nil", "Referring to the serviceVersion that is provided by ComponentDefinition and ComponentVersion")
+	cmd.Flags().BoolVar(&o.LatestPatch, "latest-patch", false, "Upgrade to the newest patch version which is compatible with the current service version")
 	cmd.Flags().BoolVar(&o.AutoApprove, "auto-approve", false, "Skip interactive approval before upgrading the cluster")
 	return cmd
 }
@@ -1034,6 +1126,15 @@ var customOpsExample = templates.Examples(`
 
 		# example for kafka quota
         kbcli cluster custom-ops kafka-quota --cluster mycluster --user client --producerByteRate 1024 --consumerByteRate 2048
+
+		# create a custom ops with the params in a YAML or JSON file, the flags take precedence over the file
+		kbcli cluster custom-ops kafka-quota mycluster --params-file params.yaml
+
+		# read the params from stdin
+		cat params.yaml | kbcli cluster custom-ops kafka-quota mycluster --params-file -
+
+		# input the params interactively
+		kbcli cluster custom-ops kafka-quota mycluster --interactive
 `)
 
 type CustomOperations struct {
@@ -1041,6 +1142,10 @@ type CustomOperations struct {
 	OpsDefinitionName string                  `json:"opsDefinitionName"`
 	Params            []opsv1alpha1.Parameter `json:"params,omitempty"`
 	SchemaProperties  *apiextensionsv1.JSONSchemaProps
+	// ParamsFile is the YAML or JSON file of the params, "-" means reading from stdin
+	ParamsFile string `json:"-"`
+	// Interactive prompts the params which are not specified by the params file and flags
+	Interactive bool `json:"-"`
 }
 
 func NewCustomOpsCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
@@ -1113,6 +1218,12 @@ func buildCustomOpsCmds(option *OperationsOptions) []*cobra.Command {
 			Short:             fmt.Sprintf("Create a custom ops with opsDef %s", t.GetName()),
 			Example:           buildCustomOpsExamples(t),
 			ValidArgsFunction: util.ResourceNameCompletionFunc(option.Factory, types.ClusterGVR()),
+			PreRun: func(cmd *cobra.Command, args []string) {
+				// the required params can be provided by the params file or interactive inputs
+				if o.ParamsFile != "" || o.Interactive {
+					unmarkRequiredFlags(cmd)
+				}
+			},
 			Run: func(cmd *cobra.Command, args []string) {
 				o.Args = args
 				cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
@@ -1126,6 +1237,8 @@ func buildCustomOpsCmds(option *OperationsOptions) []*cobra.Command {
 		o.addCommonFlags(cmd, option.Factory)
 		flags.AddComponentFlag(option.Factory, cmd, &o.Component, "Specify the component name of the cluster. if not specified, using the first component which referenced the defined componentDefinition.")
 		cmd.Flags().BoolVar(&o.AutoApprove, "auto-approve", false, "Skip interactive approval before promote the instance")
+		cmd.Flags().StringVar(&o.ParamsFile, "params-file", "", "The YAML or JSON file of the params, use \"-\" to read from stdin. The params specified by flags take precedence")
+		cmd.Flags().BoolVar(&o.Interactive, "interactive", false, "Prompt the params which are not specified by the params file or flags one by one")
 		// build opsDef flags
 		util.CheckErr(o.addOpsDefFlags(cmd, t))
 		cmds = append(cmds, cmd)
@@ -1211,35 +1324,58 @@ func (o *CustomOperations) validateAndCompleteComponentName() error {
 }
 
 func (o *CustomOperations) completeCustomSpec(cmd *cobra.Command) error {
-	var (
-		params   = make([]opsv1alpha1.Parameter, 0)
-		paramMap = map[string]string{}
-	)
-	// Construct config and credential map from flags
-	if o.SchemaProperties != nil {
-		fromFlags := flags.FlagsToValues(cmd.LocalNonPersistentFlags(), true)
-		for name := range o.SchemaProperties.Properties {
-			flagName := strcase.KebabCase(name)
-			if name == "component" {
-				flagName = fmt.Sprintf("%s-fork", flagName)
-			}
-			if val, ok := fromFlags[flagName]; ok {
-				params = append(params, opsv1alpha1.Parameter{
-					Name:  name,
-					Value: val.String(),
-				})
-				paramMap[name] = val.String()
-			}
+	if o.SchemaProperties == nil {
+		if o.ParamsFile != "" || o.Interactive {
+			return fmt.Errorf(`the OpsDefinition "%s" does not define any params`, o.OpsDefinitionName)
 		}
-		// validate if flags values are legal.
-		data, err := common.ConvertStringToInterfaceBySchemaType(o.SchemaProperties, paramMap)
-		if err != nil {
-			return err
+		o.Params = make([]opsv1alpha1.Parameter, 0)
+		return nil
+	}
+	if o.ParamsFile == "-" && o.Interactive {
+		return fmt.Errorf("--interactive can not be used when reading the params file from stdin")
+	}
+	values, err := o.loadParamsFile()
+	if err != nil {
+		return err
+	}
+	// Construct config and credential map from flags, the flags take precedence over the params file
+	paramMap := map[string]string{}
+	fromFlags := flags.FlagsToValues(cmd.LocalNonPersistentFlags(), true)
+	for name := range o.SchemaProperties.Properties {
+		if val, ok := fromFlags[paramFlagName(name)]; ok {
+			paramMap[name] = val.String()
 		}
-		if err = common.ValidateDataWithSchema(o.SchemaProperties, data); err != nil {
+	}
+	flagValues, err := common.ConvertStringToInterfaceBySchemaType(o.SchemaProperties, paramMap)
+	if err != nil {
+		return err
+	}
+	for name, value := range flagValues {
+		values[name] = value
+	}
+	if o.Interactive {
+		if err = o.promptParams(values); err != nil {
 			return err
 		}
 	}
+	// validate if the params are legal before creating the OpsRequest.
+	data, err := normalizeParams(values)
+	if err != nil {
+		return err
+	}
+	if err = common.ValidateDataWithSchema(o.SchemaProperties, data); err != nil {
+		return err
+	}
+	params, err := paramsToOpsParameters(values)
+	if err != nil {
+		return err
+	}
+	// keep the raw values of flags
+	for i := range params {
+		if val, ok := paramMap[params[i].Name]; ok {
+			params[i].Value = val
+		}
+	}
 	o.Params = params
 	return nil
 }
@@ -20,16 +20,26 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package cluster
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/url"
 	"os"
+	"os/signal"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/repo"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
@@ -50,6 +60,12 @@ var clusterRegisterExample = templates.Examples(`
 
 	# Register a cluster type from a Helm repository, specifying the version and engine.
 	kbcli cluster register mysql --engine mysql --version 0.9.0 --repo https://jihulab.com/api/v4/projects/150246/packages/helm/stable
+
+	# Register a cluster type from an OCI registry, the credentials are read from the helm registry config or the docker config
+	kbcli cluster register mysql --source oci://registry-1.docker.io/apecloud/mysql-cluster --version 1.0.0
+
+	# Register a cluster type from a local chart directory, and re-register it when the chart files change
+	kbcli cluster register mycluster --source ./mycluster-chart --watch
 `)
 
 type registerOption struct {
@@ -64,6 +80,10 @@ type registerOption struct {
 	engine     string
 	repo       string
 	version    string
+	// watch re-registers the cluster type when the local source changes
+	watch bool
+	// plainHTTP uses the insecure HTTP connection to pull the chart from the OCI registry
+	plainHTTP bool
 }
 
 func newRegisterOption(f cmdutil.Factory, streams genericiooptions.IOStreams) *registerOption {
@@ -86,13 +106,20 @@ func newRegisterCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobr
 			cmdutil.CheckErr(o.validate())
 			cmdutil.CheckErr(o.run())
 			fmt.Fprint(streams.Out, BuildRegisterSuccessExamples(o.clusterType))
+			if o.watch {
+				ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer cancel()
+				cmdutil.CheckErr(o.watchSource(ctx))
+			}
 		},
 	}
-	cmd.Flags().StringVarP(&o.source, "source", "S", "", "Specify the cluster type chart source, support a URL or a local file path")
+	cmd.Flags().StringVarP(&o.source, "source", "S", "", "Specify the cluster type chart source, support a URL, an OCI reference (oci://), a local file path or a local chart directory")
 	cmd.Flags().StringVar(&o.alias, "alias", "", "Set the cluster type alias")
 	cmd.Flags().StringVar(&o.engine, "engine", "", "Specify the cluster chart name in helm repo")
-	cmd.Flags().StringVar(&o.repo, "repo", types.ClusterChartsRepoURL, "Specify the url of helm repo which contains cluster charts")
-	cmd.Flags().StringVar(&o.version, "version", "", "Specify the version of cluster chart to register")
+	cmd.Flags().StringVar(&o.repo, "repo", types.ClusterChartsRepoURL, "Specify the url of helm repo or OCI registry (oci://) which contains cluster charts")
+	cmd.Flags().StringVar(&o.version, "version", "", "Specify the version of cluster chart to register, the latest version is used for the OCI source if not specified")
+	cmd.Flags().BoolVar(&o.plainHTTP, "plain-http", false, "Use insecure HTTP connections to pull the chart from the OCI registry")
+	cmd.Flags().BoolVar(&o.watch, "watch", false, "Watch the local source and re-register the cluster type when it changes")
 
 	return cmd
 }
@@ -128,22 +155,64 @@ func (o *registerOption) validate() error {
 		return fmt.Errorf("cluster type %s is not appropriate as a subcommand", o.clusterType.String())
 	}
 
-	if o.isSourceMethod() {
+	// the cluster chart in the OCI registry is pulled by the reference
+	if !o.isSourceMethod() && registry.IsOCI(o.repo) {
+		o.source = fmt.Sprintf("%s/%s-cluster", strings.TrimSuffix(o.repo, "/"), o.engine)
+		o.cachedName = fmt.Sprintf("%s-cluster-%s.tgz", o.engine, o.version)
+	} else if o.isOCISource() {
+		// the cached name is the same as the pulled chart, it's decided by the version after pulling if the version is empty
+		o.cachedName = fmt.Sprintf("%s-%s.tgz", path.Base(o.source), o.version)
+	} else if o.isSourceMethod() {
 		if validateSource(o.source) != nil {
 			return fmt.Errorf("your entered `--source` %s, which is neither a URL nor a file that can be found locally", o.source)
 		}
 		o.cachedName = filepath.Base(o.source)
+		if o.isChartDir() {
+			o.cachedName = fmt.Sprintf("%s-local.tgz", o.clusterType)
+			if abs, err := filepath.Abs(o.source); err == nil {
+				o.source = abs
+			}
+		}
 	} else {
 		o.cachedName = fmt.Sprintf("%s-cluster-%s.tgz", o.engine, o.version)
 	}
 
+	if o.watch && !o.isLocalSource() {
+		return fmt.Errorf("--watch only supports the local file or chart directory specified by `--source`")
+	}
 	return nil
 }
 
 func (o *registerOption) run() error {
+	// pull the OCI chart first, the version of the chart is unknown before pulling if it's not specified
+	var ociChartPath string
+	if o.isOCISource() {
+		tempDir, err := os.MkdirTemp("", "kbcli-oci-chart")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tempDir)
+		if ociChartPath, err = helm.DownloadOCIChart(o.source, o.version, tempDir, o.plainHTTP); err != nil {
+			return fmt.Errorf("failed to pull chart %s: %w", o.source, err)
+		}
+		if o.version == "" {
+			o.cachedName = filepath.Base(ociChartPath)
+		}
+	}
+
 	localChartPath := filepath.Join(cluster.CliChartsCacheDir, o.cachedName)
+	// the previous chart is restored if the new one is invalid
+	previous, _ := os.ReadFile(localChartPath)
 	if o.isSourceMethod() {
-		if govalidator.IsURL(o.source) {
+		if ociChartPath != "" {
+			if err := copyFile(ociChartPath, localChartPath); err != nil {
+				return err
+			}
+		} else if o.isChartDir() {
+			if err := packageChartDir(o.source, localChartPath); err != nil {
+				return err
+			}
+		} else if govalidator.IsURL(o.source) {
 			// source is URL
 			chartsDownloader, err := helm.NewDownloader(helm.NewConfig("default", "", "", false))
 			if err != nil {
@@ -186,7 +255,11 @@ func (o *registerOption) run() error {
 		ChartName: o.cachedName,
 	}
 	if validated, err := instance.ValidateChartSchema(); !validated {
-		_ = os.Remove(localChartPath)
+		if previous != nil {
+			_ = os.WriteFile(localChartPath, previous, 0666)
+		} else {
+			_ = os.Remove(localChartPath)
+		}
 		return err
 	}
 	// register this chart into Cluster_types
@@ -209,6 +282,111 @@ func (o *registerOption) isSourceMethod() bool {
 	return o.source != ""
 }
 
+func (o *registerOption) isOCISource() bool {
+	return registry.IsOCI(o.source)
+}
+
+func (o *registerOption) isChartDir() bool {
+	fi, err := os.Stat(o.source)
+	return err == nil && fi.IsDir()
+}
+
+func (o *registerOption) isLocalSource() bool {
+	if !o.isSourceMethod() {
+		return false
+	}
+	_, err := os.Stat(o.source)
+	return err == nil
+}
+
+// packageChartDir packages the chart directory into the chart archive dest
+func packageChartDir(dir, dest string) error {
+	c, err := loader.LoadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to load chart directory %s: %w", dir, err)
+	}
+	tempDir, err := os.MkdirTemp("", "kbcli-chart")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+	archive, err := chartutil.Save(c, tempDir)
+	if err != nil {
+		return fmt.Errorf("failed to package chart directory %s: %w", dir, err)
+	}
+	return copyFile(archive, dest)
+}
+
+// watchSource re-registers the cluster type when the local source changes until ctx is done,
+// the failures are reported and the previous registered chart is kept.
+func (o *registerOption) watchSource(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err = addWatchPaths(watcher, o.source); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Watching %s for changes, press Ctrl+C to stop\n", o.source)
+
+	const debounce = 300 * time.Millisecond
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !o.isChartDir() && filepath.Clean(event.Name) != filepath.Clean(o.source) {
+				continue
+			}
+			// watch the new sub directories of the chart
+			if event.Has(fsnotify.Create) {
+				if fi, err := os.Stat(event.Name); err == nil && fi.IsDir() {
+					_ = addWatchPaths(watcher, event.Name)
+				}
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(o.ErrOut, "watch error: %v\n", err)
+		case <-timer.C:
+			if err = o.run(); err != nil {
+				fmt.Fprintf(o.ErrOut, "%s failed to re-register cluster type %s: %v\n", time.Now().Format(time.TimeOnly), o.clusterType, err)
+				continue
+			}
+			fmt.Fprintf(o.Out, "%s cluster type %s is re-registered\n", time.Now().Format(time.TimeOnly), o.clusterType)
+		}
+	}
+}
+
+// addWatchPaths watches the directory and its sub directories, or the parent directory of the file
+func addWatchPaths(watcher *fsnotify.Watcher, root string) error {
+	fi, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return watcher.Add(filepath.Dir(root))
+	}
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		if p != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return watcher.Add(p)
+	})
+}
+
 func copyFile(src, dest string) error {
 	if src == dest {
 		return nil
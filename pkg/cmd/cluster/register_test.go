@@ -80,6 +80,31 @@ var _ = Describe("cluster register", func() {
 		Expect(o.validate()).Should(Succeed())
 	})
 
+	It("test validate OCI source and repo", func() {
+		o := &registerOption{
+			Factory:     tf,
+			IOStreams:   streams,
+			clusterType: "mysql",
+			source:      "oci://registry.example.com/charts/mysql-cluster",
+			version:     "1.0.0",
+		}
+		Expect(o.validate()).Should(Succeed())
+		Expect(o.isOCISource()).Should(BeTrue())
+		Expect(o.cachedName).Should(Equal("mysql-cluster-1.0.0.tgz"))
+
+		o = &registerOption{
+			Factory:     tf,
+			IOStreams:   streams,
+			clusterType: "mysql",
+			engine:      "mysql",
+			repo:        "oci://registry.example.com/charts/",
+			version:     "1.0.0",
+		}
+		Expect(o.validate()).Should(Succeed())
+		Expect(o.source).Should(Equal("oci://registry.example.com/charts/mysql-cluster"))
+		Expect(o.cachedName).Should(Equal("mysql-cluster-1.0.0.tgz"))
+	})
+
 	It("test copy file", func() {
 		Expect(copyFile(tempLocalPath, tempLocalPath)).Should(Succeed())
 		Expect(copyFile("bad local path", tempLocalPath)).Should(HaveOccurred())
@@ -89,6 +114,48 @@ var _ = Describe("cluster register", func() {
 		os.Remove(filepath.Join(os.TempDir(), "fake-other.tgz"))
 	})
 
+	It("test package chart directory", func() {
+		chartDir, err := os.MkdirTemp("", "kbcli-chart-dir")
+		Expect(err).Should(Succeed())
+		defer os.RemoveAll(chartDir)
+		Expect(os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte("apiVersion: v2\nname: mycluster\nversion: 0.1.0\n"), 0666)).Should(Succeed())
+
+		o := &registerOption{
+			Factory:     tf,
+			IOStreams:   streams,
+			clusterType: "mycluster",
+			source:      chartDir,
+			watch:       true,
+		}
+		Expect(o.validate()).Should(Succeed())
+		Expect(o.cachedName).Should(Equal("mycluster-local.tgz"))
+
+		dest := filepath.Join(os.TempDir(), o.cachedName)
+		defer os.Remove(dest)
+		Expect(packageChartDir(chartDir, dest)).Should(Succeed())
+		Expect(dest).Should(BeAnExistingFile())
+		Expect(packageChartDir(filepath.Join(chartDir, "not-exist"), dest)).Should(HaveOccurred())
+	})
+
+	It("test watch only supports local source", func() {
+		o := &registerOption{
+			Factory:     tf,
+			IOStreams:   streams,
+			clusterType: "mysql",
+			version:     "0.9.0",
+			repo:        types.ClusterChartsRepoURL,
+			engine:      "mysql",
+			watch:       true,
+		}
+		Expect(o.validate()).Should(HaveOccurred())
+	})
+
+	It("test unregister and list-types commands", func() {
+		Expect(newUnregisterCmd(streams)).ShouldNot(BeNil())
+		Expect(newListTypesCmd(streams)).ShouldNot(BeNil())
+		Expect(cluster.UnregisterClusterType("not-registered-type")).Should(HaveOccurred())
+	})
+
 	Context("test register cluster chart", func() {
 		var (
 			source  = "https://github.com/apecloud/helm-charts/releases/download/apecloud-mysql-cluster-1.0.0/apecloud-mysql-cluster-1.0.0.tgz"
@@ -0,0 +1,558 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var topologyExample = templates.Examples(`
+		# show how the replicas of a cluster are spread across regions, zones and nodes
+		kbcli cluster topology mycluster
+
+		# show the topology of all clusters in current namespace
+		kbcli cluster topology --all
+
+		# show the topology of all clusters in all namespaces
+		kbcli cluster topology --all -A
+
+		# exit with a non-zero code if any failure domain risk or scheduling policy violation is found
+		kbcli cluster topology --all --check`)
+
+const (
+	// failure domain risks
+	riskNone       = "OK"
+	riskLoseQuorum = "LoseQuorum"
+	riskLoseAll    = "LoseAll"
+	riskUnknown    = "Unknown"
+
+	// scheduling policy placement status
+	placementSatisfied    = "Satisfied"
+	placementNotSatisfied = "NotSatisfied"
+	placementViolated     = "Violated"
+	placementUnknown      = "Unknown"
+)
+
+type TopologyOptions struct {
+	factory       cmdutil.Factory
+	client        clientset.Interface
+	dynamic       dynamic.Interface
+	namespace     string
+	names         []string
+	all           bool
+	allNamespaces bool
+	check         bool
+
+	genericiooptions.IOStreams
+}
+
+// componentTopology is the placement of a component's replicas across failure domains
+type componentTopology struct {
+	cluster   string
+	namespace string
+	component string
+	replicas  int
+	// unscheduled is the number of replicas that have not been placed on a node
+	unscheduled int
+	regions     map[string]int
+	zones       map[string]int
+	nodes       map[string]int
+	nodeRisk    string
+	zoneRisk    string
+	policies    []placementPolicy
+}
+
+// placementPolicy is a topology key declared by the component's scheduling policy and
+// whether the actual placement honors it
+type placementPolicy struct {
+	topologyKey string
+	required    bool
+	status      string
+}
+
+func NewTopologyCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &TopologyOptions{
+		factory:   f,
+		IOStreams: streams,
+	}
+	cmd := &cobra.Command{
+		Use:               "topology [NAME | --all]",
+		Short:             "Show how cluster replicas are spread across regions, zones and nodes, and report failure domain risks.",
+		Example:           topologyExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().BoolVar(&o.all, "all", false, "Show the topology of all clusters")
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "If present, show the topology of clusters across all namespaces, only works with --all")
+	cmd.Flags().BoolVar(&o.check, "check", false, "Exit with a non-zero code if any failure domain risk or scheduling policy violation is found")
+	return cmd
+}
+
+func (o *TopologyOptions) complete(args []string) error {
+	var err error
+	o.names = args
+	if o.client, err = o.factory.KubernetesClientSet(); err != nil {
+		return err
+	}
+	if o.dynamic, err = o.factory.DynamicClient(); err != nil {
+		return err
+	}
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *TopologyOptions) validate() error {
+	if o.all && len(o.names) > 0 {
+		return fmt.Errorf("cannot specify cluster names with --all")
+	}
+	if !o.all && len(o.names) == 0 {
+		return fmt.Errorf("cluster name should be specified or use --all")
+	}
+	if o.allNamespaces && !o.all {
+		return fmt.Errorf("--all-namespaces only works with --all")
+	}
+	return nil
+}
+
+func (o *TopologyOptions) run() error {
+	clusters, err := o.getClusters()
+	if err != nil {
+		return err
+	}
+	if len(clusters) == 0 {
+		fmt.Fprintln(o.Out, "No cluster found")
+		return nil
+	}
+
+	// the domains of all nodes are needed to check the spread of the replicas,
+	// including the domains without any replica
+	nodes, err := o.listNodes()
+	if err != nil {
+		return err
+	}
+
+	var topologies []*componentTopology
+	for _, c := range clusters {
+		getter := cluster.ObjectsGetter{
+			Client:    o.client,
+			Dynamic:   o.dynamic,
+			Name:      c.Name,
+			Namespace: c.Namespace,
+			GetOptions: cluster.GetOptions{
+				WithPod: cluster.Need,
+			},
+		}
+		objs, err := getter.Get()
+		if err != nil {
+			return err
+		}
+		topologies = append(topologies, buildComponentTopologies(objs, nodes)...)
+	}
+
+	printTopologies(topologies, o.Out)
+	if o.check {
+		if n := countTopologyProblems(topologies); n > 0 {
+			return fmt.Errorf("found %d failure domain risk(s) or scheduling policy violation(s)", n)
+		}
+	}
+	return nil
+}
+
+func (o *TopologyOptions) listNodes() ([]*corev1.Node, error) {
+	list, err := o.client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*corev1.Node, 0, len(list.Items))
+	for i := range list.Items {
+		nodes = append(nodes, &list.Items[i])
+	}
+	return nodes, nil
+}
+
+// getClusters returns the clusters specified by the names or all the clusters
+func (o *TopologyOptions) getClusters() ([]metav1.ObjectMeta, error) {
+	var clusters []metav1.ObjectMeta
+	if !o.all {
+		for _, name := range o.names {
+			clusters = append(clusters, metav1.ObjectMeta{Name: name, Namespace: o.namespace})
+		}
+		return clusters, nil
+	}
+
+	namespace := o.namespace
+	if o.allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	list, err := o.dynamic.Resource(types.ClusterGVR()).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range list.Items {
+		clusters = append(clusters, metav1.ObjectMeta{Name: item.GetName(), Namespace: item.GetNamespace()})
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Namespace != clusters[j].Namespace {
+			return clusters[i].Namespace < clusters[j].Namespace
+		}
+		return clusters[i].Name < clusters[j].Name
+	})
+	return clusters, nil
+}
+
+// buildComponentTopologies groups the instances of a cluster by component and computes
+// their placement across failure domains, the nodes are all the nodes in the kubernetes cluster.
+func buildComponentTopologies(objs *cluster.ClusterObjects, nodes []*corev1.Node) []*componentTopology {
+	if objs.Pods == nil {
+		return nil
+	}
+
+	podNodes := map[string]string{}
+	for _, pod := range objs.Pods.Items {
+		podNodes[pod.Name] = pod.Spec.NodeName
+	}
+
+	var (
+		names      []string
+		topologies = map[string]*componentTopology{}
+		podsOfComp = map[string][]string{}
+	)
+	for _, ins := range objs.GetInstanceInfo() {
+		t, ok := topologies[ins.Component]
+		if !ok {
+			t = &componentTopology{
+				cluster:   objs.Cluster.Name,
+				namespace: objs.Cluster.Namespace,
+				component: ins.Component,
+				regions:   map[string]int{},
+				zones:     map[string]int{},
+				nodes:     map[string]int{},
+			}
+			topologies[ins.Component] = t
+			names = append(names, ins.Component)
+		}
+		t.replicas++
+		nodeName := podNodes[ins.Name]
+		if nodeName == "" {
+			t.unscheduled++
+			continue
+		}
+		t.nodes[nodeName]++
+		t.zones[ins.AZ]++
+		t.regions[ins.Region]++
+		podsOfComp[ins.Component] = append(podsOfComp[ins.Component], nodeName)
+	}
+
+	sort.Strings(names)
+	var result []*componentTopology
+	for _, name := range names {
+		t := topologies[name]
+		t.nodeRisk = failureDomainRisk(t.replicas, t.nodes)
+		t.zoneRisk = failureDomainRisk(t.replicas, t.zones)
+		if spec := getTopologyComponentSpec(objs.Cluster, name); spec != nil {
+			t.policies = checkPlacementPolicies(spec.SchedulingPolicy, podsOfComp[name], nodes)
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// getTopologyComponentSpec returns the component spec of the component or sharding displayed
+// in the instance info.
+func getTopologyComponentSpec(c *kbappsv1.Cluster, compName string) *kbappsv1.ClusterComponentSpec {
+	if i := strings.Index(compName, "("); i > 0 {
+		shardingName := compName[:i]
+		for j := range c.Spec.Shardings {
+			if c.Spec.Shardings[j].Name == shardingName {
+				return &c.Spec.Shardings[j].Template
+			}
+		}
+		return nil
+	}
+	for j := range c.Spec.ComponentSpecs {
+		if c.Spec.ComponentSpecs[j].Name == compName {
+			return &c.Spec.ComponentSpecs[j]
+		}
+	}
+	return nil
+}
+
+// failureDomainRisk checks what happens to a component when the failure domain holding
+// the most replicas goes down.
+func failureDomainRisk(replicas int, domains map[string]int) string {
+	if replicas == 0 {
+		return riskNone
+	}
+	// the node is not labeled with the failure domain
+	if _, ok := domains[types.None]; ok {
+		return riskUnknown
+	}
+	maxInDomain := 0
+	for _, n := range domains {
+		if n > maxInDomain {
+			maxInDomain = n
+		}
+	}
+	remaining := replicas - maxInDomain
+	switch {
+	case remaining <= 0:
+		return riskLoseAll
+	case remaining < replicas/2+1:
+		return riskLoseQuorum
+	default:
+		return riskNone
+	}
+}
+
+// checkPlacementPolicies compares the pod anti-affinity and topology spread constraints
+// declared by the scheduling policy with the actual placement of the replicas.
+// An anti-affinity term is honored when no domain holds more than one replica, a spread
+// constraint is honored when the skew between the domains does not exceed its maxSkew.
+func checkPlacementPolicies(policy *kbappsv1.SchedulingPolicy, podNodes []string, nodes []*corev1.Node) []placementPolicy {
+	if policy == nil {
+		return nil
+	}
+	candidates := schedulableNodes(policy, nodes)
+
+	var policies []placementPolicy
+	addPolicy := func(key string, required bool, check func(domains map[string]int) bool) {
+		if key == "" {
+			return
+		}
+		p := placementPolicy{topologyKey: key, required: required, status: placementSatisfied}
+		domains := placementDomains(key, podNodes, nodes, candidates)
+		switch {
+		case domains == nil:
+			p.status = placementUnknown
+		case !check(domains):
+			p.status = placementNotSatisfied
+			if p.required {
+				p.status = placementViolated
+			}
+		}
+		policies = append(policies, p)
+	}
+
+	antiAffinityCheck := func(domains map[string]int) bool {
+		for _, n := range domains {
+			if n > 1 {
+				return false
+			}
+		}
+		return true
+	}
+	if policy.Affinity != nil && policy.Affinity.PodAntiAffinity != nil {
+		for _, term := range policy.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			addPolicy(term.TopologyKey, true, antiAffinityCheck)
+		}
+		for _, term := range policy.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			addPolicy(term.PodAffinityTerm.TopologyKey, false, antiAffinityCheck)
+		}
+	}
+	for _, c := range policy.TopologySpreadConstraints {
+		maxSkew := int(c.MaxSkew)
+		if maxSkew < 1 {
+			maxSkew = 1
+		}
+		addPolicy(c.TopologyKey, c.WhenUnsatisfiable == corev1.DoNotSchedule, func(domains map[string]int) bool {
+			return placementSkew(domains) <= maxSkew
+		})
+	}
+	return policies
+}
+
+// schedulableNodes returns the nodes which the replicas can be scheduled to by the node selector
+// and the required node affinity of the scheduling policy, the same nodes are considered by
+// the scheduler to find the domains of the topology spread constraints.
+func schedulableNodes(policy *kbappsv1.SchedulingPolicy, nodes []*corev1.Node) []*corev1.Node {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			NodeSelector: policy.NodeSelector,
+			Affinity:     policy.Affinity,
+		},
+	}
+	affinity := nodeaffinity.GetRequiredNodeAffinity(pod)
+	var res []*corev1.Node
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		if ok, err := affinity.Match(node); err != nil || !ok {
+			continue
+		}
+		res = append(res, node)
+	}
+	return res
+}
+
+// placementDomains counts the replicas in each domain of the topology key, domains of the
+// candidate nodes without any replica are counted as zero. It returns nil if the domain of
+// a replica is unknown.
+func placementDomains(key string, podNodes []string, nodes, candidates []*corev1.Node) map[string]int {
+	domains := map[string]int{}
+	for _, node := range candidates {
+		if domain := node.Labels[key]; domain != "" {
+			domains[domain] = 0
+		}
+	}
+	for _, nodeName := range podNodes {
+		node := util.GetNodeByName(nodes, nodeName)
+		if node == nil || node.Labels[key] == "" {
+			return nil
+		}
+		domains[node.Labels[key]]++
+	}
+	return domains
+}
+
+// placementSkew returns the difference between the most and the least replicas of the domains
+func placementSkew(domains map[string]int) int {
+	first := true
+	var maxCount, minCount int
+	for _, n := range domains {
+		if first {
+			maxCount, minCount = n, n
+			first = false
+			continue
+		}
+		if n > maxCount {
+			maxCount = n
+		}
+		if n < minCount {
+			minCount = n
+		}
+	}
+	return maxCount - minCount
+}
+
+func countTopologyProblems(topologies []*componentTopology) int {
+	count := 0
+	for _, t := range topologies {
+		if t.nodeRisk == riskLoseQuorum || t.nodeRisk == riskLoseAll {
+			count++
+		}
+		if t.zoneRisk == riskLoseQuorum || t.zoneRisk == riskLoseAll {
+			count++
+		}
+		for _, p := range t.policies {
+			if p.status == placementViolated {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func printTopologies(topologies []*componentTopology, out io.Writer) {
+	tbl := newTbl(out, "Failure Domains:", "NAMESPACE", "CLUSTER", "COMPONENT", "REPLICAS", "REGIONS", "ZONES", "NODES", "NODE-FAILURE", "ZONE-FAILURE")
+	for _, t := range topologies {
+		replicas := fmt.Sprintf("%d", t.replicas)
+		if t.unscheduled > 0 {
+			replicas = fmt.Sprintf("%d (%d unscheduled)", t.replicas, t.unscheduled)
+		}
+		tbl.AddRow(t.namespace, t.cluster, t.component, replicas,
+			formatDomainCounts(t.regions), formatDomainCounts(t.zones), formatDomainCounts(t.nodes),
+			colorRisk(t.nodeRisk), colorRisk(t.zoneRisk))
+	}
+	tbl.Print()
+
+	var hasPolicy bool
+	for _, t := range topologies {
+		if len(t.policies) > 0 {
+			hasPolicy = true
+			break
+		}
+	}
+	if !hasPolicy {
+		return
+	}
+	tbl = newTbl(out, "\nScheduling Policy:", "NAMESPACE", "CLUSTER", "COMPONENT", "TOPOLOGY-KEY", "TYPE", "PLACEMENT")
+	for _, t := range topologies {
+		for _, p := range t.policies {
+			policyType := "Preferred"
+			if p.required {
+				policyType = "Required"
+			}
+			status := p.status
+			switch status {
+			case placementViolated:
+				status = printer.BoldRed(status)
+			case placementNotSatisfied:
+				status = printer.BoldYellow(status)
+			}
+			tbl.AddRow(t.namespace, t.cluster, t.component, p.topologyKey, policyType, status)
+		}
+	}
+	tbl.Print()
+}
+
+// formatDomainCounts formats the replica count of each domain, such as "zone-a:2\nzone-b:1"
+func formatDomainCounts(domains map[string]int) string {
+	if len(domains) == 0 {
+		return types.None
+	}
+	var names []string
+	for name := range domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var res []string
+	for _, name := range names {
+		res = append(res, fmt.Sprintf("%s:%d", name, domains[name]))
+	}
+	return strings.Join(res, "\n")
+}
+
+func colorRisk(risk string) string {
+	switch risk {
+	case riskLoseAll:
+		return printer.BoldRed(risk)
+	case riskLoseQuorum:
+		return printer.BoldYellow(risk)
+	default:
+		return risk
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientfake "k8s.io/client-go/rest/fake"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+)
+
+var _ = Describe("topology", func() {
+	const (
+		namespace   = "test"
+		clusterName = "test"
+	)
+
+	var (
+		streams genericiooptions.IOStreams
+		tf      *cmdtesting.TestFactory
+	)
+
+	buildNode := func(name, zone string) *corev1.Node {
+		node := &corev1.Node{}
+		node.Name = name
+		node.Labels = map[string]string{
+			corev1.LabelHostname:       name,
+			corev1.LabelTopologyRegion: "region",
+			corev1.LabelTopologyZone:   zone,
+		}
+		return node
+	}
+
+	BeforeEach(func() {
+		streams, _, _, _ = genericiooptions.NewTestIOStreams()
+		tf = testing.NewTestFactory(namespace)
+		codec := scheme.Codecs.LegacyCodec(scheme.Scheme.PrioritizedVersionsAllGroups()...)
+		httpResp := func(obj runtime.Object) *http.Response {
+			return &http.Response{StatusCode: http.StatusOK, Header: cmdtesting.DefaultHeader(), Body: cmdtesting.ObjBody(codec, obj)}
+		}
+		tf.UnstructuredClient = &clientfake.RESTClient{
+			GroupVersion:         schema.GroupVersion{Group: types.AppsAPIGroup, Version: types.AppsV1APIVersion},
+			NegotiatedSerializer: resource.UnstructuredPlusDefaultContentConfig().NegotiatedSerializer,
+			Client: clientfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+				mapping := map[string]*http.Response{
+					"/api/v1/nodes/" + testing.NodeName:         httpResp(testing.FakeNode()),
+					"/api/v1/nodes":                             httpResp(&corev1.NodeList{Items: []corev1.Node{*testing.FakeNode()}}),
+					"/api/v1/namespaces/" + namespace + "/pods": httpResp(testing.FakePods(3, namespace, clusterName)),
+				}
+				return mapping[req.URL.Path], nil
+			}),
+		}
+		tf.Client = tf.UnstructuredClient
+		tf.FakeDynamicClient = testing.FakeDynamicClient(testing.FakeCluster(clusterName, namespace))
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	It("validate", func() {
+		o := &TopologyOptions{factory: tf, IOStreams: streams}
+		Expect(o.complete(nil)).Should(Succeed())
+		Expect(o.validate()).Should(HaveOccurred())
+
+		o.all = true
+		Expect(o.validate()).Should(Succeed())
+
+		o.names = []string{clusterName}
+		Expect(o.validate()).Should(HaveOccurred())
+
+		o.all = false
+		o.allNamespaces = true
+		Expect(o.validate()).Should(HaveOccurred())
+	})
+
+	It("run", func() {
+		cmd := NewTopologyCmd(tf, streams)
+		Expect(cmd).ShouldNot(BeNil())
+
+		o := &TopologyOptions{factory: tf, IOStreams: streams}
+		Expect(o.complete([]string{clusterName})).Should(Succeed())
+		Expect(o.run()).Should(Succeed())
+
+		// all the fake pods are located on the same node
+		o.check = true
+		Expect(o.run()).Should(HaveOccurred())
+	})
+
+	It("failureDomainRisk", func() {
+		Expect(failureDomainRisk(3, map[string]int{"a": 1, "b": 1, "c": 1})).Should(Equal(riskNone))
+		Expect(failureDomainRisk(3, map[string]int{"a": 2, "b": 1})).Should(Equal(riskLoseQuorum))
+		Expect(failureDomainRisk(3, map[string]int{"a": 3})).Should(Equal(riskLoseAll))
+		Expect(failureDomainRisk(1, map[string]int{"a": 1})).Should(Equal(riskLoseAll))
+		Expect(failureDomainRisk(2, map[string]int{types.None: 2})).Should(Equal(riskUnknown))
+	})
+
+	It("buildComponentTopologies", func() {
+		c := testing.FakeCluster(clusterName, namespace)
+		c.Spec.ComponentSpecs[0].SchedulingPolicy = &kbappsv1.SchedulingPolicy{
+			Affinity: &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+						{TopologyKey: corev1.LabelHostname},
+					},
+				},
+			},
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+				{TopologyKey: corev1.LabelTopologyZone, MaxSkew: 1, WhenUnsatisfiable: corev1.ScheduleAnyway},
+			},
+		}
+		pods := testing.FakePods(3, namespace, clusterName)
+		nodes := []*corev1.Node{buildNode("node-0", "zone-a"), buildNode("node-1", "zone-a"), buildNode("node-2", "zone-b")}
+		for i := range pods.Items {
+			pods.Items[i].Spec.NodeName = fmt.Sprintf("node-%d", i)
+		}
+		objs := &cluster.ClusterObjects{Cluster: c, Pods: pods, Nodes: nodes}
+
+		topologies := buildComponentTopologies(objs, nodes)
+		Expect(topologies).Should(HaveLen(1))
+		t := topologies[0]
+		Expect(t.replicas).Should(Equal(3))
+		Expect(t.nodes).Should(HaveLen(3))
+		Expect(t.nodeRisk).Should(Equal(riskNone))
+		Expect(t.zoneRisk).Should(Equal(riskLoseQuorum))
+		Expect(t.policies).Should(HaveLen(2))
+		Expect(t.policies[0].status).Should(Equal(placementSatisfied))
+		Expect(t.policies[1].status).Should(Equal(placementSatisfied))
+		Expect(countTopologyProblems(topologies)).Should(Equal(1))
+
+		out := &bytes.Buffer{}
+		printTopologies(topologies, out)
+		Expect(out.String()).Should(ContainSubstring("zone-a:2"))
+		Expect(out.String()).Should(ContainSubstring(corev1.LabelTopologyZone))
+	})
+
+	It("checkPlacementPolicies", func() {
+		nodes := []*corev1.Node{buildNode("node-0", "zone-a"), buildNode("node-1", "zone-a"),
+			buildNode("node-2", "zone-b"), buildNode("node-3", "zone-c")}
+		spread := func(maxSkew int32, whenUnsatisfiable corev1.UnsatisfiableConstraintAction) *kbappsv1.SchedulingPolicy {
+			return &kbappsv1.SchedulingPolicy{
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{TopologyKey: corev1.LabelTopologyZone, MaxSkew: maxSkew, WhenUnsatisfiable: whenUnsatisfiable},
+				},
+			}
+		}
+
+		// zone-a:2, zone-b:1, zone-c:0
+		podNodes := []string{"node-0", "node-1", "node-2"}
+		policies := checkPlacementPolicies(spread(2, corev1.DoNotSchedule), podNodes, nodes)
+		Expect(policies).Should(HaveLen(1))
+		Expect(policies[0].status).Should(Equal(placementSatisfied))
+
+		policies = checkPlacementPolicies(spread(1, corev1.DoNotSchedule), podNodes, nodes)
+		Expect(policies[0].required).Should(BeTrue())
+		Expect(policies[0].status).Should(Equal(placementViolated))
+
+		policies = checkPlacementPolicies(spread(1, corev1.ScheduleAnyway), podNodes, nodes)
+		Expect(policies[0].status).Should(Equal(placementNotSatisfied))
+		topologies := []*componentTopology{{policies: policies}}
+		Expect(countTopologyProblems(topologies)).Should(Equal(0))
+
+		// zone-a:1, zone-b:1, zone-c:1
+		podNodes = []string{"node-0", "node-2", "node-3"}
+		policies = checkPlacementPolicies(spread(1, corev1.DoNotSchedule), podNodes, nodes)
+		Expect(policies[0].status).Should(Equal(placementSatisfied))
+
+		policies = checkPlacementPolicies(spread(1, corev1.DoNotSchedule), []string{"node-4"}, nodes)
+		Expect(policies[0].status).Should(Equal(placementUnknown))
+
+		By("the domains the replicas can not be scheduled to are ignored")
+		// zone-a:2, zone-b:1, zone-c is excluded by the node selector
+		podNodes = []string{"node-0", "node-1", "node-2"}
+		policy := spread(1, corev1.DoNotSchedule)
+		policy.NodeSelector = map[string]string{corev1.LabelTopologyRegion: "region"}
+		nodes[3].Labels[corev1.LabelTopologyRegion] = "other"
+		policies = checkPlacementPolicies(policy, podNodes, nodes)
+		Expect(policies[0].status).Should(Equal(placementSatisfied))
+
+		// zone-c is excluded as the node is cordoned
+		nodes[3].Labels[corev1.LabelTopologyRegion] = "region"
+		Expect(checkPlacementPolicies(policy, podNodes, nodes)[0].status).Should(Equal(placementViolated))
+		nodes[3].Spec.Unschedulable = true
+		Expect(checkPlacementPolicies(policy, podNodes, nodes)[0].status).Should(Equal(placementSatisfied))
+	})
+})
@@ -0,0 +1,79 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+)
+
+var clusterUnregisterExample = templates.Examples(`
+	# Unregister the cluster type mycluster and remove its cached chart
+	kbcli cluster unregister mycluster
+`)
+
+var clusterListTypesExample = templates.Examples(`
+	# List the registered cluster types
+	kbcli cluster list-types
+`)
+
+func newUnregisterCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "unregister NAME",
+		Short:   "Unregister the cluster type and remove its cached chart",
+		Example: clusterUnregisterExample,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			clusterType := cluster.ClusterType(args[0])
+			cmdutil.CheckErr(cluster.UnregisterClusterType(clusterType))
+			fmt.Fprintf(streams.Out, "cluster type %s is unregistered\n", clusterType)
+		},
+	}
+	return cmd
+}
+
+func newListTypesCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list-types",
+		Short:   "List the registered cluster types with their source, version and cache path",
+		Example: clusterListTypesExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			printClusterTypes(streams, cluster.ListClusterTypes())
+		},
+	}
+	return cmd
+}
+
+func printClusterTypes(streams genericiooptions.IOStreams, types []cluster.ClusterTypeInfo) {
+	tbl := printer.NewTablePrinter(streams.Out)
+	tbl.SetHeader("NAME", "ALIAS", "SOURCE", "VERSION", "CACHE PATH")
+	for _, t := range types {
+		tbl.AddRow(t.Name, t.Alias, t.Source, t.Version, t.CachePath)
+	}
+	tbl.Print()
+}
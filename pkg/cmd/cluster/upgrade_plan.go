@@ -0,0 +1,639 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	parametersv1alpha1 "github.com/apecloud/kubeblocks/apis/parameters/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+)
+
+var upgradePlanExample = templates.Examples(`
+		# show the reachable service versions of all components in the cluster
+		kbcli cluster upgrade-plan mycluster
+
+		# show the reachable service versions of the specified components
+		kbcli cluster upgrade-plan mycluster --components mysql
+
+		# upgrade the component to the newest compatible patch version
+		kbcli cluster upgrade mycluster --components mysql --latest-patch
+`)
+
+const (
+	versionChangeCurrent   = "Current"
+	versionChangePatch     = "Patch"
+	versionChangeMinor     = "Minor"
+	versionChangeMajor     = "Major"
+	versionChangeDowngrade = "Downgrade"
+	versionChangeUnknown   = "Unknown"
+
+	parameterKindStatic    = "static"
+	parameterKindDynamic   = "dynamic"
+	parameterKindImmutable = "immutable"
+)
+
+// upgradeTarget is a service version that the component can be upgraded to.
+type upgradeTarget struct {
+	ServiceVersion   string
+	Release          string
+	ComponentVersion string
+	// ComponentDef is the ComponentDefinition that provides the service version,
+	// it differs from the current one when the upgrade requires a ComponentDefinition change.
+	ComponentDef   string
+	CompDefChanged bool
+	ChangeType     string
+	Images         map[string]string
+}
+
+type parameterDiff struct {
+	Name string
+	File string
+	From string
+	To   string
+}
+
+type componentUpgradePlan struct {
+	Name           string
+	ComponentDef   string
+	ServiceVersion string
+	Targets        []*upgradeTarget
+	// ParameterDiffs is keyed by the target ComponentDefinition name.
+	ParameterDiffs map[string][]parameterDiff
+}
+
+type UpgradePlanOptions struct {
+	factory   cmdutil.Factory
+	dynamic   dynamic.Interface
+	namespace string
+
+	name       string
+	components []string
+	genericiooptions.IOStreams
+}
+
+func NewUpgradePlanCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &UpgradePlanOptions{factory: f, IOStreams: streams}
+	cmd := &cobra.Command{
+		Use:               "upgrade-plan NAME",
+		Short:             "Show the service versions that the cluster components can be upgraded to.",
+		Example:           upgradePlanExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(args))
+			util.CheckErr(o.run())
+		},
+	}
+	flags.AddComponentsFlag(f, cmd, &o.components, "Component names to show the upgrade plan, all components are shown if not specified")
+	return cmd
+}
+
+func (o *UpgradePlanOptions) complete(args []string) error {
+	var err error
+	if len(args) == 0 {
+		return makeMissingClusterNameErr()
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only support to show the upgrade plan of one cluster")
+	}
+	o.name = args[0]
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	o.dynamic, err = o.factory.DynamicClient()
+	return err
+}
+
+func (o *UpgradePlanOptions) run() error {
+	clusterObj, err := cluster.GetClusterByName(o.dynamic, o.name, o.namespace)
+	if err != nil {
+		return err
+	}
+	plans, err := buildUpgradePlans(o.dynamic, clusterObj, o.components)
+	if err != nil {
+		return err
+	}
+	if err = addParameterDiffs(o.dynamic, plans); err != nil {
+		return err
+	}
+	printUpgradePlans(plans, o.Out)
+	return nil
+}
+
+// buildUpgradePlans builds the upgrade plans of the specified components, all components are included if compNames is empty.
+func buildUpgradePlans(dynamic dynamic.Interface, clusterObj *kbappsv1.Cluster, compNames []string) ([]*componentUpgradePlan, error) {
+	cmpvs, err := listComponentVersions(dynamic)
+	if err != nil {
+		return nil, err
+	}
+	compDefs, err := listComponentDefinitions(dynamic)
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []*componentUpgradePlan
+	buildPlan := func(name string, spec kbappsv1.ClusterComponentSpec) error {
+		if len(compNames) > 0 && !slices.Contains(compNames, name) {
+			return nil
+		}
+		compDef, serviceVersion := getResolvedComponent(dynamic, clusterObj, name, spec)
+		plans = append(plans, buildComponentUpgradePlan(name, compDef, serviceVersion, cmpvs, compDefs))
+		return nil
+	}
+	for _, spec := range clusterObj.Spec.ComponentSpecs {
+		if err = buildPlan(spec.Name, spec); err != nil {
+			return nil, err
+		}
+	}
+	for _, sharding := range clusterObj.Spec.Shardings {
+		if err = buildPlan(sharding.Name, sharding.Template); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range compNames {
+		if cluster.GetComponentSpec(clusterObj, name) == nil {
+			return nil, fmt.Errorf(`can not found the component "%s" in cluster "%s"`, name, clusterObj.Name)
+		}
+	}
+	return plans, nil
+}
+
+// addParameterDiffs adds the parameter differences between the current ComponentDefinition and the
+// target ComponentDefinitions to the plans.
+func addParameterDiffs(dynamic dynamic.Interface, plans []*componentUpgradePlan) error {
+	compDefs, err := listComponentDefinitions(dynamic)
+	if err != nil {
+		return err
+	}
+	paramsGetter := &compDefParametersGetter{dynamic: dynamic, cache: map[string]map[string]parameterInfo{}}
+	for _, plan := range plans {
+		for _, t := range plan.Targets {
+			if !t.CompDefChanged {
+				continue
+			}
+			if _, ok := plan.ParameterDiffs[t.ComponentDef]; ok {
+				continue
+			}
+			diffs, err := paramsGetter.diff(compDefs[plan.ComponentDef], compDefs[t.ComponentDef])
+			if err != nil {
+				return err
+			}
+			plan.ParameterDiffs[t.ComponentDef] = diffs
+		}
+	}
+	return nil
+}
+
+// buildComponentUpgradePlan lists the releases provided by the ComponentVersions which are compatible with
+// the current ComponentDefinition of the component. If a release is only compatible with other ComponentDefinitions,
+// the latest one of them is chosen as the target ComponentDefinition.
+func buildComponentUpgradePlan(name, compDef, serviceVersion string,
+	cmpvs []*kbappsv1.ComponentVersion,
+	compDefs map[string]*kbappsv1.ComponentDefinition) *componentUpgradePlan {
+	plan := &componentUpgradePlan{
+		Name:           name,
+		ComponentDef:   compDef,
+		ServiceVersion: serviceVersion,
+		ParameterDiffs: map[string][]parameterDiff{},
+	}
+	targets := map[string]*upgradeTarget{}
+	for _, cmpv := range cmpvs {
+		if !compatibleWithComponentVersion(cmpv, compDef) {
+			continue
+		}
+		releases := map[string]kbappsv1.ComponentVersionRelease{}
+		for _, r := range cmpv.Spec.Releases {
+			releases[r.Name] = r
+		}
+		for _, rule := range cmpv.Spec.CompatibilityRules {
+			targetCompDef := compDef
+			if !cluster.CompatibleComponentDefs(rule.CompDefs, compDef) {
+				if targetCompDef = latestCompatibleCompDef(rule.CompDefs, compDefs); targetCompDef == "" {
+					continue
+				}
+			}
+			for _, releaseName := range rule.Releases {
+				release, ok := releases[releaseName]
+				if !ok || release.ServiceVersion == serviceVersion {
+					continue
+				}
+				// prefer the release that does not require to change the ComponentDefinition
+				if t, ok := targets[release.ServiceVersion]; ok && (!t.CompDefChanged || targetCompDef != compDef) {
+					continue
+				}
+				targets[release.ServiceVersion] = &upgradeTarget{
+					ServiceVersion:   release.ServiceVersion,
+					Release:          release.Name,
+					ComponentVersion: cmpv.Name,
+					ComponentDef:     targetCompDef,
+					CompDefChanged:   targetCompDef != compDef,
+					ChangeType:       getVersionChangeType(serviceVersion, release.ServiceVersion),
+					Images:           release.Images,
+				}
+			}
+		}
+	}
+	for _, t := range targets {
+		plan.Targets = append(plan.Targets, t)
+	}
+	sort.SliceStable(plan.Targets, func(i, j int) bool {
+		return compareServiceVersion(plan.Targets[i].ServiceVersion, plan.Targets[j].ServiceVersion) < 0
+	})
+	return plan
+}
+
+// compDefServiceVersions returns the service versions provided by the ComponentVersions for the ComponentDefinition.
+func compDefServiceVersions(cmpvs []*kbappsv1.ComponentVersion, compDef string) []string {
+	var versions []string
+	for _, cmpv := range cmpvs {
+		releases := map[string]string{}
+		for _, r := range cmpv.Spec.Releases {
+			releases[r.Name] = r.ServiceVersion
+		}
+		for _, rule := range cmpv.Spec.CompatibilityRules {
+			if !cluster.CompatibleComponentDefs(rule.CompDefs, compDef) {
+				continue
+			}
+			for _, releaseName := range rule.Releases {
+				if v, ok := releases[releaseName]; ok && !slices.Contains(versions, v) {
+					versions = append(versions, v)
+				}
+			}
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return compareServiceVersion(versions[i], versions[j]) < 0
+	})
+	return versions
+}
+
+// compatibleWithComponentVersion checks whether the ComponentVersion has any rule that matches the ComponentDefinition.
+func compatibleWithComponentVersion(cmpv *kbappsv1.ComponentVersion, compDef string) bool {
+	for _, rule := range cmpv.Spec.CompatibilityRules {
+		if cluster.CompatibleComponentDefs(rule.CompDefs, compDef) {
+			return true
+		}
+	}
+	return false
+}
+
+// latestCompatibleCompDef returns the latest existing ComponentDefinition matched the patterns.
+func latestCompatibleCompDef(patterns []string, compDefs map[string]*kbappsv1.ComponentDefinition) string {
+	var names []string
+	for name := range compDefs {
+		if cluster.CompatibleComponentDefs(patterns, name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return compareCompDefName(names[i], names[j]) < 0
+	})
+	return names[len(names)-1]
+}
+
+// compareCompDefName compares the ComponentDefinition names by the semantic versions at the end of the names,
+// such as "mysql-8.0-1.0.0" and "mysql-8.0-1.0.10", and falls back to the string comparison.
+func compareCompDefName(a, b string) int {
+	aPrefix, aVer := splitCompDefName(a)
+	bPrefix, bVer := splitCompDefName(b)
+	if aVer == nil || bVer == nil || aPrefix != bPrefix {
+		return strings.Compare(a, b)
+	}
+	return aVer.Compare(bVer)
+}
+
+// splitCompDefName splits the ComponentDefinition name into the prefix and the semantic version at the end.
+func splitCompDefName(name string) (string, *semver.Version) {
+	for i := strings.LastIndex(name, "-"); i > 0; i = strings.LastIndex(name[:i], "-") {
+		if ver, err := semver.StrictNewVersion(name[i+1:]); err == nil {
+			return name[:i], ver
+		}
+	}
+	return name, nil
+}
+
+// latestPatchTarget returns the newest target that has the same major and minor version with the current service version.
+func latestPatchTarget(plan *componentUpgradePlan) *upgradeTarget {
+	var latest *upgradeTarget
+	for _, t := range plan.Targets {
+		if t.ChangeType != versionChangePatch {
+			continue
+		}
+		if latest == nil || compareServiceVersion(latest.ServiceVersion, t.ServiceVersion) < 0 ||
+			(latest.ServiceVersion == t.ServiceVersion && latest.CompDefChanged && !t.CompDefChanged) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+func getVersionChangeType(from, to string) string {
+	fromVer, err1 := semver.NewVersion(from)
+	toVer, err2 := semver.NewVersion(to)
+	switch {
+	case err1 != nil || err2 != nil:
+		return versionChangeUnknown
+	case toVer.Equal(fromVer):
+		return versionChangeCurrent
+	case toVer.LessThan(fromVer):
+		return versionChangeDowngrade
+	case toVer.Major() != fromVer.Major():
+		return versionChangeMajor
+	case toVer.Minor() != fromVer.Minor():
+		return versionChangeMinor
+	default:
+		return versionChangePatch
+	}
+}
+
+func compareServiceVersion(a, b string) int {
+	aVer, err1 := semver.NewVersion(a)
+	bVer, err2 := semver.NewVersion(b)
+	if err1 != nil || err2 != nil {
+		return strings.Compare(a, b)
+	}
+	return aVer.Compare(bVer)
+}
+
+// getResolvedComponent gets the ComponentDefinition and the service version resolved by the Component object,
+// the Components of a sharding are looked up by the sharding label. The values specified in the cluster are used
+// if the Component is not found, since the cluster may refer to the ComponentDefinition by a prefix or regex.
+func getResolvedComponent(dynamic dynamic.Interface, clusterObj *kbappsv1.Cluster, compName string,
+	spec kbappsv1.ClusterComponentSpec) (string, string) {
+	compDef, serviceVersion := spec.ComponentDef, spec.ServiceVersion
+	comp := &kbappsv1.Component{}
+	var err error
+	if cluster.IsShardingComponent(clusterObj, compName) {
+		var objs *unstructured.UnstructuredList
+		objs, err = dynamic.Resource(types.ComponentGVR()).Namespace(clusterObj.Namespace).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s,%s=%s", constant.AppInstanceLabelKey, clusterObj.Name, constant.KBAppShardingNameLabelKey, compName),
+		})
+		switch {
+		case err != nil:
+		case len(objs.Items) == 0:
+			err = fmt.Errorf("no component of sharding %s found", compName)
+		default:
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(objs.Items[0].Object, comp)
+		}
+	} else {
+		err = util.GetK8SClientObject(dynamic, comp, types.ComponentGVR(), clusterObj.Namespace,
+			fmt.Sprintf("%s-%s", clusterObj.Name, compName))
+	}
+	if err == nil {
+		if comp.Spec.CompDef != "" {
+			compDef = comp.Spec.CompDef
+		}
+		if comp.Spec.ServiceVersion != "" {
+			serviceVersion = comp.Spec.ServiceVersion
+		}
+	}
+	if serviceVersion == "" {
+		serviceVersion = types.None
+	}
+	return compDef, serviceVersion
+}
+
+func listComponentVersions(dynamic dynamic.Interface) ([]*kbappsv1.ComponentVersion, error) {
+	objs, err := dynamic.Resource(types.ComponentVersionsGVR()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var cmpvs []*kbappsv1.ComponentVersion
+	for i := range objs.Items {
+		cmpv := &kbappsv1.ComponentVersion{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(objs.Items[i].Object, cmpv); err != nil {
+			return nil, err
+		}
+		cmpvs = append(cmpvs, cmpv)
+	}
+	return cmpvs, nil
+}
+
+func listComponentDefinitions(dynamic dynamic.Interface) (map[string]*kbappsv1.ComponentDefinition, error) {
+	objs, err := dynamic.Resource(types.CompDefGVR()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	compDefs := map[string]*kbappsv1.ComponentDefinition{}
+	for i := range objs.Items {
+		compDef := &kbappsv1.ComponentDefinition{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(objs.Items[i].Object, compDef); err != nil {
+			return nil, err
+		}
+		compDefs[compDef.Name] = compDef
+	}
+	return compDefs, nil
+}
+
+type parameterInfo struct {
+	file string
+	kind string
+}
+
+// compDefParametersGetter gets the parameters declared by the ParametersDefinitions of the ComponentDefinition.
+type compDefParametersGetter struct {
+	dynamic dynamic.Interface
+	pcrs    []*parametersv1alpha1.ParamConfigRenderer
+	cache   map[string]map[string]parameterInfo
+}
+
+func (g *compDefParametersGetter) diff(from, to *kbappsv1.ComponentDefinition) ([]parameterDiff, error) {
+	fromParams, err := g.get(from)
+	if err != nil {
+		return nil, err
+	}
+	toParams, err := g.get(to)
+	if err != nil {
+		return nil, err
+	}
+	return diffParameters(fromParams, toParams), nil
+}
+
+func (g *compDefParametersGetter) get(compDef *kbappsv1.ComponentDefinition) (map[string]parameterInfo, error) {
+	if compDef == nil {
+		return nil, nil
+	}
+	if params, ok := g.cache[compDef.Name]; ok {
+		return params, nil
+	}
+	if g.pcrs == nil {
+		objs, err := g.dynamic.Resource(types.ParamConfigRendererGVR()).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		g.pcrs = []*parametersv1alpha1.ParamConfigRenderer{}
+		for i := range objs.Items {
+			pcr := &parametersv1alpha1.ParamConfigRenderer{}
+			if err = runtime.DefaultUnstructuredConverter.FromUnstructured(objs.Items[i].Object, pcr); err != nil {
+				return nil, err
+			}
+			g.pcrs = append(g.pcrs, pcr)
+		}
+	}
+	params := map[string]parameterInfo{}
+	for _, pcr := range g.pcrs {
+		if pcr.Spec.ComponentDef != compDef.Name ||
+			(pcr.Spec.ServiceVersion != "" && pcr.Spec.ServiceVersion != compDef.Spec.ServiceVersion) {
+			continue
+		}
+		for _, pdName := range pcr.Spec.ParametersDefs {
+			pd := &parametersv1alpha1.ParametersDefinition{}
+			if err := util.GetK8SClientObject(g.dynamic, pd, types.ParametersDefinitionGVR(), "", pdName); err != nil {
+				return nil, err
+			}
+			for k, v := range getDefinedParameters(pd) {
+				params[k] = v
+			}
+		}
+	}
+	g.cache[compDef.Name] = params
+	return params, nil
+}
+
+// getDefinedParameters returns the parameters declared in the ParametersDefinition with their kinds.
+func getDefinedParameters(pd *parametersv1alpha1.ParametersDefinition) map[string]parameterInfo {
+	params := map[string]parameterInfo{}
+	add := func(kind string, names []string) {
+		for _, name := range names {
+			params[name] = parameterInfo{file: pd.Spec.FileName, kind: kind}
+		}
+	}
+	if pd.Spec.ParametersSchema != nil && pd.Spec.ParametersSchema.SchemaInJSON != nil {
+		var names []string
+		for name := range pd.Spec.ParametersSchema.SchemaInJSON.Properties["spec"].Properties {
+			names = append(names, name)
+		}
+		add(parameterKindDynamic, names)
+	}
+	add(parameterKindDynamic, pd.Spec.DynamicParameters)
+	add(parameterKindStatic, pd.Spec.StaticParameters)
+	add(parameterKindImmutable, pd.Spec.ImmutableParameters)
+	return params
+}
+
+func diffParameters(from, to map[string]parameterInfo) []parameterDiff {
+	var diffs []parameterDiff
+	for name, f := range from {
+		t, ok := to[name]
+		switch {
+		case !ok:
+			diffs = append(diffs, parameterDiff{Name: name, File: f.file, From: f.kind})
+		case f.kind != t.kind || f.file != t.file:
+			diffs = append(diffs, parameterDiff{Name: name, File: t.file, From: f.kind, To: t.kind})
+		}
+	}
+	for name, t := range to {
+		if _, ok := from[name]; !ok {
+			diffs = append(diffs, parameterDiff{Name: name, File: t.file, To: t.kind})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].File == diffs[j].File {
+			return diffs[i].Name < diffs[j].Name
+		}
+		return diffs[i].File < diffs[j].File
+	})
+	return diffs
+}
+
+func printUpgradePlans(plans []*componentUpgradePlan, out io.Writer) {
+	for _, plan := range plans {
+		fmt.Fprintf(out, "Component: %s\n", plan.Name)
+		fmt.Fprintf(out, "  Component Definition:\t%s\n", plan.ComponentDef)
+		fmt.Fprintf(out, "  Service Version:\t%s\n", plan.ServiceVersion)
+		if len(plan.Targets) == 0 {
+			fmt.Fprintf(out, "\nNo reachable service versions found in the compatible ComponentVersions.\n\n")
+			continue
+		}
+		tbl := newTbl(out, "\nReachable Service Versions:", "SERVICE-VERSION", "CHANGE", "RELEASE", "COMPONENT-DEF", "IMAGES")
+		for _, t := range plan.Targets {
+			compDef := t.ComponentDef
+			if t.CompDefChanged {
+				compDef = printer.BoldYellow(compDef + " (required)")
+			}
+			tbl.AddRow(t.ServiceVersion, colorVersionChange(t.ChangeType), t.Release, compDef, formatImages(t.Images))
+		}
+		tbl.Print()
+
+		var compDefNames []string
+		for name := range plan.ParameterDiffs {
+			compDefNames = append(compDefNames, name)
+		}
+		sort.Strings(compDefNames)
+		for _, name := range compDefNames {
+			diffs := plan.ParameterDiffs[name]
+			if len(diffs) == 0 {
+				fmt.Fprintf(out, "\nNo parameter definition differences between %s and %s.\n", plan.ComponentDef, name)
+				continue
+			}
+			tbl = newTbl(out, fmt.Sprintf("\nParameter Differences (%s -> %s):", plan.ComponentDef, name), "FILE", "PARAMETER", "FROM", "TO")
+			for _, d := range diffs {
+				tbl.AddRow(d.File, d.Name, util.CheckEmpty(d.From), util.CheckEmpty(d.To))
+			}
+			tbl.Print()
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+func formatImages(images map[string]string) string {
+	var res []string
+	for name, image := range images {
+		res = append(res, fmt.Sprintf("%s=%s", name, image))
+	}
+	sort.Strings(res)
+	return strings.Join(res, ",")
+}
+
+func colorVersionChange(change string) string {
+	switch change {
+	case versionChangeMajor:
+		return printer.BoldRed(change)
+	case versionChangeMinor, versionChangeDowngrade:
+		return printer.BoldYellow(change)
+	default:
+		return change
+	}
+}
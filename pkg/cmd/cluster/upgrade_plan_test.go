@@ -0,0 +1,224 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	opsv1alpha1 "github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	parametersv1alpha1 "github.com/apecloud/kubeblocks/apis/parameters/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("upgrade plan", func() {
+	const (
+		clusterName = "test"
+		newCompDef  = testing.CompDefName + "-2"
+	)
+
+	var (
+		streams genericiooptions.IOStreams
+		out     *bytes.Buffer
+		tf      *cmdtesting.TestFactory
+	)
+
+	fakeComponentVersion := func() *kbappsv1.ComponentVersion {
+		cmpv := &kbappsv1.ComponentVersion{}
+		cmpv.Name = "fake-cmpv"
+		cmpv.Spec.CompatibilityRules = []kbappsv1.ComponentVersionCompatibilityRule{
+			{CompDefs: []string{testing.CompDefName}, Releases: []string{"r1", "r2"}},
+			{CompDefs: []string{"^" + newCompDef + "$"}, Releases: []string{"r3", "r4"}},
+		}
+		cmpv.Spec.Releases = []kbappsv1.ComponentVersionRelease{
+			{Name: "r1", ServiceVersion: "8.0.30", Images: map[string]string{"mysql": "mysql:8.0.30"}},
+			{Name: "r2", ServiceVersion: "8.0.32", Images: map[string]string{"mysql": "mysql:8.0.32"}},
+			{Name: "r3", ServiceVersion: "8.0.33", Images: map[string]string{"mysql": "mysql:8.0.33"}},
+			{Name: "r4", ServiceVersion: "8.4.0", Images: map[string]string{"mysql": "mysql:8.4.0"}},
+		}
+		return cmpv
+	}
+
+	fakeParameters := func() []*parametersv1alpha1.ParamConfigRenderer {
+		pcr := testing.FakeParameterConfigRenderer()
+		pcr.Namespace = ""
+		newPcr := testing.FakeParameterConfigRenderer()
+		newPcr.Name = "test-pcr-2"
+		newPcr.Namespace = ""
+		newPcr.Spec.ComponentDef = newCompDef
+		newPcr.Spec.ParametersDefs = []string{"test-pd-2"}
+		return []*parametersv1alpha1.ParamConfigRenderer{pcr, newPcr}
+	}
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericiooptions.NewTestIOStreams()
+		tf = testing.NewTestFactory(testing.Namespace)
+
+		c := testing.FakeCluster(clusterName, testing.Namespace)
+		for i := range c.Spec.ComponentSpecs {
+			c.Spec.ComponentSpecs[i].ServiceVersion = "8.0.30"
+		}
+		compDef := testing.FakeCompDef()
+		compDef2 := testing.FakeCompDef()
+		compDef2.Name = newCompDef
+		pd := testing.FakeParameterDefinition()
+		pd.Spec.StaticParameters = []string{"innodb_buffer_pool_size"}
+		pd.Spec.DynamicParameters = []string{"max_connections"}
+		pd2 := testing.FakeParameterDefinition()
+		pd2.Name = "test-pd-2"
+		pd2.Spec.DynamicParameters = []string{"max_connections", "innodb_buffer_pool_size"}
+		pcrs := fakeParameters()
+		tf.FakeDynamicClient = testing.FakeDynamicClient(c, compDef, compDef2, fakeComponentVersion(), pd, pd2, pcrs[0], pcrs[1])
+	})
+
+	AfterEach(func() {
+		tf.Cleanup()
+	})
+
+	It("getVersionChangeType", func() {
+		Expect(getVersionChangeType("8.0.30", "8.0.32")).Should(Equal(versionChangePatch))
+		Expect(getVersionChangeType("8.0.30", "8.4.0")).Should(Equal(versionChangeMinor))
+		Expect(getVersionChangeType("8.0.30", "9.0.0")).Should(Equal(versionChangeMajor))
+		Expect(getVersionChangeType("8.0.30", "5.7.44")).Should(Equal(versionChangeDowngrade))
+		Expect(getVersionChangeType(testing.CompDefName, "8.0.30")).Should(Equal(versionChangeUnknown))
+	})
+
+	It("compareCompDefName", func() {
+		Expect(compareCompDefName("mysql-8.0-1.0.10", "mysql-8.0-1.0.9")).Should(Equal(1))
+		Expect(compareCompDefName("mysql-8.0.30-1.0.0", "mysql-8.0.30-1.0.0-alpha.1")).Should(Equal(1))
+		Expect(compareCompDefName("mysql-8.0-1.0.0", "mysql-8.0-1.0.0")).Should(Equal(0))
+		Expect(compareCompDefName("mysql-5.7-1.0.1", "mysql-8.0-1.0.0")).Should(Equal(-1))
+		Expect(latestCompatibleCompDef([]string{"mysql-8.0"}, map[string]*kbappsv1.ComponentDefinition{
+			"mysql-8.0-1.0.9": nil, "mysql-8.0-1.0.10": nil,
+		})).Should(Equal("mysql-8.0-1.0.10"))
+	})
+
+	It("build and print upgrade plans", func() {
+		c := testing.FakeCluster(clusterName, testing.Namespace)
+		c.Spec.ComponentSpecs[0].ServiceVersion = "8.0.30"
+		plans, err := buildUpgradePlans(tf.FakeDynamicClient, c, []string{testing.ComponentName})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(plans).Should(HaveLen(1))
+
+		plan := plans[0]
+		Expect(plan.Targets).Should(HaveLen(3))
+		Expect(plan.Targets[0].ServiceVersion).Should(Equal("8.0.32"))
+		Expect(plan.Targets[0].CompDefChanged).Should(BeFalse())
+		Expect(plan.Targets[1].ServiceVersion).Should(Equal("8.0.33"))
+		Expect(plan.Targets[1].ComponentDef).Should(Equal(newCompDef))
+		Expect(plan.Targets[1].CompDefChanged).Should(BeTrue())
+		Expect(plan.Targets[2].ChangeType).Should(Equal(versionChangeMinor))
+		Expect(plan.ParameterDiffs).Should(BeEmpty())
+
+		Expect(addParameterDiffs(tf.FakeDynamicClient, plans)).Should(Succeed())
+		diffs := plan.ParameterDiffs[newCompDef]
+		Expect(diffs).Should(HaveLen(1))
+		Expect(diffs[0]).Should(Equal(parameterDiff{Name: "innodb_buffer_pool_size", File: "my.cnf", From: parameterKindStatic, To: parameterKindDynamic}))
+
+		Expect(latestPatchTarget(plan).ServiceVersion).Should(Equal("8.0.33"))
+
+		printUpgradePlans(plans, out)
+		Expect(out.String()).Should(ContainSubstring("mysql=mysql:8.0.32"))
+		Expect(out.String()).Should(ContainSubstring("innodb_buffer_pool_size"))
+
+		_, err = buildUpgradePlans(tf.FakeDynamicClient, c, []string{"not-exist"})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("build upgrade plans with the resolved component definition and service version", func() {
+		c := testing.FakeCluster(clusterName, testing.Namespace)
+		c.Spec.Shardings = []kbappsv1.ClusterSharding{{
+			Name:     "shard",
+			Template: kbappsv1.ClusterComponentSpec{Name: "shard", ComponentDef: "^" + testing.CompDefName},
+		}}
+		comp := &kbappsv1.Component{}
+		comp.Name = clusterName + "-shard-abc"
+		comp.Namespace = testing.Namespace
+		comp.Labels = map[string]string{
+			constant.AppInstanceLabelKey:       clusterName,
+			constant.KBAppShardingNameLabelKey: "shard",
+		}
+		comp.Spec.CompDef = testing.CompDefName
+		comp.Spec.ServiceVersion = "8.0.30"
+		dynamic := testing.FakeDynamicClient(c, testing.FakeCompDef(), fakeComponentVersion(), comp)
+
+		plans, err := buildUpgradePlans(dynamic, c, []string{"shard"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(plans).Should(HaveLen(1))
+		Expect(plans[0].ComponentDef).Should(Equal(testing.CompDefName))
+		Expect(plans[0].ServiceVersion).Should(Equal("8.0.30"))
+		Expect(latestPatchTarget(plans[0]).ServiceVersion).Should(Equal("8.0.32"))
+	})
+
+	It("run", func() {
+		cmd := NewUpgradePlanCmd(tf, streams)
+		Expect(cmd).ShouldNot(BeNil())
+
+		o := &UpgradePlanOptions{factory: tf, IOStreams: streams}
+		Expect(o.complete(nil)).Should(HaveOccurred())
+		Expect(o.complete([]string{clusterName})).Should(Succeed())
+		o.dynamic = tf.FakeDynamicClient
+		o.namespace = testing.Namespace
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("8.4.0"))
+	})
+
+	It("upgrade with latest patch", func() {
+		o := newBaseOperationsOptions(tf, streams, opsv1alpha1.UpgradeType, true)
+		o.Dynamic = tf.FakeDynamicClient
+		o.Name = clusterName
+		o.Namespace = testing.Namespace
+		o.AutoApprove = true
+		o.ComponentDefinitionName = "nil"
+		o.ServiceVersion = "nil"
+		o.ComponentNames = []string{testing.ComponentName}
+
+		By("validate the service version is provided by the compatible ComponentVersion")
+		o.ServiceVersion = "8.0.31"
+		Expect(o.Validate()).Should(HaveOccurred())
+		o.ServiceVersion = "8.0.32"
+		Expect(o.Validate()).Should(Succeed())
+
+		By("validate the service version with the target component definition")
+		o.ComponentDefinitionName = newCompDef
+		o.ServiceVersion = "8.4.0"
+		Expect(o.Validate()).Should(Succeed())
+		o.ServiceVersion = "8.0.32"
+		Expect(o.Validate()).Should(HaveOccurred())
+		o.ComponentDefinitionName = "nil"
+
+		By("latest patch can not be used with service version")
+		o.LatestPatch = true
+		Expect(o.Validate()).Should(HaveOccurred())
+
+		By("pick the latest patch version and the required component definition")
+		o.ServiceVersion = "nil"
+		Expect(o.Validate()).Should(Succeed())
+		Expect(o.ServiceVersion).Should(Equal("8.0.33"))
+		Expect(o.ComponentDefinitionName).Should(Equal(newCompDef))
+	})
+})
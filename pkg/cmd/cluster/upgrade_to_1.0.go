@@ -307,6 +307,19 @@ type UpgradeToV1Options struct {
 	NoDiff    bool
 	Name      string
 	Namespace string
+
+	// All converts all the v1alpha1 clusters in the namespace
+	All bool
+	// Resume finishes the interrupted conversions recorded in the state file
+	Resume bool
+	// PlanFile is the file to write the migration plan to, print to stdout if empty
+	PlanFile   string
+	PlanFormat string
+	// SnapshotDir is the dir to save the original objects and the conversion state
+	SnapshotDir string
+	// AutoApprove skips the interactive approval before converting
+	AutoApprove bool
+
 	genericiooptions.IOStreams
 	compDefList *unstructured.UnstructuredList
 }
@@ -324,6 +337,21 @@ var convertExample = templates.Examples(`
 
 		# upgrade a v1alpha1 cluster with --dry-run
 		kbcli cluster upgrade-to-v1 mycluster --dry-run
+
+		# write the migration plan of all v1alpha1 clusters in current namespace to a markdown file
+		kbcli cluster upgrade-to-v1 --all --dry-run --plan-file plan.md --plan-format markdown
+
+		# upgrade all v1alpha1 clusters in namespace demo
+		kbcli cluster upgrade-to-v1 --all -n demo
+
+		# upgrade all v1alpha1 clusters in namespace demo without confirmation
+		kbcli cluster upgrade-to-v1 --all -n demo --auto-approve
+
+		# resume the upgrade after interruption
+		kbcli cluster upgrade-to-v1 --all -n demo --resume
+
+		# rollback a converted cluster from its snapshot
+		kbcli cluster upgrade-to-v1 rollback mycluster
 `)
 
 func NewUpgradeToV1Cmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
@@ -341,15 +369,36 @@ func NewUpgradeToV1Cmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *c
 	}
 	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "dry run mode")
 	cmd.Flags().BoolVar(&o.NoDiff, "no-diff", false, "only print the new cluster yaml")
+	cmd.Flags().BoolVar(&o.All, "all", false, "Upgrade all the v1alpha1 clusters in the namespace")
+	cmd.Flags().BoolVar(&o.Resume, "resume", false, "Resume the interrupted conversions recorded in the state file")
+	cmd.Flags().StringVar(&o.PlanFile, "plan-file", "", "Write the migration plan to the file when --dry-run is specified, print to stdout if empty")
+	cmd.Flags().StringVar(&o.PlanFormat, "plan-format", planFormatJSON, fmt.Sprintf("The format of the migration plan, one of: (%s, %s)", planFormatJSON, planFormatMarkdown))
+	cmd.Flags().StringVar(&o.SnapshotDir, "snapshot-dir", "", "The dir to save the snapshots of the original objects and the state file, default is $HOME/.kbcli/upgrade-to-v1")
+	cmd.Flags().BoolVar(&o.AutoApprove, "auto-approve", false, "Skip interactive approval before upgrading")
+	util.CheckErr(cmd.RegisterFlagCompletionFunc("plan-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{planFormatJSON, planFormatMarkdown}, cobra.ShellCompDirectiveNoFileComp
+	}))
+	cmd.AddCommand(newUpgradeToV1RollbackCmd(f, streams))
 	return cmd
 }
 
 func (o *UpgradeToV1Options) complete(args []string) error {
-
-	if len(args) == 0 {
-		return fmt.Errorf("must specify cluster name")
+	var err error
+	if o.All && len(args) > 0 {
+		return fmt.Errorf("cannot specify cluster name with --all")
+	}
+	if !o.All {
+		if len(args) == 0 {
+			return fmt.Errorf("must specify cluster name")
+		}
+		o.Name = args[0]
+	}
+	if o.PlanFormat != planFormatJSON && o.PlanFormat != planFormatMarkdown {
+		return fmt.Errorf("plan format must be one of: (%s, %s)", planFormatJSON, planFormatMarkdown)
+	}
+	if o.SnapshotDir, err = getUpgradeToV1Dir(o.SnapshotDir); err != nil {
+		return err
 	}
-	o.Name = args[0]
 	o.Namespace, _, _ = o.f.ToRawKubeConfigLoader().Namespace()
 	o.Dynamic, _ = o.f.DynamicClient()
 	o.Client, _ = o.f.KubernetesClientSet()
@@ -392,6 +441,9 @@ func (o *UpgradeToV1Options) GetConvertedCluster() (*kbappsv1.Cluster, *kbappsv1
 }
 
 func (o *UpgradeToV1Options) Run() error {
+	if o.All {
+		return o.runAll()
+	}
 	cluster, clusterV1alpha1, existUnsupportedSpec, err := o.GetConvertedCluster()
 	if err != nil {
 		return err
@@ -401,12 +453,32 @@ func (o *UpgradeToV1Options) Run() error {
 		return fmt.Errorf(`cluster "%s" has unknown clusterVersion or componentDefinition, you can replace with accorrding ComponentDefinition with 1.0 api`, o.Name)
 	}
 	if o.DryRun {
-		return nil
+		plan, err := o.buildMigrationPlan(cluster, clusterV1alpha1, existUnsupportedSpec)
+		if err != nil {
+			return err
+		}
+		return o.writeMigrationPlan([]*clusterMigrationPlan{plan})
 	}
 	fmt.Println(printer.BoldYellow(fmt.Sprintf("Cluster %s will be converted to v1 with output as yaml.", o.Name)))
-	if err = prompt.Confirm(nil, o.In, "", "Please type 'Yes/yes' to confirm your operation:"); err != nil {
+	if !o.AutoApprove {
+		if err = prompt.Confirm(nil, o.In, "", "Please type 'Yes/yes' to confirm your operation:"); err != nil {
+			return err
+		}
+	}
+	return o.convertWithSnapshot(cluster, clusterV1alpha1)
+}
+
+// convert converts the cluster and its dependent objects to v1 api.
+func (o *UpgradeToV1Options) convert(cluster *kbappsv1.Cluster, clusterV1alpha1 *kbappsv1alpha1.Cluster) error {
+	if err := o.convertCluster(cluster, clusterV1alpha1); err != nil {
 		return err
 	}
+	return o.cleanupAfterConvert()
+}
+
+// convertCluster converts the credentials, services and the cluster object.
+func (o *UpgradeToV1Options) convertCluster(cluster *kbappsv1.Cluster, clusterV1alpha1 *kbappsv1alpha1.Cluster) error {
+	var err error
 	if len(clusterV1alpha1.Spec.ClusterVersionRef) > 0 {
 		if err = o.convertCredential(clusterV1alpha1.Spec.ClusterDefRef); err != nil {
 			return err
@@ -432,7 +504,12 @@ func (o *UpgradeToV1Options) Run() error {
 	printer.PrintLine(output)
 	nextLine := fmt.Sprintf("\tkubectl get clusters.apps.kubeblocks.io %s -n %s -oyaml", o.Name, o.Namespace)
 	printer.PrintLine(nextLine)
-	if err = o.deleteConfiguration(); err != nil {
+	return nil
+}
+
+// cleanupAfterConvert deletes the v1alpha1 configurations and normalizes the configmaps after the cluster is converted.
+func (o *UpgradeToV1Options) cleanupAfterConvert() error {
+	if err := o.deleteConfiguration(); err != nil {
 		return err
 	}
 	return o.normalizeConfigMaps()
@@ -444,6 +521,28 @@ func (o *UpgradeToV1Options) convertCredential(cdName string) error {
 	if err != nil {
 		return err
 	}
+	compName, accountName, err := getCredentialAccount(cdName)
+	if err != nil {
+		return err
+	}
+	newSecret := &corev1.Secret{}
+	newSecret.Name = constant.GenerateAccountSecretName(o.Name, compName, accountName)
+	newSecret.Namespace = oldSecret.Namespace
+	newSecret.Labels = constant.GetCompLabels(o.Name, compName)
+	newSecret.Labels["apps.kubeblocks.io/system-account"] = accountName
+	newSecret.Data = map[string][]byte{
+		"username": oldSecret.Data["username"],
+		"password": oldSecret.Data["password"],
+	}
+	if _, err := o.Client.CoreV1().Secrets(oldSecret.Namespace).Create(context.TODO(), newSecret, metav1.CreateOptions{}); err != nil {
+		return client.IgnoreAlreadyExists(err)
+	}
+	return nil
+}
+
+// getCredentialAccount returns the component and the system account that the v1alpha1 connection credential
+// of the cluster definition is converted to.
+func getCredentialAccount(cdName string) (string, string, error) {
 	var (
 		compName    string
 		accountName string
@@ -484,21 +583,9 @@ func (o *UpgradeToV1Options) convertCredential(cdName string) error {
 		compName = "mongodb"
 		accountName = "root"
 	default:
-		return fmt.Errorf("unknown cluster definition %s", cdName)
-	}
-	newSecret := &corev1.Secret{}
-	newSecret.Name = constant.GenerateAccountSecretName(o.Name, compName, accountName)
-	newSecret.Namespace = oldSecret.Namespace
-	newSecret.Labels = constant.GetCompLabels(o.Name, compName)
-	newSecret.Labels["apps.kubeblocks.io/system-account"] = accountName
-	newSecret.Data = map[string][]byte{
-		"username": oldSecret.Data["username"],
-		"password": oldSecret.Data["password"],
+		return "", "", fmt.Errorf("unknown cluster definition %s", cdName)
 	}
-	if _, err := o.Client.CoreV1().Secrets(oldSecret.Namespace).Create(context.TODO(), newSecret, metav1.CreateOptions{}); err != nil {
-		return client.IgnoreAlreadyExists(err)
-	}
-	return nil
+	return compName, accountName, nil
 }
 
 func (o *UpgradeToV1Options) convertAccounts() error {
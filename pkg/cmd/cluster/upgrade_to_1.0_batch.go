@@ -0,0 +1,843 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	kbappsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/prompt"
+)
+
+const (
+	planFormatJSON     = "json"
+	planFormatMarkdown = "markdown"
+
+	upgradeToV1Dir          = "upgrade-to-v1"
+	upgradeToV1SnapshotDir  = "snapshots"
+	upgradeToV1StateFile    = "state.json"
+	accountNameLabelKey     = "account.kubeblocks.io/name"
+	systemAccountLabelKey   = "apps.kubeblocks.io/system-account"
+	migrationActionCreate   = "Create"
+	migrationActionRelabel  = "Relabel"
+	migrationActionDelete   = "Delete"
+	migrationActionOrphan   = "RemoveOwnerReferences"
+	migrationActionSelector = "RemoveSelector"
+)
+
+// the conversion phases recorded in the state file
+const (
+	upgradeToV1PhasePending          = "Pending"
+	upgradeToV1PhaseSnapshotTaken    = "SnapshotTaken"
+	upgradeToV1PhaseClusterConverted = "ClusterConverted"
+	upgradeToV1PhaseCompleted        = "Completed"
+	upgradeToV1PhaseFailed           = "Failed"
+	upgradeToV1PhaseRolledBack       = "RolledBack"
+)
+
+// clusterMigrationPlan describes how a v1alpha1 cluster and its dependent objects will be converted
+type clusterMigrationPlan struct {
+	Name           string               `json:"name"`
+	Namespace      string               `json:"namespace"`
+	Supported      bool                 `json:"supported"`
+	Error          string               `json:"error,omitempty"`
+	Components     []componentMigration `json:"components,omitempty"`
+	Credentials    []objectMigration    `json:"credentials,omitempty"`
+	Services       []objectMigration    `json:"services,omitempty"`
+	ConfigMaps     []objectMigration    `json:"configMaps,omitempty"`
+	Configurations []objectMigration    `json:"configurations,omitempty"`
+	Warnings       []string             `json:"warnings,omitempty"`
+
+	cluster       *kbappsv1.Cluster
+	clusterAlpha1 *kbappsv1alpha1.Cluster
+	state         *upgradeToV1ClusterState
+}
+
+type componentMigration struct {
+	Name               string `json:"name"`
+	Sharding           bool   `json:"sharding,omitempty"`
+	FromComponentDef   string `json:"fromComponentDef"`
+	ToComponentDef     string `json:"toComponentDef"`
+	FromServiceVersion string `json:"fromServiceVersion,omitempty"`
+	ToServiceVersion   string `json:"toServiceVersion,omitempty"`
+}
+
+type objectMigration struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Target string `json:"target,omitempty"`
+}
+
+// upgradeToV1Snapshot saves the original objects of a cluster before it is converted to v1
+type upgradeToV1Snapshot struct {
+	Name      string           `json:"name"`
+	Namespace string           `json:"namespace"`
+	CreatedAt string           `json:"createdAt"`
+	Objects   []snapshotObject `json:"objects"`
+	// Created are the objects created by the conversion, they will be deleted when rollback
+	Created []snapshotObject `json:"created,omitempty"`
+}
+
+type snapshotObject struct {
+	Group    string                 `json:"group,omitempty"`
+	Version  string                 `json:"version"`
+	Resource string                 `json:"resource"`
+	Name     string                 `json:"name"`
+	Object   map[string]interface{} `json:"object,omitempty"`
+}
+
+func (s snapshotObject) gvr() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: s.Group, Version: s.Version, Resource: s.Resource}
+}
+
+// clusterUID returns the uid of the cluster saved in the snapshot
+func (s *upgradeToV1Snapshot) clusterUID() apitypes.UID {
+	for _, obj := range s.Objects {
+		if obj.gvr() == types.ClusterV1alphaGVR() {
+			return (&unstructured.Unstructured{Object: obj.Object}).GetUID()
+		}
+	}
+	return ""
+}
+
+// upgradeToV1State records the conversion progress of the clusters, it is used to resume after interruption
+type upgradeToV1State struct {
+	Clusters []*upgradeToV1ClusterState `json:"clusters"`
+}
+
+type upgradeToV1ClusterState struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Phase     string `json:"phase"`
+	Message   string `json:"message,omitempty"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// getUpgradeToV1Dir returns the dir to save the snapshots and state file
+func getUpgradeToV1Dir(dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	home, err := util.GetCliHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, upgradeToV1Dir), nil
+}
+
+func snapshotFilePath(dir, namespace, name string) string {
+	return filepath.Join(dir, upgradeToV1SnapshotDir, namespace, name+".yaml")
+}
+
+func loadUpgradeToV1State(file string) (*upgradeToV1State, error) {
+	state := &upgradeToV1State{}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse the state file %s: %v", file, err)
+	}
+	return state, nil
+}
+
+func (s *upgradeToV1State) save(file string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0600)
+}
+
+// get returns the state of the cluster, a pending state will be added if not found
+func (s *upgradeToV1State) get(namespace, name string) *upgradeToV1ClusterState {
+	for _, c := range s.Clusters {
+		if c.Namespace == namespace && c.Name == name {
+			return c
+		}
+	}
+	c := &upgradeToV1ClusterState{Name: name, Namespace: namespace, Phase: upgradeToV1PhasePending}
+	s.Clusters = append(s.Clusters, c)
+	return c
+}
+
+func (c *upgradeToV1ClusterState) set(phase string, err error) {
+	c.Phase = phase
+	c.Message = ""
+	if err != nil {
+		c.Message = err.Error()
+	}
+	c.UpdatedAt = time.Now().Format(time.RFC3339)
+}
+
+func (o *UpgradeToV1Options) stateFile() string {
+	return filepath.Join(o.SnapshotDir, upgradeToV1StateFile)
+}
+
+// runAll converts all the v1alpha1 clusters in the namespace
+func (o *UpgradeToV1Options) runAll() error {
+	var errs []error
+	state, err := loadUpgradeToV1State(o.stateFile())
+	if err != nil {
+		return err
+	}
+	saveState := func() {
+		if o.DryRun {
+			return
+		}
+		if err := state.save(o.stateFile()); err != nil {
+			printer.Warning(o.ErrOut, "failed to save the state file: %v\n", err)
+		}
+	}
+
+	// the clusters have been converted but the cleanup is interrupted
+	var unfinished []*upgradeToV1ClusterState
+	for _, c := range state.Clusters {
+		if c.Namespace != o.Namespace || c.Phase != upgradeToV1PhaseClusterConverted {
+			continue
+		}
+		if !o.Resume {
+			printer.Warning(o.ErrOut, "the cleanup of cluster %s is interrupted, use --resume to finish it\n", c.Name)
+			continue
+		}
+		unfinished = append(unfinished, c)
+	}
+
+	names, err := o.listV1alpha1Clusters()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 && len(unfinished) == 0 {
+		fmt.Fprintf(o.Out, "No v1alpha1 cluster found in namespace %s\n", o.Namespace)
+		return nil
+	}
+
+	var plans []*clusterMigrationPlan
+	for _, name := range names {
+		o.Name = name
+		plan, err := o.getMigrationPlan()
+		if err != nil {
+			plan = &clusterMigrationPlan{Name: name, Namespace: o.Namespace, Error: err.Error()}
+		}
+		plan.state = state.get(o.Namespace, name)
+		plans = append(plans, plan)
+	}
+
+	if o.DryRun {
+		return o.writeMigrationPlan(plans)
+	}
+
+	printMigrationSummary(plans, unfinished, o.Out)
+	if !o.AutoApprove {
+		if err = prompt.Confirm(nil, o.In, "", "Please type 'Yes/yes' to confirm your operation:"); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range unfinished {
+		o.Name = c.Name
+		fmt.Fprintf(o.Out, "Resume cleanup of cluster %s\n", c.Name)
+		if err = o.cleanupAfterConvert(); err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %v", c.Name, err))
+			c.set(upgradeToV1PhaseClusterConverted, err)
+		} else {
+			c.set(upgradeToV1PhaseCompleted, nil)
+		}
+		saveState()
+	}
+
+	for _, plan := range plans {
+		o.Name = plan.Name
+		if plan.Error != "" || !plan.Supported {
+			err = fmt.Errorf("cluster %s is skipped: %s", plan.Name, plan.skipReason())
+			plan.state.set(upgradeToV1PhaseFailed, err)
+			errs = append(errs, err)
+			saveState()
+			continue
+		}
+		fmt.Fprintf(o.Out, "Convert cluster %s\n", plan.Name)
+		if err = o.convertWithState(plan, saveState); err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %v", plan.Name, err))
+		}
+	}
+	fmt.Fprintf(o.Out, "The conversion state is saved to %s\n", o.stateFile())
+	return utilerrors.NewAggregate(errs)
+}
+
+func (o *UpgradeToV1Options) getMigrationPlan() (*clusterMigrationPlan, error) {
+	cluster, clusterV1alpha1, existUnsupportedSpec, err := o.GetConvertedCluster()
+	if err != nil {
+		return nil, err
+	}
+	return o.buildMigrationPlan(cluster, clusterV1alpha1, existUnsupportedSpec)
+}
+
+func (p *clusterMigrationPlan) skipReason() string {
+	if p.Error != "" {
+		return p.Error
+	}
+	return "it has unknown clusterVersion or componentDefinition"
+}
+
+// convertWithState converts the cluster and records the phases in the state file
+func (o *UpgradeToV1Options) convertWithState(plan *clusterMigrationPlan, saveState func()) error {
+	if err := o.takeSnapshot(plan.clusterAlpha1); err != nil {
+		plan.state.set(upgradeToV1PhaseFailed, err)
+		saveState()
+		return err
+	}
+	plan.state.set(upgradeToV1PhaseSnapshotTaken, nil)
+	saveState()
+
+	if err := o.convertCluster(plan.cluster, plan.clusterAlpha1); err != nil {
+		plan.state.set(upgradeToV1PhaseFailed, err)
+		saveState()
+		return err
+	}
+	plan.state.set(upgradeToV1PhaseClusterConverted, nil)
+	saveState()
+
+	if err := o.cleanupAfterConvert(); err != nil {
+		plan.state.set(upgradeToV1PhaseClusterConverted, err)
+		saveState()
+		return err
+	}
+	plan.state.set(upgradeToV1PhaseCompleted, nil)
+	saveState()
+	return nil
+}
+
+// convertWithSnapshot takes a snapshot of the original objects and converts the cluster
+func (o *UpgradeToV1Options) convertWithSnapshot(cluster *kbappsv1.Cluster, clusterV1alpha1 *kbappsv1alpha1.Cluster) error {
+	if err := o.takeSnapshot(clusterV1alpha1); err != nil {
+		return err
+	}
+	return o.convert(cluster, clusterV1alpha1)
+}
+
+// listV1alpha1Clusters returns the names of the clusters that have not been converted to v1
+func (o *UpgradeToV1Options) listV1alpha1Clusters() ([]string, error) {
+	list, err := o.Dynamic.Resource(types.ClusterGVR()).Namespace(o.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, item := range list.Items {
+		if item.GetAnnotations()[constant.CRDAPIVersionAnnotationKey] == kbappsv1.GroupVersion.String() {
+			continue
+		}
+		names = append(names, item.GetName())
+	}
+	return names, nil
+}
+
+func (o *UpgradeToV1Options) listClusterObjects(gvr schema.GroupVersionResource, selector string) ([]unstructured.Unstructured, error) {
+	list, err := o.Dynamic.Resource(gvr).Namespace(o.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// buildMigrationPlan builds the migration plan of the cluster without any side effect.
+func (o *UpgradeToV1Options) buildMigrationPlan(cluster *kbappsv1.Cluster, clusterV1alpha1 *kbappsv1alpha1.Cluster, existUnsupportedSpec bool) (*clusterMigrationPlan, error) {
+	plan := &clusterMigrationPlan{
+		Name:          o.Name,
+		Namespace:     o.Namespace,
+		Supported:     !existUnsupportedSpec,
+		cluster:       cluster,
+		clusterAlpha1: clusterV1alpha1,
+	}
+	useClusterVersion := len(clusterV1alpha1.Spec.ClusterVersionRef) > 0
+
+	// components and shardings
+	fromCompDef := func(spec kbappsv1alpha1.ClusterComponentSpec) string {
+		if useClusterVersion {
+			return spec.ComponentDefRef
+		}
+		return spec.ComponentDef
+	}
+	for i, spec := range clusterV1alpha1.Spec.ComponentSpecs {
+		if i >= len(cluster.Spec.ComponentSpecs) {
+			break
+		}
+		plan.Components = append(plan.Components, componentMigration{
+			Name:               spec.Name,
+			FromComponentDef:   fromCompDef(spec),
+			ToComponentDef:     cluster.Spec.ComponentSpecs[i].ComponentDef,
+			FromServiceVersion: spec.ServiceVersion,
+			ToServiceVersion:   cluster.Spec.ComponentSpecs[i].ServiceVersion,
+		})
+	}
+	for i, spec := range clusterV1alpha1.Spec.ShardingSpecs {
+		if i >= len(cluster.Spec.Shardings) {
+			break
+		}
+		plan.Components = append(plan.Components, componentMigration{
+			Name:               spec.Name,
+			Sharding:           true,
+			FromComponentDef:   fromCompDef(spec.Template),
+			ToComponentDef:     cluster.Spec.Shardings[i].Template.ComponentDef,
+			FromServiceVersion: spec.Template.ServiceVersion,
+			ToServiceVersion:   cluster.Spec.Shardings[i].Template.ServiceVersion,
+		})
+	}
+
+	// credentials
+	instanceSelector := fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, o.Name)
+	if useClusterVersion {
+		compName, accountName, err := getCredentialAccount(clusterV1alpha1.Spec.ClusterDefRef)
+		if err != nil {
+			plan.Supported = false
+			plan.Warnings = append(plan.Warnings, err.Error())
+		} else {
+			plan.Credentials = append(plan.Credentials, objectMigration{
+				Name:   fmt.Sprintf("%s-conn-credential", o.Name),
+				Action: migrationActionCreate,
+				Target: constant.GenerateAccountSecretName(o.Name, compName, accountName),
+			})
+		}
+	}
+	secrets, err := o.listClusterObjects(types.SecretGVR(), instanceSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets {
+		if account, ok := secret.GetLabels()[accountNameLabelKey]; ok {
+			plan.Credentials = append(plan.Credentials, objectMigration{
+				Name:   secret.GetName(),
+				Action: migrationActionRelabel,
+				Target: fmt.Sprintf("%s=%s", systemAccountLabelKey, account),
+			})
+		}
+	}
+
+	// services
+	services, err := o.listClusterObjects(types.ServiceGVR(), fmt.Sprintf("%s,%s=%s", instanceSelector, constant.AppManagedByLabelKey, constant.AppName))
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range services {
+		selector, _, _ := unstructured.NestedStringMap(svc.Object, "spec", "selector")
+		if _, ok := selector[constant.AppNameLabelKey]; ok {
+			plan.Services = append(plan.Services, objectMigration{
+				Name:   svc.GetName(),
+				Action: migrationActionSelector,
+				Target: constant.AppNameLabelKey,
+			})
+		}
+	}
+
+	// configmaps
+	configMaps, err := o.listClusterObjects(types.ConfigmapGVR(), instanceSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, cm := range configMaps {
+		labels := cm.GetLabels()
+		if _, ok := labels[constant.CMConfigurationSpecProviderLabelKey]; ok {
+			plan.ConfigMaps = append(plan.ConfigMaps, objectMigration{Name: cm.GetName(), Action: migrationActionOrphan})
+		} else if _, ok = labels[constant.CMTemplateNameLabelKey]; ok {
+			plan.ConfigMaps = append(plan.ConfigMaps, objectMigration{Name: cm.GetName(), Action: migrationActionDelete})
+		}
+	}
+
+	// configurations
+	configurations, err := o.listClusterObjects(types.ConfigurationGVR(), instanceSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range configurations {
+		plan.Configurations = append(plan.Configurations, objectMigration{Name: c.GetName(), Action: migrationActionDelete})
+	}
+	return plan, nil
+}
+
+// writeMigrationPlan writes the migration plans to the plan file or stdout
+func (o *UpgradeToV1Options) writeMigrationPlan(plans []*clusterMigrationPlan) error {
+	buf := &bytes.Buffer{}
+	switch o.PlanFormat {
+	case planFormatMarkdown:
+		renderMigrationPlanMarkdown(plans, buf)
+	default:
+		data, err := json.MarshalIndent(map[string]interface{}{"clusters": plans}, "", "  ")
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteString("\n")
+	}
+	if o.PlanFile == "" {
+		_, err := o.Out.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(o.PlanFile, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "The migration plan of %d cluster(s) is written to %s\n", len(plans), o.PlanFile)
+	return nil
+}
+
+func renderMigrationPlanMarkdown(plans []*clusterMigrationPlan, out io.Writer) {
+	fmt.Fprintln(out, "# Cluster Migration Plan to v1")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "| NAMESPACE | CLUSTER | SUPPORTED | COMPONENTS | NOTE |")
+	fmt.Fprintln(out, "|---|---|---|---|---|")
+	for _, p := range plans {
+		note := p.Error
+		if note == "" {
+			note = strings.Join(p.Warnings, "; ")
+		}
+		fmt.Fprintf(out, "| %s | %s | %t | %d | %s |\n", p.Namespace, p.Name, p.Supported && p.Error == "", len(p.Components), note)
+	}
+
+	writeObjects := func(title string, objs []objectMigration) {
+		if len(objs) == 0 {
+			return
+		}
+		fmt.Fprintf(out, "\n### %s\n\n", title)
+		fmt.Fprintln(out, "| NAME | ACTION | TARGET |")
+		fmt.Fprintln(out, "|---|---|---|")
+		for _, obj := range objs {
+			fmt.Fprintf(out, "| %s | %s | %s |\n", obj.Name, obj.Action, obj.Target)
+		}
+	}
+	for _, p := range plans {
+		fmt.Fprintf(out, "\n## %s/%s\n", p.Namespace, p.Name)
+		if p.Error != "" {
+			fmt.Fprintf(out, "\n**Error:** %s\n", p.Error)
+			continue
+		}
+		for _, w := range p.Warnings {
+			fmt.Fprintf(out, "\n**Warning:** %s\n", w)
+		}
+		if len(p.Components) > 0 {
+			fmt.Fprintln(out, "\n### Components")
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, "| COMPONENT | SHARDING | FROM COMPONENT-DEF | TO COMPONENT-DEF | FROM SERVICE-VERSION | TO SERVICE-VERSION |")
+			fmt.Fprintln(out, "|---|---|---|---|---|---|")
+			for _, c := range p.Components {
+				fmt.Fprintf(out, "| %s | %t | %s | %s | %s | %s |\n", c.Name, c.Sharding, c.FromComponentDef, c.ToComponentDef, c.FromServiceVersion, c.ToServiceVersion)
+			}
+		}
+		writeObjects("Credentials", p.Credentials)
+		writeObjects("Services", p.Services)
+		writeObjects("ConfigMaps", p.ConfigMaps)
+		writeObjects("Configurations", p.Configurations)
+	}
+}
+
+func printMigrationSummary(plans []*clusterMigrationPlan, unfinished []*upgradeToV1ClusterState, out io.Writer) {
+	tbl := newTbl(out, "", "NAMESPACE", "CLUSTER", "COMPONENTS", "ACTION")
+	for _, c := range unfinished {
+		tbl.AddRow(c.Namespace, c.Name, "", "Resume cleanup")
+	}
+	for _, p := range plans {
+		action := "Convert"
+		if p.Error != "" || !p.Supported {
+			action = printer.BoldRed("Skip: " + p.skipReason())
+		}
+		var comps []string
+		for _, c := range p.Components {
+			comps = append(comps, fmt.Sprintf("%s: %s -> %s", c.Name, c.FromComponentDef, c.ToComponentDef))
+		}
+		tbl.AddRow(p.Namespace, p.Name, strings.Join(comps, "\n"), action)
+	}
+	tbl.Print()
+}
+
+// takeSnapshot saves the original v1alpha1 cluster and the objects that will be modified by
+// the conversion to a local file. An existing snapshot of the same cluster is kept since it holds
+// the original objects of an interrupted conversion, a snapshot left by a deleted cluster with
+// the same name is overwritten.
+func (o *UpgradeToV1Options) takeSnapshot(clusterV1alpha1 *kbappsv1alpha1.Cluster) error {
+	file := snapshotFilePath(o.SnapshotDir, o.Namespace, o.Name)
+	if existing, err := loadSnapshot(file); err == nil && existing.clusterUID() == clusterV1alpha1.UID {
+		return nil
+	}
+
+	snapshot := &upgradeToV1Snapshot{
+		Name:      o.Name,
+		Namespace: o.Namespace,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	addObject := func(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) {
+		obj = obj.DeepCopy()
+		obj.SetManagedFields(nil)
+		snapshot.Objects = append(snapshot.Objects, snapshotObject{
+			Group:    gvr.Group,
+			Version:  gvr.Version,
+			Resource: gvr.Resource,
+			Name:     obj.GetName(),
+			Object:   obj.Object,
+		})
+	}
+
+	clusterObj, err := o.Dynamic.Resource(types.ClusterV1alphaGVR()).Namespace(o.Namespace).Get(context.TODO(), o.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	addObject(types.ClusterV1alphaGVR(), clusterObj)
+
+	instanceSelector := fmt.Sprintf("%s=%s", constant.AppInstanceLabelKey, o.Name)
+	for _, gvr := range []schema.GroupVersionResource{types.SecretGVR(), types.ServiceGVR(), types.ConfigmapGVR(), types.ConfigurationGVR()} {
+		objs, err := o.listClusterObjects(gvr, instanceSelector)
+		if err != nil {
+			return err
+		}
+		for i := range objs {
+			addObject(gvr, &objs[i])
+		}
+	}
+
+	if len(clusterV1alpha1.Spec.ClusterVersionRef) > 0 {
+		if compName, accountName, err := getCredentialAccount(clusterV1alpha1.Spec.ClusterDefRef); err == nil {
+			gvr := types.SecretGVR()
+			snapshot.Created = append(snapshot.Created, snapshotObject{
+				Group:    gvr.Group,
+				Version:  gvr.Version,
+				Resource: gvr.Resource,
+				Name:     constant.GenerateAccountSecretName(o.Name, compName, accountName),
+			})
+		}
+	}
+
+	data, err := yaml.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	// the snapshot contains secrets, only the owner can read it
+	if err = os.WriteFile(file, data, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "The original objects of cluster %s are saved to %s\n", o.Name, file)
+	return nil
+}
+
+var upgradeToV1RollbackExample = templates.Examples(`
+		# rollback a converted cluster from its snapshot
+		kbcli cluster upgrade-to-v1 rollback mycluster
+
+		# rollback all the converted clusters in namespace demo
+		kbcli cluster upgrade-to-v1 rollback --all -n demo`)
+
+type upgradeToV1RollbackOptions struct {
+	factory     cmdutil.Factory
+	dynamic     dynamic.Interface
+	namespace   string
+	names       []string
+	all         bool
+	snapshotDir string
+	autoApprove bool
+
+	genericiooptions.IOStreams
+}
+
+func newUpgradeToV1RollbackCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &upgradeToV1RollbackOptions{
+		factory:   f,
+		IOStreams: streams,
+	}
+	cmd := &cobra.Command{
+		Use:               "rollback [NAME | --all]",
+		Short:             "Rollback the clusters converted by upgrade-to-v1 from their snapshots.",
+		Example:           upgradeToV1RollbackExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.ClusterGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.BehaviorOnFatal(printer.FatalWithRedColor)
+			cmdutil.CheckErr(o.complete(args))
+			cmdutil.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().BoolVar(&o.all, "all", false, "Rollback all the clusters with snapshots in the namespace")
+	cmd.Flags().StringVar(&o.snapshotDir, "snapshot-dir", "", "The dir where the snapshots and the state file are saved, default is $HOME/.kbcli/upgrade-to-v1")
+	cmd.Flags().BoolVar(&o.autoApprove, "auto-approve", false, "Skip interactive approval before rollback")
+	return cmd
+}
+
+func (o *upgradeToV1RollbackOptions) complete(args []string) error {
+	var err error
+	if o.all && len(args) > 0 {
+		return fmt.Errorf("cannot specify cluster name with --all")
+	}
+	if !o.all && len(args) == 0 {
+		return fmt.Errorf("must specify cluster name or use --all")
+	}
+	if o.snapshotDir, err = getUpgradeToV1Dir(o.snapshotDir); err != nil {
+		return err
+	}
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	if o.dynamic, err = o.factory.DynamicClient(); err != nil {
+		return err
+	}
+	o.names = args
+	if o.all {
+		entries, err := os.ReadDir(filepath.Join(o.snapshotDir, upgradeToV1SnapshotDir, o.namespace))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".yaml") {
+				o.names = append(o.names, strings.TrimSuffix(e.Name(), ".yaml"))
+			}
+		}
+		if len(o.names) == 0 {
+			return fmt.Errorf("no snapshot found in namespace %s", o.namespace)
+		}
+	}
+	return nil
+}
+
+func (o *upgradeToV1RollbackOptions) run() error {
+	if !o.autoApprove {
+		fmt.Fprintln(o.Out, printer.BoldYellow(fmt.Sprintf("Cluster(s) %s will be restored from the snapshots, the changes made after the conversion will be lost.", strings.Join(o.names, ", "))))
+		if err := prompt.Confirm(nil, o.In, "", "Please type 'Yes/yes' to confirm your operation:"); err != nil {
+			return err
+		}
+	}
+
+	stateFile := filepath.Join(o.snapshotDir, upgradeToV1StateFile)
+	state, err := loadUpgradeToV1State(stateFile)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, name := range o.names {
+		clusterState := state.get(o.namespace, name)
+		if err = o.rollback(name); err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %v", name, err))
+			clusterState.set(clusterState.Phase, err)
+			continue
+		}
+		clusterState.set(upgradeToV1PhaseRolledBack, nil)
+		fmt.Fprintf(o.Out, "Cluster %s is rolled back\n", name)
+	}
+	if err = state.save(stateFile); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// rollback restores the cluster and its dependent objects from the snapshot, the snapshot
+// is removed after the cluster is restored so that the next conversion takes a new one.
+func (o *upgradeToV1RollbackOptions) rollback(name string) error {
+	file := snapshotFilePath(o.snapshotDir, o.namespace, name)
+	snapshot, err := loadSnapshot(file)
+	if err != nil {
+		return err
+	}
+
+	// delete the objects created by the conversion
+	for _, obj := range snapshot.Created {
+		err = o.dynamic.Resource(obj.gvr()).Namespace(o.namespace).Delete(context.TODO(), obj.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	// restore the dependent objects first, and the cluster at last
+	var clusterObj *snapshotObject
+	for i, obj := range snapshot.Objects {
+		if obj.gvr() == types.ClusterV1alphaGVR() {
+			clusterObj = &snapshot.Objects[i]
+			continue
+		}
+		if err = restoreObject(o.dynamic, obj.gvr(), &unstructured.Unstructured{Object: obj.Object}); err != nil {
+			return err
+		}
+	}
+	if clusterObj == nil {
+		return fmt.Errorf("the snapshot of cluster %s does not contain the cluster object", name)
+	}
+	if err = restoreObject(o.dynamic, clusterObj.gvr(), &unstructured.Unstructured{Object: clusterObj.Object}); err != nil {
+		return err
+	}
+	return os.Remove(file)
+}
+
+// loadSnapshot reads the snapshot from the file
+func loadSnapshot(file string) (*upgradeToV1Snapshot, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &upgradeToV1Snapshot{}
+	if err = yaml.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// restoreObject updates the object to the snapshot, or creates it if it has been deleted
+func restoreObject(dynamic dynamic.Interface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	obj = obj.DeepCopy()
+	client := dynamic.Resource(gvr).Namespace(obj.GetNamespace())
+	current, err := client.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		obj.SetResourceVersion("")
+		obj.SetUID("")
+		obj.SetCreationTimestamp(metav1.Time{})
+		_, err = client.Create(context.TODO(), obj, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(current.GetResourceVersion())
+	_, err = client.Update(context.TODO(), obj, metav1.UpdateOptions{})
+	return err
+}
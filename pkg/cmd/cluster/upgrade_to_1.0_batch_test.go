@@ -0,0 +1,152 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var _ = Describe("upgrade-to-v1 batch", func() {
+	var (
+		streams genericiooptions.IOStreams
+		out     *bytes.Buffer
+		plans   []*clusterMigrationPlan
+	)
+
+	BeforeEach(func() {
+		streams, _, out, _ = genericiooptions.NewTestIOStreams()
+		plans = []*clusterMigrationPlan{
+			{
+				Name:      "mycluster",
+				Namespace: "default",
+				Supported: true,
+				Components: []componentMigration{
+					{Name: "mysql", FromComponentDef: "mysql", ToComponentDef: "apecloud-mysql-1.0.0", ToServiceVersion: "8.0.30"},
+				},
+				Credentials: []objectMigration{
+					{Name: "mycluster-conn-credential", Action: migrationActionCreate, Target: "mycluster-mysql-account-root"},
+				},
+				Configurations: []objectMigration{{Name: "mycluster-mysql", Action: migrationActionDelete}},
+			},
+			{Name: "broken", Namespace: "default", Error: "not found"},
+		}
+	})
+
+	It("write migration plan", func() {
+		o := NewUpgradeToV1Option(testing.NewTestFactory("default"), streams)
+		o.PlanFormat = planFormatJSON
+		Expect(o.writeMigrationPlan(plans)).Should(Succeed())
+		res := map[string][]clusterMigrationPlan{}
+		Expect(json.Unmarshal(out.Bytes(), &res)).Should(Succeed())
+		Expect(res["clusters"]).Should(HaveLen(2))
+		Expect(res["clusters"][0].Components[0].ToComponentDef).Should(Equal("apecloud-mysql-1.0.0"))
+
+		out.Reset()
+		o.PlanFormat = planFormatMarkdown
+		o.PlanFile = filepath.Join(GinkgoT().TempDir(), "plan.md")
+		Expect(o.writeMigrationPlan(plans)).Should(Succeed())
+		data, err := os.ReadFile(o.PlanFile)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(data)).Should(ContainSubstring("## default/mycluster"))
+		Expect(string(data)).Should(ContainSubstring("mycluster-mysql-account-root"))
+		Expect(string(data)).Should(ContainSubstring("**Error:** not found"))
+	})
+
+	It("save and load state", func() {
+		file := filepath.Join(GinkgoT().TempDir(), upgradeToV1StateFile)
+		state, err := loadUpgradeToV1State(file)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(state.Clusters).Should(BeEmpty())
+
+		state.get("default", "mycluster").set(upgradeToV1PhaseClusterConverted, nil)
+		Expect(state.save(file)).Should(Succeed())
+
+		state, err = loadUpgradeToV1State(file)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(state.Clusters).Should(HaveLen(1))
+		Expect(state.get("default", "mycluster").Phase).Should(Equal(upgradeToV1PhaseClusterConverted))
+		Expect(state.get("default", "other").Phase).Should(Equal(upgradeToV1PhasePending))
+	})
+
+	It("restore object", func() {
+		cm := testing.FakeConfigMap("test-cm", "default", map[string]string{"key": "value"})
+		obj, err := util.ConvertObjToUnstructured(cm)
+		Expect(err).ShouldNot(HaveOccurred())
+		dynamic := testing.FakeDynamicClient()
+
+		// create the object if it has been deleted
+		Expect(restoreObject(dynamic, types.ConfigmapGVR(), obj)).Should(Succeed())
+		current, err := dynamic.Resource(types.ConfigmapGVR()).Namespace("default").Get(context.TODO(), "test-cm", metav1.GetOptions{})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// update the object to the snapshot
+		_ = unstructured.SetNestedField(current.Object, "changed", "data", "key")
+		_, err = dynamic.Resource(types.ConfigmapGVR()).Namespace("default").Update(context.TODO(), current, metav1.UpdateOptions{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(restoreObject(dynamic, types.ConfigmapGVR(), obj)).Should(Succeed())
+		current, err = dynamic.Resource(types.ConfigmapGVR()).Namespace("default").Get(context.TODO(), "test-cm", metav1.GetOptions{})
+		Expect(err).ShouldNot(HaveOccurred())
+		val, _, _ := unstructured.NestedString(current.Object, "data", "key")
+		Expect(val).Should(Equal("value"))
+	})
+
+	It("load snapshot", func() {
+		gvr := types.ClusterV1alphaGVR()
+		snapshot := &upgradeToV1Snapshot{
+			Name:      "mycluster",
+			Namespace: "default",
+			Objects: []snapshotObject{
+				{
+					Group:    gvr.Group,
+					Version:  gvr.Version,
+					Resource: gvr.Resource,
+					Name:     "mycluster",
+					Object:   map[string]interface{}{"metadata": map[string]interface{}{"name": "mycluster", "uid": "uid-1"}},
+				},
+			},
+		}
+		data, err := json.Marshal(snapshot)
+		Expect(err).ShouldNot(HaveOccurred())
+		file := filepath.Join(GinkgoT().TempDir(), "mycluster.yaml")
+		Expect(os.WriteFile(file, data, 0600)).Should(Succeed())
+
+		loaded, err := loadSnapshot(file)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(loaded.clusterUID())).Should(Equal("uid-1"))
+		Expect((&upgradeToV1Snapshot{}).clusterUID()).Should(BeEmpty())
+
+		_, err = loadSnapshot(filepath.Join(filepath.Dir(file), "other.yaml"))
+		Expect(err).Should(HaveOccurred())
+	})
+})
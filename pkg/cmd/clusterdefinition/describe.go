@@ -43,7 +43,13 @@ import (
 var (
 	describeExample = templates.Examples(`
 		# describe a specified cluster definition
-		kbcli clusterdefinition describe myclusterdef`)
+		kbcli clusterdefinition describe myclusterdef
+
+		# draw the topologies of the cluster definition
+		kbcli clusterdefinition describe myclusterdef --graph
+
+		# draw the topologies of the cluster definition in mermaid
+		kbcli clusterdefinition describe myclusterdef --graph -o mermaid`)
 )
 
 type describeOptions struct {
@@ -52,7 +58,9 @@ type describeOptions struct {
 	dynamic   dynamic.Interface
 	namespace string
 
-	names []string
+	names  []string
+	graph  bool
+	output string
 	genericiooptions.IOStreams
 }
 
@@ -72,6 +80,12 @@ func NewDescribeCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobr
 			cmdutil.CheckErr(o.run())
 		},
 	}
+	cmd.Flags().BoolVar(&o.graph, "graph", false, "Draw the topologies with the component definitions and the provision, update and terminate orders")
+	cmd.Flags().StringVarP(&o.output, "output", "o", graphFormatASCII,
+		fmt.Sprintf("The output format of the graph, it only works with --graph. Allowed values: %s, %s, %s", graphFormatASCII, graphFormatDot, graphFormatMermaid))
+	util.CheckErr(cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{graphFormatASCII, graphFormatDot, graphFormatMermaid}, cobra.ShellCompDirectiveNoFileComp
+	}))
 	return cmd
 }
 
@@ -83,6 +97,15 @@ func (o *describeOptions) complete(args []string) error {
 	}
 	o.names = args
 
+	switch o.output {
+	case graphFormatASCII, graphFormatDot, graphFormatMermaid:
+	default:
+		return fmt.Errorf("invalid output format %s, only support %s, %s and %s", o.output, graphFormatASCII, graphFormatDot, graphFormatMermaid)
+	}
+	if o.output != graphFormatASCII && !o.graph {
+		return fmt.Errorf("--output only works with --graph")
+	}
+
 	if o.client, err = o.factory.KubernetesClientSet(); err != nil {
 		return err
 	}
@@ -113,6 +136,9 @@ func (o *describeOptions) describeClusterDef(name string) error {
 	if err := util.GetK8SClientObject(o.dynamic, clusterDef, types.ClusterDefGVR(), "", name); err != nil {
 		return err
 	}
+	if o.graph {
+		return o.showGraph(clusterDef)
+	}
 	if err := o.showClusterDef(clusterDef); err != nil {
 		return err
 	}
@@ -143,6 +169,33 @@ func (o *describeOptions) showClusterDef(cd *kbappsv1.ClusterDefinition) error {
 	return nil
 }
 
+func (o *describeOptions) showGraph(cd *kbappsv1.ClusterDefinition) error {
+	usages, errs := getTopologyUsages(cd)
+	for _, err := range errs {
+		printer.Warning(o.ErrOut, "%v\n", err)
+	}
+	switch o.output {
+	case graphFormatDot:
+		printDotGraph(o.Out, cd, usages)
+	case graphFormatMermaid:
+		printMermaidGraph(o.Out, cd, usages)
+	default:
+		compDefList, err := o.dynamic.Resource(types.CompDefGVR()).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		cmpvList, err := o.dynamic.Resource(types.ComponentVersionsGVR()).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		printASCIIGraph(o.Out, cd, usages, func(pattern string) string {
+			compDefs, _ := o.getComponentDefAndVersions(compDefList, cmpvList, pattern)
+			return compDefs
+		})
+	}
+	return nil
+}
+
 func (o *describeOptions) getComponentDefAndVersions(compDefList, cmpvList *unstructured.UnstructuredList, compMatchRegex string) (string, string) {
 	var (
 		compDefs []string
@@ -0,0 +1,296 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package clusterdefinition
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util/helm"
+)
+
+const (
+	graphFormatASCII   = "ascii"
+	graphFormatDot     = "dot"
+	graphFormatMermaid = "mermaid"
+
+	orderProvision = "provision"
+	orderUpdate    = "update"
+	orderTerminate = "terminate"
+)
+
+var (
+	orderColors = map[string]string{
+		orderProvision: "darkgreen",
+		orderUpdate:    "blue",
+		orderTerminate: "red",
+	}
+
+	mermaidIDRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// topologyUsage is a registered cluster type which creates clusters with the topology.
+type topologyUsage struct {
+	clusterType string
+	chart       string
+}
+
+func (u topologyUsage) command(topology string) string {
+	return fmt.Sprintf("kbcli cluster create %s --topology %s", u.clusterType, topology)
+}
+
+// getTopologyUsages renders the registered cluster charts and returns the cluster types grouped by
+// the topology of the cluster definition. If the chart provides a topology enum in its schema, all
+// the topologies in the enum are regarded as supported. The charts failed to render are returned
+// as errors and skipped.
+func getTopologyUsages(cd *kbappsv1.ClusterDefinition) (map[string][]topologyUsage, []error) {
+	var defaultTopology string
+	for _, t := range cd.Spec.Topologies {
+		if t.Default {
+			defaultTopology = t.Name
+		}
+	}
+	usages := map[string][]topologyUsage{}
+	var errs []error
+	for _, t := range cluster.SupportedTypes() {
+		ci, err := cluster.BuildChartInfo(t)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to load the chart of cluster type %s: %v", t, err))
+			continue
+		}
+		manifests, err := cluster.GetManifests(ci.Chart, true, metav1.NamespaceDefault, t.String(), helm.FakeKubeVersion, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to render the chart of cluster type %s: %v", t, err))
+			continue
+		}
+		clusterDef, topology := getClusterDefAndTopology(manifests)
+		if clusterDef != cd.Name {
+			continue
+		}
+		usage := topologyUsage{
+			clusterType: t.String(),
+			chart:       fmt.Sprintf("%s-%s", ci.Chart.Metadata.Name, ci.Chart.Metadata.Version),
+		}
+		topologies := []string{topology}
+		if ci.Schema != nil {
+			if prop, ok := ci.Schema.Properties["topology"]; ok && len(prop.Enum) > 0 {
+				topologies = nil
+				for _, e := range prop.Enum {
+					topologies = append(topologies, fmt.Sprintf("%v", e))
+				}
+			}
+		}
+		for _, name := range topologies {
+			if name == "" {
+				name = defaultTopology
+			}
+			usages[name] = append(usages[name], usage)
+		}
+	}
+	return usages, errs
+}
+
+// getClusterDefAndTopology finds the cluster object in the manifests and returns its cluster definition and topology.
+func getClusterDefAndTopology(manifests map[string]string) (string, string) {
+	for _, m := range manifests {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(m), &obj.Object); err != nil || obj.GetKind() != types.KindCluster {
+			continue
+		}
+		clusterDef, _, _ := unstructured.NestedString(obj.Object, "spec", "clusterDef")
+		topology, _, _ := unstructured.NestedString(obj.Object, "spec", "topology")
+		return clusterDef, topology
+	}
+	return "", ""
+}
+
+// parseOrder parses the order to stages, the components in the same stage are separated by comma
+// and processed concurrently.
+func parseOrder(order []string) [][]string {
+	var stages [][]string
+	for _, s := range order {
+		var stage []string
+		for _, c := range strings.Split(s, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				stage = append(stage, c)
+			}
+		}
+		if len(stage) > 0 {
+			stages = append(stages, stage)
+		}
+	}
+	return stages
+}
+
+type topologyOrder struct {
+	name   string
+	stages [][]string
+}
+
+func getOrders(t kbappsv1.ClusterTopology) []topologyOrder {
+	orders := []topologyOrder{{name: orderProvision}, {name: orderUpdate}, {name: orderTerminate}}
+	if t.Orders == nil {
+		return orders
+	}
+	orders[0].stages = parseOrder(t.Orders.Provision)
+	orders[1].stages = parseOrder(t.Orders.Update)
+	orders[2].stages = parseOrder(t.Orders.Terminate)
+	return orders
+}
+
+func topologyTitle(t kbappsv1.ClusterTopology) string {
+	if t.Default {
+		return t.Name + " (default)"
+	}
+	return t.Name
+}
+
+func sortedUsages(usages []topologyUsage) []topologyUsage {
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].clusterType < usages[j].clusterType
+	})
+	return usages
+}
+
+// printASCIIGraph draws the topologies with the component definitions and orders in ASCII.
+// compDefsFn returns the component definitions which match the pattern.
+func printASCIIGraph(out io.Writer, cd *kbappsv1.ClusterDefinition, usages map[string][]topologyUsage, compDefsFn func(pattern string) string) {
+	fmt.Fprintf(out, "ClusterDefinition: %s\n", cd.Name)
+	for _, t := range cd.Spec.Topologies {
+		fmt.Fprintf(out, "\nTopology: %s\n", topologyTitle(t))
+		fmt.Fprintf(out, "  Components:\n")
+		for i, c := range t.Components {
+			branch := "├──"
+			if i == len(t.Components)-1 {
+				branch = "└──"
+			}
+			compDefs := ""
+			if compDefsFn != nil {
+				compDefs = compDefsFn(c.CompDef)
+			}
+			if compDefs == "" {
+				compDefs = types.None
+			}
+			fmt.Fprintf(out, "  %s %s [%s] => %s\n", branch, c.Name, c.CompDef, compDefs)
+		}
+		if len(t.Shardings) > 0 {
+			fmt.Fprintf(out, "  Shardings:\n")
+		}
+		for i, s := range t.Shardings {
+			branch := "├──"
+			if i == len(t.Shardings)-1 {
+				branch = "└──"
+			}
+			fmt.Fprintf(out, "  %s %s [%s]\n", branch, s.Name, s.ShardingDef)
+		}
+		for _, order := range getOrders(t) {
+			fmt.Fprintf(out, "  %s:\n", strings.ToUpper(order.name[:1])+order.name[1:])
+			if len(order.stages) == 0 {
+				fmt.Fprintf(out, "    %s (all components are processed concurrently)\n", types.None)
+				continue
+			}
+			var stages []string
+			for _, stage := range order.stages {
+				stages = append(stages, "["+strings.Join(stage, ", ")+"]")
+			}
+			fmt.Fprintf(out, "    %s\n", strings.Join(stages, " --> "))
+		}
+		fmt.Fprintf(out, "  Used By:\n")
+		if len(usages[t.Name]) == 0 {
+			fmt.Fprintf(out, "    %s\n", types.None)
+		}
+		for _, u := range sortedUsages(usages[t.Name]) {
+			fmt.Fprintf(out, "    %s (chart: %s)\n", u.command(t.Name), u.chart)
+		}
+	}
+}
+
+// printDotGraph draws the topologies in the Graphviz dot language, each topology is a subgraph.
+func printDotGraph(out io.Writer, cd *kbappsv1.ClusterDefinition, usages map[string][]topologyUsage) {
+	fmt.Fprintf(out, "digraph %q {\n", cd.Name)
+	fmt.Fprintf(out, "  rankdir=LR;\n")
+	fmt.Fprintf(out, "  node [shape=box];\n")
+	for _, t := range cd.Spec.Topologies {
+		label := topologyTitle(t)
+		for _, u := range sortedUsages(usages[t.Name]) {
+			label += fmt.Sprintf("\\n%s (chart: %s)", u.command(t.Name), u.chart)
+		}
+		fmt.Fprintf(out, "  subgraph %q {\n", "cluster_"+t.Name)
+		fmt.Fprintf(out, "    label=%q;\n", label)
+		for _, c := range t.Components {
+			fmt.Fprintf(out, "    %q [label=%q];\n", t.Name+"/"+c.Name, c.Name+"\\n"+c.CompDef)
+		}
+		for _, s := range t.Shardings {
+			fmt.Fprintf(out, "    %q [label=%q, shape=box3d];\n", t.Name+"/"+s.Name, s.Name+"\\n"+s.ShardingDef)
+		}
+		for _, order := range getOrders(t) {
+			for i := 0; i+1 < len(order.stages); i++ {
+				for _, from := range order.stages[i] {
+					for _, to := range order.stages[i+1] {
+						fmt.Fprintf(out, "    %q -> %q [label=%q, color=%q];\n",
+							t.Name+"/"+from, t.Name+"/"+to, order.name, orderColors[order.name])
+					}
+				}
+			}
+		}
+		fmt.Fprintf(out, "  }\n")
+	}
+	fmt.Fprintf(out, "}\n")
+}
+
+// printMermaidGraph draws the topologies in the mermaid flowchart syntax, each topology is a subgraph.
+func printMermaidGraph(out io.Writer, cd *kbappsv1.ClusterDefinition, usages map[string][]topologyUsage) {
+	id := func(names ...string) string {
+		return mermaidIDRegex.ReplaceAllString(strings.Join(names, "_"), "_")
+	}
+	fmt.Fprintf(out, "flowchart LR\n")
+	for _, t := range cd.Spec.Topologies {
+		fmt.Fprintf(out, "  subgraph %s[\"%s\"]\n", id("topology", t.Name), topologyTitle(t))
+		for _, c := range t.Components {
+			fmt.Fprintf(out, "    %s[\"%s<br/>%s\"]\n", id(t.Name, c.Name), c.Name, c.CompDef)
+		}
+		for _, s := range t.Shardings {
+			fmt.Fprintf(out, "    %s[[\"%s<br/>%s\"]]\n", id(t.Name, s.Name), s.Name, s.ShardingDef)
+		}
+		for _, order := range getOrders(t) {
+			for i := 0; i+1 < len(order.stages); i++ {
+				for _, from := range order.stages[i] {
+					for _, to := range order.stages[i+1] {
+						fmt.Fprintf(out, "    %s -->|%s| %s\n", id(t.Name, from), order.name, id(t.Name, to))
+					}
+				}
+			}
+		}
+		for i, u := range sortedUsages(usages[t.Name]) {
+			fmt.Fprintf(out, "    %s[/\"%s<br/>chart: %s\"/]\n", id(t.Name, "usage", fmt.Sprint(i)), u.command(t.Name), u.chart)
+		}
+		fmt.Fprintf(out, "  end\n")
+	}
+}
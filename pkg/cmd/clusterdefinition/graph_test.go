@@ -0,0 +1,128 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package clusterdefinition
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+)
+
+var _ = Describe("clusterdefinition graph", func() {
+	var (
+		cd     *kbappsv1.ClusterDefinition
+		usages map[string][]topologyUsage
+	)
+
+	BeforeEach(func() {
+		cd = &kbappsv1.ClusterDefinition{}
+		cd.Name = "mysql"
+		cd.Spec.Topologies = []kbappsv1.ClusterTopology{
+			{
+				Name:    "semisync",
+				Default: true,
+				Components: []kbappsv1.ClusterTopologyComponent{
+					{Name: "mysql", CompDef: "mysql-8.0"},
+				},
+			},
+			{
+				Name: "semisync-proxysql",
+				Components: []kbappsv1.ClusterTopologyComponent{
+					{Name: "mysql", CompDef: "mysql-8.0"},
+					{Name: "proxysql", CompDef: "proxysql-mysql"},
+					{Name: "orc", CompDef: "orchestrator"},
+				},
+				Orders: &kbappsv1.ClusterTopologyOrders{
+					Provision: []string{"mysql", "proxysql, orc"},
+					Terminate: []string{"proxysql,orc", "mysql"},
+				},
+			},
+			{
+				Name: "sharding",
+				Components: []kbappsv1.ClusterTopologyComponent{
+					{Name: "proxy", CompDef: "mysql-proxy"},
+				},
+				Shardings: []kbappsv1.ClusterTopologySharding{
+					{Name: "shard", ShardingDef: "mysql-sharding"},
+				},
+				Orders: &kbappsv1.ClusterTopologyOrders{
+					Provision: []string{"shard", "proxy"},
+				},
+			},
+		}
+		usages = map[string][]topologyUsage{
+			"semisync": {{clusterType: "mysql", chart: "mysql-cluster-1.0.0"}},
+		}
+	})
+
+	It("parse order", func() {
+		Expect(parseOrder(nil)).Should(BeEmpty())
+		Expect(parseOrder([]string{"a", " b, c ", ""})).Should(Equal([][]string{{"a"}, {"b", "c"}}))
+	})
+
+	It("get cluster definition and topology from manifests", func() {
+		manifests := map[string]string{
+			"templates/account.yaml": "apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: test\n",
+			"templates/cluster.yaml": "apiVersion: apps.kubeblocks.io/v1\nkind: Cluster\nmetadata:\n  name: test\nspec:\n  clusterDef: mysql\n  topology: semisync\n",
+		}
+		clusterDef, topology := getClusterDefAndTopology(manifests)
+		Expect(clusterDef).Should(Equal("mysql"))
+		Expect(topology).Should(Equal("semisync"))
+	})
+
+	It("print ascii graph", func() {
+		out := &bytes.Buffer{}
+		printASCIIGraph(out, cd, usages, func(pattern string) string {
+			if pattern == "mysql-8.0" {
+				return "mysql-8.0.30"
+			}
+			return ""
+		})
+		Expect(out.String()).Should(ContainSubstring("Topology: semisync (default)"))
+		Expect(out.String()).Should(ContainSubstring("└── mysql [mysql-8.0] => mysql-8.0.30"))
+		Expect(out.String()).Should(ContainSubstring("├── proxysql [proxysql-mysql] => <none>"))
+		Expect(out.String()).Should(ContainSubstring("[mysql] --> [proxysql, orc]"))
+		Expect(out.String()).Should(ContainSubstring("[proxysql, orc] --> [mysql]"))
+		Expect(out.String()).Should(ContainSubstring("Shardings:\n  └── shard [mysql-sharding]"))
+		Expect(out.String()).Should(ContainSubstring("kbcli cluster create mysql --topology semisync (chart: mysql-cluster-1.0.0)"))
+	})
+
+	It("print dot graph", func() {
+		out := &bytes.Buffer{}
+		printDotGraph(out, cd, usages)
+		Expect(out.String()).Should(HavePrefix(`digraph "mysql" {`))
+		Expect(out.String()).Should(ContainSubstring(`subgraph "cluster_semisync-proxysql" {`))
+		Expect(out.String()).Should(ContainSubstring(`"semisync-proxysql/mysql" -> "semisync-proxysql/orc" [label="provision", color="darkgreen"];`))
+		Expect(out.String()).Should(ContainSubstring(`"semisync-proxysql/orc" -> "semisync-proxysql/mysql" [label="terminate", color="red"];`))
+		Expect(out.String()).Should(ContainSubstring(`"sharding/shard" [label="shard\\nmysql-sharding", shape=box3d];`))
+	})
+
+	It("print mermaid graph", func() {
+		out := &bytes.Buffer{}
+		printMermaidGraph(out, cd, usages)
+		Expect(out.String()).Should(HavePrefix("flowchart LR\n"))
+		Expect(out.String()).Should(ContainSubstring("semisync_proxysql_mysql -->|provision| semisync_proxysql_proxysql"))
+		Expect(out.String()).Should(ContainSubstring(`sharding_shard[["shard<br/>mysql-sharding"]]`))
+		Expect(out.String()).Should(ContainSubstring("kbcli cluster create mysql --topology semisync<br/>chart: mysql-cluster-1.0.0"))
+	})
+})
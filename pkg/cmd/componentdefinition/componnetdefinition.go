@@ -54,6 +54,7 @@ func NewComponentDefinitionCmd(f cmdutil.Factory, streams genericiooptions.IOStr
 
 	cmd.AddCommand(NewListCmd(f, streams))
 	cmd.AddCommand(NewDescribeCmd(f, streams))
+	cmd.AddCommand(NewDiffCmd(f, streams))
 	return cmd
 }
 
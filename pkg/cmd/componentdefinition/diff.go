@@ -0,0 +1,441 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package componentdefinition
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/repo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/helm"
+)
+
+var diffExample = templates.Examples(`
+		# show the differences between two ComponentDefinitions
+		kbcli componentdefinition diff apecloud-mysql-1.0.0 apecloud-mysql-1.0.1
+
+		# show the differences of the ComponentDefinitions between two addon versions
+		kbcli componentdefinition diff --addon mysql --from-version 1.0.0 --to-version 1.0.1`)
+
+const (
+	impactNone      = "None"
+	impactRestart   = "Restart"
+	impactMigration = "Migration"
+)
+
+// specFieldImpacts is the impact on the running components when the field of the ComponentDefinition spec is changed,
+// the fields not listed here have no impact on the running pods.
+var specFieldImpacts = map[string]string{
+	"serviceKind":      impactMigration,
+	"volumes":          impactMigration,
+	"systemAccounts":   impactMigration,
+	"serviceVersion":   impactRestart,
+	"runtime":          impactRestart,
+	"vars":             impactRestart,
+	"configs":          impactRestart,
+	"scripts":          impactRestart,
+	"logConfigs":       impactRestart,
+	"hostNetwork":      impactRestart,
+	"tls":              impactRestart,
+	"lifecycleActions": impactRestart,
+	"exporter":         impactRestart,
+	"policyRules":      impactRestart,
+	"roles":            impactRestart,
+}
+
+// fieldChange is a changed leaf field of the spec.
+type fieldChange struct {
+	Path string
+	From interface{}
+	To   interface{}
+}
+
+// fieldGroupDiff is the changes of a top level field of the spec.
+type fieldGroupDiff struct {
+	Field   string
+	Impact  string
+	Changes []fieldChange
+}
+
+// compDefDiff is the diff of a pair of ComponentDefinitions.
+type compDefDiff struct {
+	From   string
+	To     string
+	Groups []fieldGroupDiff
+}
+
+type diffOptions struct {
+	factory cmdutil.Factory
+	dynamic dynamic.Interface
+
+	names       []string
+	addon       string
+	fromVersion string
+	toVersion   string
+	genericiooptions.IOStreams
+}
+
+func NewDiffCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &diffOptions{
+		factory:   f,
+		IOStreams: streams,
+	}
+	cmd := &cobra.Command{
+		Use:               "diff [FROM TO]",
+		Short:             "Show the differences between ComponentDefinitions and the affected clusters.",
+		Example:           diffExample,
+		ValidArgsFunction: util.ResourceNameCompletionFunc(f, types.CompDefGVR()),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.complete(args))
+			cmdutil.CheckErr(o.validate())
+			cmdutil.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVar(&o.addon, "addon", "", "Compare the ComponentDefinitions rendered from the addon chart of two versions")
+	cmd.Flags().StringVar(&o.fromVersion, "from-version", "", "The addon version to compare from, it's required with --addon")
+	cmd.Flags().StringVar(&o.toVersion, "to-version", "", "The addon version to compare to, it's required with --addon")
+	return cmd
+}
+
+func (o *diffOptions) complete(args []string) error {
+	var err error
+	o.names = args
+	o.dynamic, err = o.factory.DynamicClient()
+	return err
+}
+
+func (o *diffOptions) validate() error {
+	if o.addon == "" {
+		if len(o.names) != 2 {
+			return fmt.Errorf("two ComponentDefinition names should be specified")
+		}
+		return nil
+	}
+	if len(o.names) > 0 {
+		return fmt.Errorf("ComponentDefinition names can not be specified with --addon")
+	}
+	if o.fromVersion == "" || o.toVersion == "" {
+		return fmt.Errorf("--from-version and --to-version are required with --addon")
+	}
+	return nil
+}
+
+func (o *diffOptions) run() error {
+	var (
+		from, to []*kbappsv1.ComponentDefinition
+		err      error
+	)
+	if o.addon == "" {
+		from, to = make([]*kbappsv1.ComponentDefinition, 1), make([]*kbappsv1.ComponentDefinition, 1)
+		from[0], to[0] = &kbappsv1.ComponentDefinition{}, &kbappsv1.ComponentDefinition{}
+		if err = util.GetK8SClientObject(o.dynamic, from[0], types.CompDefGVR(), "", o.names[0]); err != nil {
+			return err
+		}
+		if err = util.GetK8SClientObject(o.dynamic, to[0], types.CompDefGVR(), "", o.names[1]); err != nil {
+			return err
+		}
+	} else {
+		if from, err = renderAddonCompDefs(o.addon, o.fromVersion); err != nil {
+			return err
+		}
+		if to, err = renderAddonCompDefs(o.addon, o.toVersion); err != nil {
+			return err
+		}
+	}
+
+	clusters, err := cluster.GetCompDefClusters(o.dynamic)
+	if err != nil {
+		return err
+	}
+	pairs, added, removed := pairCompDefs(from, to, o.fromVersion, o.toVersion)
+	for _, name := range removed {
+		fmt.Fprintf(o.Out, "%s\n", printer.BoldRed("- ComponentDefinition "+name+" is removed"))
+		printAffectedClusters(o.Out, clusters[name])
+	}
+	for _, name := range added {
+		fmt.Fprintf(o.Out, "%s\n", printer.BoldGreen("+ ComponentDefinition "+name+" is added"))
+	}
+	for _, p := range pairs {
+		d, err := diffCompDefs(p[0], p[1])
+		if err != nil {
+			return err
+		}
+		printCompDefDiff(o.Out, d, clusters[d.From])
+	}
+	return nil
+}
+
+// renderAddonCompDefs renders the addon chart of the version and returns the ComponentDefinitions in it.
+func renderAddonCompDefs(addon, version string) ([]*kbappsv1.ComponentDefinition, error) {
+	if err := helm.AddRepo(&repo.Entry{Name: types.ClusterChartsRepoName, URL: types.ClusterChartsRepoURL}); err != nil {
+		return nil, err
+	}
+	opts := helm.GetTemplateInstallOps(addon, fmt.Sprintf("%s/%s", types.ClusterChartsRepoName, addon), version, metav1.NamespaceDefault)
+	opts.ValueOpts = &values.Options{}
+	release, err := opts.Install(helm.NewFakeConfig(metav1.NamespaceDefault))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render addon %s-%s: %w", addon, version, err)
+	}
+	return parseCompDefsFromManifest(release.Manifest)
+}
+
+func parseCompDefsFromManifest(manifest string) ([]*kbappsv1.ComponentDefinition, error) {
+	var compDefs []*kbappsv1.ComponentDefinition
+	for _, m := range releaseutil.SplitManifests(manifest) {
+		meta := metav1.TypeMeta{}
+		if err := yaml.Unmarshal([]byte(m), &meta); err != nil || meta.Kind != types.KindComponentDef {
+			continue
+		}
+		compDef := &kbappsv1.ComponentDefinition{}
+		if err := yaml.Unmarshal([]byte(m), compDef); err != nil {
+			return nil, err
+		}
+		compDefs = append(compDefs, compDef)
+	}
+	sort.Slice(compDefs, func(i, j int) bool {
+		return compDefs[i].Name < compDefs[j].Name
+	})
+	return compDefs, nil
+}
+
+// pairCompDefs pairs the ComponentDefinitions by name, the addon version in the name is ignored when pairing.
+func pairCompDefs(from, to []*kbappsv1.ComponentDefinition, fromVersion, toVersion string) ([][2]*kbappsv1.ComponentDefinition, []string, []string) {
+	if len(from) == 1 && len(to) == 1 {
+		return [][2]*kbappsv1.ComponentDefinition{{from[0], to[0]}}, nil, nil
+	}
+	trimVersion := func(name, version string) string {
+		if version == "" {
+			return name
+		}
+		return strings.TrimSuffix(name, "-"+version)
+	}
+	toMap := map[string]*kbappsv1.ComponentDefinition{}
+	for _, c := range to {
+		toMap[trimVersion(c.Name, toVersion)] = c
+	}
+	var (
+		pairs          [][2]*kbappsv1.ComponentDefinition
+		added, removed []string
+	)
+	for _, c := range from {
+		key := trimVersion(c.Name, fromVersion)
+		if t, ok := toMap[key]; ok {
+			pairs = append(pairs, [2]*kbappsv1.ComponentDefinition{c, t})
+			delete(toMap, key)
+			continue
+		}
+		removed = append(removed, c.Name)
+	}
+	for _, c := range toMap {
+		added = append(added, c.Name)
+	}
+	sort.Strings(added)
+	return pairs, added, removed
+}
+
+// diffCompDefs diffs the specs of two ComponentDefinitions and groups the changes by the top level fields.
+func diffCompDefs(from, to *kbappsv1.ComponentDefinition) (*compDefDiff, error) {
+	fromSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&from.Spec)
+	if err != nil {
+		return nil, err
+	}
+	toSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&to.Spec)
+	if err != nil {
+		return nil, err
+	}
+	d := &compDefDiff{From: from.Name, To: to.Name}
+	fields := map[string]struct{}{}
+	for k := range fromSpec {
+		fields[k] = struct{}{}
+	}
+	for k := range toSpec {
+		fields[k] = struct{}{}
+	}
+	for field := range fields {
+		var changes []fieldChange
+		diffValue(field, fromSpec[field], toSpec[field], &changes)
+		if len(changes) == 0 {
+			continue
+		}
+		impact, ok := specFieldImpacts[field]
+		if !ok {
+			impact = impactNone
+		}
+		sort.Slice(changes, func(i, j int) bool {
+			return changes[i].Path < changes[j].Path
+		})
+		d.Groups = append(d.Groups, fieldGroupDiff{Field: field, Impact: impact, Changes: changes})
+	}
+	impactOrder := map[string]int{impactMigration: 0, impactRestart: 1, impactNone: 2}
+	sort.Slice(d.Groups, func(i, j int) bool {
+		if d.Groups[i].Impact != d.Groups[j].Impact {
+			return impactOrder[d.Groups[i].Impact] < impactOrder[d.Groups[j].Impact]
+		}
+		return d.Groups[i].Field < d.Groups[j].Field
+	})
+	return d, nil
+}
+
+// diffValue compares the values recursively and records the changed leaf fields, the items of
+// a list are identified by their names if all of them have a name.
+func diffValue(path string, from, to interface{}, changes *[]fieldChange) {
+	if reflect.DeepEqual(from, to) {
+		return
+	}
+	fromMap, ok1 := from.(map[string]interface{})
+	toMap, ok2 := to.(map[string]interface{})
+	if ok1 && ok2 {
+		keys := map[string]struct{}{}
+		for k := range fromMap {
+			keys[k] = struct{}{}
+		}
+		for k := range toMap {
+			keys[k] = struct{}{}
+		}
+		for k := range keys {
+			diffValue(path+"."+k, fromMap[k], toMap[k], changes)
+		}
+		return
+	}
+	fromList, ok1 := from.([]interface{})
+	toList, ok2 := to.([]interface{})
+	if ok1 && ok2 {
+		fromNamed, ok1 := namedItems(fromList)
+		toNamed, ok2 := namedItems(toList)
+		if ok1 && ok2 {
+			names := map[string]struct{}{}
+			for k := range fromNamed {
+				names[k] = struct{}{}
+			}
+			for k := range toNamed {
+				names[k] = struct{}{}
+			}
+			for name := range names {
+				diffValue(fmt.Sprintf("%s[%s]", path, name), fromNamed[name], toNamed[name], changes)
+			}
+			return
+		}
+		if len(fromList) == len(toList) {
+			for i := range fromList {
+				diffValue(fmt.Sprintf("%s[%d]", path, i), fromList[i], toList[i], changes)
+			}
+			return
+		}
+	}
+	*changes = append(*changes, fieldChange{Path: path, From: from, To: to})
+}
+
+func namedItems(list []interface{}) (map[string]interface{}, bool) {
+	res := map[string]interface{}{}
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := m["name"].(string)
+		if !ok || name == "" {
+			return nil, false
+		}
+		res[name] = item
+	}
+	return res, true
+}
+
+func printCompDefDiff(out io.Writer, d *compDefDiff, clusters []string) {
+	fmt.Fprintf(out, "ComponentDefinition: %s -> %s\n", d.From, d.To)
+	if len(d.Groups) == 0 {
+		fmt.Fprintf(out, "  No differences found\n\n")
+		return
+	}
+	for _, g := range d.Groups {
+		impact := g.Impact
+		switch g.Impact {
+		case impactMigration:
+			impact = printer.BoldRed(impact)
+		case impactRestart:
+			impact = printer.BoldYellow(impact)
+		}
+		fmt.Fprintf(out, "\n  %s (impact: %s)\n", g.Field, impact)
+		for _, c := range g.Changes {
+			switch {
+			case c.From == nil:
+				fmt.Fprintf(out, "    %s %s: %s\n", printer.BoldGreen("+"), c.Path, formatValue(c.To))
+			case c.To == nil:
+				fmt.Fprintf(out, "    %s %s: %s\n", printer.BoldRed("-"), c.Path, formatValue(c.From))
+			default:
+				fmt.Fprintf(out, "    %s %s: %s -> %s\n", printer.BoldYellow("~"), c.Path, formatValue(c.From), formatValue(c.To))
+			}
+		}
+	}
+	fmt.Fprintln(out)
+	for _, g := range d.Groups {
+		if g.Impact != impactNone {
+			fmt.Fprintf(out, "%s\n", printer.BoldYellow("Warning: the changes will restart the pods or require data migration of the affected clusters"))
+			break
+		}
+	}
+	printAffectedClusters(out, clusters)
+}
+
+func printAffectedClusters(out io.Writer, clusters []string) {
+	if len(clusters) == 0 {
+		fmt.Fprintf(out, "Affected Clusters: %s\n\n", types.None)
+		return
+	}
+	fmt.Fprintf(out, "Affected Clusters:\n")
+	for _, c := range clusters {
+		fmt.Fprintf(out, "  %s\n", c)
+	}
+	fmt.Fprintln(out)
+}
+
+func formatValue(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package componentdefinition
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("componentdefinition diff", func() {
+	const newCompDefName = testing.CompDefName + "-new"
+
+	var (
+		from, to *kbappsv1.ComponentDefinition
+	)
+
+	BeforeEach(func() {
+		from = testing.FakeCompDef()
+		to = testing.FakeCompDef()
+		to.Name = newCompDefName
+		to.Spec.Runtime.Containers[0].Image = "bar:new"
+		to.Spec.Roles = append(to.Spec.Roles, kbappsv1.ReplicaRole{Name: "witness"})
+	})
+
+	It("validate", func() {
+		o := &diffOptions{}
+		Expect(o.validate()).Should(HaveOccurred())
+		o.names = []string{"a", "b"}
+		Expect(o.validate()).Should(Succeed())
+		o.addon = "mysql"
+		Expect(o.validate()).Should(HaveOccurred())
+		o.names = nil
+		Expect(o.validate()).Should(HaveOccurred())
+		o.fromVersion, o.toVersion = "1.0.0", "1.0.1"
+		Expect(o.validate()).Should(Succeed())
+	})
+
+	It("diff component definitions", func() {
+		d, err := diffCompDefs(from, to)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(d.Groups).ShouldNot(BeEmpty())
+
+		var runtimeGroup *fieldGroupDiff
+		for i := range d.Groups {
+			if d.Groups[i].Field == "runtime" {
+				runtimeGroup = &d.Groups[i]
+			}
+		}
+		Expect(runtimeGroup).ShouldNot(BeNil())
+		Expect(runtimeGroup.Impact).Should(Equal(impactRestart))
+		Expect(runtimeGroup.Changes).Should(HaveLen(1))
+		Expect(runtimeGroup.Changes[0].Path).Should(Equal("runtime.containers[foo].image"))
+		Expect(runtimeGroup.Changes[0].From).Should(Equal("bar"))
+		Expect(runtimeGroup.Changes[0].To).Should(Equal("bar:new"))
+
+		out := &bytes.Buffer{}
+		printCompDefDiff(out, d, []string{"default/mycluster(mysql)"})
+		Expect(out.String()).Should(ContainSubstring("runtime.containers[foo].image: bar -> bar:new"))
+		Expect(out.String()).Should(ContainSubstring("default/mycluster(mysql)"))
+
+		d, err = diffCompDefs(from, from)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(d.Groups).Should(BeEmpty())
+	})
+
+	It("pair component definitions", func() {
+		a, b, c := testing.FakeCompDef(), testing.FakeCompDef(), testing.FakeCompDef()
+		a.Name, b.Name, c.Name = "mysql-1.0.0", "mysql-proxy-1.0.0", "mysql-1.0.1"
+		pairs, added, removed := pairCompDefs([]*kbappsv1.ComponentDefinition{a, b}, []*kbappsv1.ComponentDefinition{c}, "1.0.0", "1.0.1")
+		Expect(pairs).Should(HaveLen(1))
+		Expect(pairs[0][1].Name).Should(Equal("mysql-1.0.1"))
+		Expect(added).Should(BeEmpty())
+		Expect(removed).Should(Equal([]string{"mysql-proxy-1.0.0"}))
+	})
+
+	It("parse component definitions from manifest", func() {
+		manifest := `---
+apiVersion: apps.kubeblocks.io/v1
+kind: ComponentDefinition
+metadata:
+  name: mysql-1.0.0
+spec:
+  serviceVersion: 8.0.30
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: mysql-scripts
+`
+		compDefs, err := parseCompDefsFromManifest(manifest)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(compDefs).Should(HaveLen(1))
+		Expect(compDefs[0].Spec.ServiceVersion).Should(Equal("8.0.30"))
+	})
+
+	It("run", func() {
+		streams, _, out, _ := genericiooptions.NewTestIOStreams()
+		tf := testing.NewTestFactory(testing.Namespace)
+		defer tf.Cleanup()
+		Expect(NewDiffCmd(tf, streams)).ShouldNot(BeNil())
+
+		comp := &kbappsv1.Component{}
+		comp.Name = testing.ClusterName + "-" + testing.ComponentName
+		comp.Namespace = testing.Namespace
+		comp.Labels = map[string]string{
+			constant.AppInstanceLabelKey:    testing.ClusterName,
+			constant.KBAppComponentLabelKey: testing.ComponentName,
+		}
+		comp.Spec.CompDef = testing.CompDefName
+		tf.FakeDynamicClient = testing.FakeDynamicClient(from, to, comp)
+
+		o := &diffOptions{factory: tf, IOStreams: streams}
+		Expect(o.complete([]string{testing.CompDefName, newCompDefName})).Should(Succeed())
+		o.dynamic = tf.FakeDynamicClient
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring(testing.Namespace + "/" + testing.ClusterName + "(" + testing.ComponentName + ")"))
+	})
+})
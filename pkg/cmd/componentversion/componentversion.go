@@ -47,6 +47,7 @@ func NewComponentVersionCmd(f cmdutil.Factory, streams genericiooptions.IOStream
 
 	cmd.AddCommand(NewListCmd(f, streams))
 	cmd.AddCommand(NewDescribeCmd(f, streams))
+	cmd.AddCommand(NewMatrixCmd(f, streams))
 	return cmd
 }
 
@@ -0,0 +1,430 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package componentversion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var (
+	matrixLong = templates.LongDesc(`
+		Show the compatibility matrix of the ComponentDefinitions and service versions built from
+		the compatibility rules of ComponentVersions.
+
+		The status of each service version is one of:
+		  Supported:   the service version can be used to create or upgrade clusters.
+		  Superseded:  a newer patch release of the same major and minor version is provided.
+		  Unsupported: the addon does not support the installed KubeBlocks version, no available
+		               ComponentDefinition matches the pattern, or the service version is not
+		               served by the ComponentVersion.`)
+
+	matrixExample = templates.Examples(`
+		# show the compatibility matrix of all ComponentVersions
+		kbcli componentversion matrix
+
+		# show the compatibility matrix of the ComponentVersions provided by the addon
+		kbcli componentversion matrix --addon mysql
+
+		# publish the compatibility matrix in markdown format
+		kbcli componentversion matrix -o markdown > support-matrix.md`)
+)
+
+const (
+	matrixFormatTable    = "table"
+	matrixFormatJSON     = "json"
+	matrixFormatMarkdown = "markdown"
+
+	versionSupported   = "Supported"
+	versionSuperseded  = "Superseded"
+	versionUnsupported = "Unsupported"
+)
+
+// matrixEntry is a row of the compatibility matrix.
+type matrixEntry struct {
+	ComponentVersion string            `json:"componentVersion"`
+	Addon            string            `json:"addon,omitempty"`
+	CompDefPatterns  []string          `json:"compDefPatterns"`
+	CompDefs         []string          `json:"compDefs"`
+	Release          string            `json:"release"`
+	ServiceVersion   string            `json:"serviceVersion"`
+	Images           map[string]string `json:"images,omitempty"`
+	Status           string            `json:"status"`
+	Reason           string            `json:"reason,omitempty"`
+	Clusters         []string          `json:"clusters,omitempty"`
+}
+
+type matrixOptions struct {
+	factory cmdutil.Factory
+	client  clientset.Interface
+	dynamic dynamic.Interface
+
+	addon  string
+	output string
+	genericiooptions.IOStreams
+}
+
+func NewMatrixCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &matrixOptions{
+		factory:   f,
+		IOStreams: streams,
+	}
+	cmd := &cobra.Command{
+		Use:     "matrix",
+		Short:   "Show the compatibility matrix of ComponentDefinitions and service versions.",
+		Long:    matrixLong,
+		Example: matrixExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.complete())
+			cmdutil.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVar(&o.addon, "addon", "", "Only show the ComponentVersions provided by the specified addon")
+	cmd.Flags().StringVarP(&o.output, "output", "o", matrixFormatTable,
+		fmt.Sprintf("Output format. Allowed values: %s, %s, %s", matrixFormatTable, matrixFormatJSON, matrixFormatMarkdown))
+	util.CheckErr(cmd.RegisterFlagCompletionFunc("addon", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return util.CompGetResourceWithLabels(f, cmd, util.GVRToString(types.AddonGVR()), nil, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}))
+	util.CheckErr(cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{matrixFormatTable, matrixFormatJSON, matrixFormatMarkdown}, cobra.ShellCompDirectiveNoFileComp
+	}))
+	return cmd
+}
+
+func (o *matrixOptions) complete() error {
+	var err error
+	switch o.output {
+	case matrixFormatTable, matrixFormatJSON, matrixFormatMarkdown:
+	default:
+		return fmt.Errorf("invalid output format %s, only support %s, %s and %s", o.output, matrixFormatTable, matrixFormatJSON, matrixFormatMarkdown)
+	}
+	if o.client, err = o.factory.KubernetesClientSet(); err != nil {
+		return err
+	}
+	o.dynamic, err = o.factory.DynamicClient()
+	return err
+}
+
+func (o *matrixOptions) run() error {
+	entries, err := o.buildMatrix()
+	if err != nil {
+		return err
+	}
+	return printMatrix(entries, o.output, o.Out)
+}
+
+func (o *matrixOptions) buildMatrix() ([]*matrixEntry, error) {
+	listOpts := metav1.ListOptions{}
+	if o.addon != "" {
+		listOpts.LabelSelector = fmt.Sprintf("%s=%s", types.AddonNameLabelKey, o.addon)
+	}
+	cmpvList, err := o.dynamic.Resource(types.ComponentVersionsGVR()).List(context.TODO(), listOpts)
+	if err != nil {
+		return nil, err
+	}
+	compDefList, err := o.dynamic.Resource(types.CompDefGVR()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	clusterList, err := o.dynamic.Resource(types.ClusterGVR()).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		compDefs []*kbappsv1.ComponentDefinition
+		clusters []*kbappsv1.Cluster
+		entries  []*matrixEntry
+	)
+	for i := range compDefList.Items {
+		compDef := &kbappsv1.ComponentDefinition{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(compDefList.Items[i].Object, compDef); err != nil {
+			return nil, err
+		}
+		compDefs = append(compDefs, compDef)
+	}
+	for i := range clusterList.Items {
+		c := &kbappsv1.Cluster{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(clusterList.Items[i].Object, c); err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, c)
+	}
+	kbVersion := o.getKubeBlocksVersion()
+	for i := range cmpvList.Items {
+		cmpv := &kbappsv1.ComponentVersion{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(cmpvList.Items[i].Object, cmpv); err != nil {
+			return nil, err
+		}
+		addonName := cmpv.Labels[types.AddonNameLabelKey]
+		entries = append(entries, buildComponentVersionMatrix(cmpv, compDefs, clusters, o.checkAddonSupported(addonName, kbVersion))...)
+	}
+	return entries, nil
+}
+
+func (o *matrixOptions) getKubeBlocksVersion() string {
+	v, err := util.GetVersionInfo(o.client)
+	if err != nil {
+		return ""
+	}
+	return v.KubeBlocks
+}
+
+// checkAddonSupported returns the reason if the addon does not support the installed KubeBlocks version.
+func (o *matrixOptions) checkAddonSupported(addonName, kbVersion string) string {
+	if addonName == "" || kbVersion == "" {
+		return ""
+	}
+	addon := &extensionsv1alpha1.Addon{}
+	if err := util.GetK8SClientObject(o.dynamic, addon, types.AddonGVR(), "", addonName); err != nil {
+		return ""
+	}
+	constraint := addon.Annotations[types.KBVersionValidateAnnotationKey]
+	if constraint == "" {
+		return ""
+	}
+	for _, v := range strings.Split(kbVersion, ",") {
+		if !kubeBlocksVersionMatched(constraint, v) {
+			return fmt.Sprintf("addon %s requires KubeBlocks %s", addonName, constraint)
+		}
+	}
+	return ""
+}
+
+// kubeBlocksVersionMatched checks the KubeBlocks version against the constraint, the pre-release
+// of the KubeBlocks version is ignored. The version is treated as matched if it can not be parsed.
+func kubeBlocksVersionMatched(constraint, kbVersion string) bool {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return true
+	}
+	v, err := semver.NewVersion(strings.TrimSpace(kbVersion))
+	if err != nil {
+		return true
+	}
+	core, _ := v.SetPrerelease("")
+	return c.Check(&core)
+}
+
+// buildComponentVersionMatrix builds the matrix entries for each compatibility rule and release of the ComponentVersion.
+func buildComponentVersionMatrix(cmpv *kbappsv1.ComponentVersion,
+	compDefs []*kbappsv1.ComponentDefinition,
+	clusters []*kbappsv1.Cluster,
+	unsupportedReason string) []*matrixEntry {
+	releases := map[string]kbappsv1.ComponentVersionRelease{}
+	for _, r := range cmpv.Spec.Releases {
+		releases[r.Name] = r
+	}
+	var servedVersions []string
+	if cmpv.Status.ServiceVersions != "" {
+		servedVersions = strings.Split(cmpv.Status.ServiceVersions, ",")
+	}
+
+	var entries []*matrixEntry
+	for _, rule := range cmpv.Spec.CompatibilityRules {
+		var (
+			matchedCompDefs   []string
+			availableCompDefs int
+			ruleEntries       []*matrixEntry
+		)
+		for _, compDef := range compDefs {
+			if !cluster.CompatibleComponentDefs(rule.CompDefs, compDef.Name) {
+				continue
+			}
+			matchedCompDefs = append(matchedCompDefs, compDef.Name)
+			if compDef.Status.Phase == kbappsv1.AvailablePhase {
+				availableCompDefs++
+			}
+		}
+		sort.Strings(matchedCompDefs)
+		for _, name := range rule.Releases {
+			release, ok := releases[name]
+			if !ok {
+				continue
+			}
+			entry := &matrixEntry{
+				ComponentVersion: cmpv.Name,
+				Addon:            cmpv.Labels[types.AddonNameLabelKey],
+				CompDefPatterns:  rule.CompDefs,
+				CompDefs:         matchedCompDefs,
+				Release:          release.Name,
+				ServiceVersion:   release.ServiceVersion,
+				Images:           release.Images,
+				Status:           versionSupported,
+				Clusters:         getPinnedClusters(clusters, rule.CompDefs, release.ServiceVersion),
+			}
+			switch {
+			case unsupportedReason != "":
+				entry.Status, entry.Reason = versionUnsupported, unsupportedReason
+			case len(matchedCompDefs) == 0:
+				entry.Status, entry.Reason = versionUnsupported, "no ComponentDefinition matches the patterns"
+			case availableCompDefs == 0:
+				entry.Status, entry.Reason = versionUnsupported, "no available ComponentDefinition matches the patterns"
+			case len(servedVersions) > 0 && !containsVersion(servedVersions, release.ServiceVersion):
+				entry.Status, entry.Reason = versionUnsupported, "not served by the ComponentVersion"
+			}
+			ruleEntries = append(ruleEntries, entry)
+		}
+		markSupersededVersions(ruleEntries)
+		entries = append(entries, ruleEntries...)
+	}
+	return entries
+}
+
+// markSupersededVersions marks the supported versions as superseded if a newer supported patch version is provided by the same rule.
+func markSupersededVersions(entries []*matrixEntry) {
+	latest := map[string]*semver.Version{}
+	minorKey := func(v *semver.Version) string {
+		return fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+	}
+	for _, e := range entries {
+		v, err := semver.NewVersion(e.ServiceVersion)
+		if err != nil || e.Status != versionSupported {
+			continue
+		}
+		if l, ok := latest[minorKey(v)]; !ok || l.LessThan(v) {
+			latest[minorKey(v)] = v
+		}
+	}
+	for _, e := range entries {
+		v, err := semver.NewVersion(e.ServiceVersion)
+		if err != nil || e.Status != versionSupported {
+			continue
+		}
+		if l := latest[minorKey(v)]; v.LessThan(l) {
+			e.Status, e.Reason = versionSuperseded, fmt.Sprintf("superseded by %s", l.Original())
+		}
+	}
+}
+
+// getPinnedClusters returns the clusters whose components are pinned to the service version with a compatible ComponentDefinition.
+func getPinnedClusters(clusters []*kbappsv1.Cluster, compDefPatterns []string, serviceVersion string) []string {
+	var res []string
+	pinned := func(spec kbappsv1.ClusterComponentSpec) bool {
+		return spec.ServiceVersion == serviceVersion && cluster.CompatibleComponentDefs(compDefPatterns, spec.ComponentDef)
+	}
+	for _, c := range clusters {
+		matched := false
+		for _, spec := range c.Spec.ComponentSpecs {
+			matched = matched || pinned(spec)
+		}
+		for _, sharding := range c.Spec.Shardings {
+			matched = matched || pinned(sharding.Template)
+		}
+		if matched {
+			res = append(res, fmt.Sprintf("%s/%s", c.Namespace, c.Name))
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func containsVersion(versions []string, version string) bool {
+	for _, v := range versions {
+		if strings.TrimSpace(v) == version {
+			return true
+		}
+	}
+	return false
+}
+
+func printMatrix(entries []*matrixEntry, format string, out io.Writer) error {
+	switch format {
+	case matrixFormatJSON:
+		if entries == nil {
+			entries = []*matrixEntry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+	case matrixFormatMarkdown:
+		fmt.Fprintln(out, "| COMPONENT-VERSION | COMPONENT-DEFS | SERVICE-VERSION | IMAGES | STATUS | CLUSTERS |")
+		fmt.Fprintln(out, "|---|---|---|---|---|---|")
+		for _, e := range entries {
+			fmt.Fprintf(out, "| %s | %s | %s | %s | %s | %s |\n", e.ComponentVersion,
+				escapeMarkdown(strings.Join(e.CompDefPatterns, ", ")), e.ServiceVersion,
+				escapeMarkdown(formatImages(e.Images, "<br>")), formatStatus(e), strings.Join(e.Clusters, "<br>"))
+		}
+	default:
+		if len(entries) == 0 {
+			fmt.Fprintln(out, "No ComponentVersions found")
+			return nil
+		}
+		tbl := printer.NewTablePrinter(out)
+		tbl.SetHeader("COMPONENT-VERSION", "COMPONENT-DEFS", "SERVICE-VERSION", "IMAGES", "STATUS", "CLUSTERS")
+		for _, e := range entries {
+			status := formatStatus(e)
+			switch e.Status {
+			case versionSuperseded:
+				status = printer.BoldYellow(status)
+			case versionUnsupported:
+				status = printer.BoldRed(status)
+			}
+			tbl.AddRow(e.ComponentVersion, strings.Join(e.CompDefPatterns, ","), e.ServiceVersion,
+				formatImages(e.Images, ","), status, util.CheckEmpty(strings.Join(e.Clusters, ",")))
+		}
+		tbl.Print()
+	}
+	return nil
+}
+
+func formatStatus(e *matrixEntry) string {
+	if e.Reason == "" {
+		return e.Status
+	}
+	return fmt.Sprintf("%s (%s)", e.Status, e.Reason)
+}
+
+func formatImages(images map[string]string, sep string) string {
+	var res []string
+	for name, image := range images {
+		res = append(res, fmt.Sprintf("%s=%s", name, image))
+	}
+	sort.Strings(res)
+	return strings.Join(res, sep)
+}
+
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
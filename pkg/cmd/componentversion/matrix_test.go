@@ -0,0 +1,127 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package componentversion
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("componentversion matrix", func() {
+	var (
+		cmpv     *kbappsv1.ComponentVersion
+		compDefs []*kbappsv1.ComponentDefinition
+		clusters []*kbappsv1.Cluster
+	)
+
+	BeforeEach(func() {
+		cmpv = &kbappsv1.ComponentVersion{}
+		cmpv.Name = "mysql"
+		cmpv.Spec.CompatibilityRules = []kbappsv1.ComponentVersionCompatibilityRule{
+			{CompDefs: []string{testing.CompDefName}, Releases: []string{"r1", "r2", "r3"}},
+			{CompDefs: []string{"not-exist"}, Releases: []string{"r3"}},
+		}
+		cmpv.Spec.Releases = []kbappsv1.ComponentVersionRelease{
+			{Name: "r1", ServiceVersion: "8.0.30", Images: map[string]string{"mysql": "mysql:8.0.30"}},
+			{Name: "r2", ServiceVersion: "8.0.32", Images: map[string]string{"mysql": "mysql:8.0.32"}},
+			{Name: "r3", ServiceVersion: "8.4.0", Images: map[string]string{"mysql": "mysql:8.4.0"}},
+		}
+		compDef := testing.FakeCompDef()
+		compDef.Status.Phase = kbappsv1.AvailablePhase
+		compDefs = []*kbappsv1.ComponentDefinition{compDef}
+		c := testing.FakeCluster(testing.ClusterName, testing.Namespace)
+		c.Spec.ComponentSpecs[0].ServiceVersion = "8.0.30"
+		clusters = []*kbappsv1.Cluster{c}
+	})
+
+	It("build matrix", func() {
+		entries := buildComponentVersionMatrix(cmpv, compDefs, clusters, "")
+		Expect(entries).Should(HaveLen(4))
+		Expect(entries[0].ServiceVersion).Should(Equal("8.0.30"))
+		Expect(entries[0].Status).Should(Equal(versionSuperseded))
+		Expect(entries[0].Clusters).Should(Equal([]string{testing.Namespace + "/" + testing.ClusterName}))
+		Expect(entries[1].Status).Should(Equal(versionSupported))
+		Expect(entries[1].Clusters).Should(BeEmpty())
+		Expect(entries[2].Status).Should(Equal(versionSupported))
+		Expect(entries[3].Status).Should(Equal(versionUnsupported))
+
+		By("the service version is not served by the ComponentVersion")
+		cmpv.Status.ServiceVersions = "8.0.30,8.0.32"
+		entries = buildComponentVersionMatrix(cmpv, compDefs, clusters, "")
+		Expect(entries[2].Status).Should(Equal(versionUnsupported))
+
+		By("the addon does not support the installed KubeBlocks version")
+		entries = buildComponentVersionMatrix(cmpv, compDefs, clusters, "addon mysql requires KubeBlocks >=1.0.0")
+		for _, e := range entries {
+			Expect(e.Status).Should(Equal(versionUnsupported))
+		}
+	})
+
+	It("kubeBlocksVersionMatched", func() {
+		Expect(kubeBlocksVersionMatched(">=1.0.0", "1.0.0")).Should(BeTrue())
+		Expect(kubeBlocksVersionMatched(">=1.0.0", "1.0.1-beta.1")).Should(BeTrue())
+		Expect(kubeBlocksVersionMatched(">=1.0.0", "0.9.3")).Should(BeFalse())
+	})
+
+	It("print matrix", func() {
+		entries := buildComponentVersionMatrix(cmpv, compDefs, clusters, "")
+		out := &bytes.Buffer{}
+		Expect(printMatrix(entries, matrixFormatJSON, out)).Should(Succeed())
+		var res []matrixEntry
+		Expect(json.Unmarshal(out.Bytes(), &res)).Should(Succeed())
+		Expect(res).Should(HaveLen(4))
+
+		out.Reset()
+		Expect(printMatrix(nil, matrixFormatJSON, out)).Should(Succeed())
+		Expect(out.String()).Should(Equal("[]\n"))
+
+		out.Reset()
+		Expect(printMatrix(entries, matrixFormatMarkdown, out)).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("| mysql | " + testing.CompDefName + " | 8.0.30 |"))
+
+		out.Reset()
+		Expect(printMatrix(entries, matrixFormatTable, out)).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("mysql=mysql:8.4.0"))
+	})
+
+	It("matrix cmd", func() {
+		streams, _, _, _ := genericiooptions.NewTestIOStreams()
+		tf := testing.NewTestFactory(testing.Namespace)
+		defer tf.Cleanup()
+		cmd := NewMatrixCmd(tf, streams)
+		Expect(cmd).ShouldNot(BeNil())
+
+		o := &matrixOptions{factory: tf, IOStreams: streams, output: "yaml"}
+		Expect(o.complete()).Should(HaveOccurred())
+		o.output = matrixFormatTable
+		Expect(o.complete()).Should(Succeed())
+		o.dynamic = testing.FakeDynamicClient(cmpv, compDefs[0], clusters[0])
+		o.client = nil
+		Expect(o.run()).Should(Succeed())
+	})
+})
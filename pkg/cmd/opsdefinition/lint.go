@@ -0,0 +1,515 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsdefinition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	"github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/pkg/util/flags"
+	"github.com/apecloud/kbcli/pkg/util/lint"
+)
+
+var (
+	lintExample = templates.Examples(`
+		# lint the ops-definitions in a file
+		kbcli ops-definition lint -f my-ops-definition.yaml
+
+		# lint the ops-definitions from stdin
+		cat my-ops-definition.yaml | kbcli ops-definition lint -f -`)
+
+	// envRefRegex matches the env references like $(KB_CLUSTER_NAME) in the containers
+	envRefRegex = regexp.MustCompile(`\$\(([A-Za-z_][A-Za-z0-9_]*)\)`)
+
+	// builtInActionEnvs are the env injected into the action pods by the custom ops controller.
+	builtInActionEnvs = []string{
+		"KB_CLUSTER_NAME",
+		"KB_COMP_NAME",
+		"KB_CLUSTER_COMP_NAME",
+		"KB_COMP_REPLICAS",
+		"KB_COMP_SERVICE_VERSION",
+		"KB_ACCOUNT_USERNAME",
+		"KB_ACCOUNT_PASSWORD",
+		"KB_COMP_SVC_NAME",
+	}
+)
+
+type lintOptions struct {
+	factory cmdutil.Factory
+	dynamic dynamic.Interface
+
+	file string
+	genericiooptions.IOStreams
+}
+
+func NewLintCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &lintOptions{
+		factory:   f,
+		IOStreams: streams,
+	}
+	cmd := &cobra.Command{
+		Use:     "lint -f FILE",
+		Short:   "Lint the OpsDefinitions in a local file before applying them.",
+		Example: lintExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.complete())
+			cmdutil.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "The file of the OpsDefinitions, use \"-\" to read from stdin")
+	util.CheckErr(cmd.MarkFlagRequired("file"))
+	return cmd
+}
+
+func (o *lintOptions) complete() error {
+	var err error
+	if o.file == "" {
+		return fmt.Errorf("the file of the OpsDefinitions should be specified by -f")
+	}
+	// the ComponentDefinition checks are skipped if the cluster is unreachable
+	o.dynamic, err = o.factory.DynamicClient()
+	return err
+}
+
+func (o *lintOptions) run() error {
+	opsDefs, err := loadOpsDefinitions(o.file, o.In)
+	if err != nil {
+		return err
+	}
+	compDefs, err := listComponentDefinitions(o.dynamic)
+	if err != nil {
+		fmt.Fprintf(o.ErrOut, "%s failed to list the ComponentDefinitions, skip the checks of the componentInfos: %s\n",
+			printer.BoldYellow("WARNING:"), err.Error())
+	}
+	var errCount int
+	for _, opsDef := range opsDefs {
+		results := lintOpsDefinition(opsDef, compDefs)
+		results.Print(o.Out, "OpsDefinition", opsDef.Name, "FIELD")
+		errCount += results.ErrorCount()
+	}
+	if errCount > 0 {
+		return fmt.Errorf("%d error(s) found in %s", errCount, o.file)
+	}
+	return nil
+}
+
+// loadOpsDefinitions loads the OpsDefinitions from the file, "-" means reading from stdin.
+func loadOpsDefinitions(file string, in io.Reader) ([]*v1alpha1.OpsDefinition, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if file == "-" {
+		data, err = io.ReadAll(in)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var opsDefs []*v1alpha1.OpsDefinition
+	manifests := releaseutil.SplitManifests(string(data))
+	keys := make([]string, 0, len(manifests))
+	for k := range manifests {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+	for _, k := range keys {
+		typeMeta := metav1.TypeMeta{}
+		if err = yaml.Unmarshal([]byte(manifests[k]), &typeMeta); err != nil {
+			return nil, err
+		}
+		if typeMeta.Kind != types.KindOpsDef {
+			continue
+		}
+		opsDef := &v1alpha1.OpsDefinition{}
+		if err = yaml.UnmarshalStrict([]byte(manifests[k]), opsDef); err != nil {
+			return nil, fmt.Errorf("invalid OpsDefinition: %s", err.Error())
+		}
+		opsDefs = append(opsDefs, opsDef)
+	}
+	if len(opsDefs) == 0 {
+		return nil, fmt.Errorf("no OpsDefinition found in %s", file)
+	}
+	return opsDefs, nil
+}
+
+func listComponentDefinitions(dynamic dynamic.Interface) ([]*kbappsv1.ComponentDefinition, error) {
+	objs, err := dynamic.Resource(types.CompDefGVR()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var compDefs []*kbappsv1.ComponentDefinition
+	for _, obj := range objs.Items {
+		compDef := &kbappsv1.ComponentDefinition{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), compDef); err != nil {
+			return nil, err
+		}
+		compDefs = append(compDefs, compDef)
+	}
+	return compDefs, nil
+}
+
+// lintOpsDefinition checks the parameters schema, componentInfos, podInfoExtractors, actions and the env
+// references of the OpsDefinition. compDefs are the installed ComponentDefinitions, the checks depending
+// on them are skipped if compDefs is nil.
+func lintOpsDefinition(opsDef *v1alpha1.OpsDefinition, compDefs []*kbappsv1.ComponentDefinition) lint.Results {
+	l := &opsDefLinter{opsDef: opsDef, installedCompDefs: compDefs}
+	l.lintParametersSchema()
+	l.lintComponentInfos()
+	l.lintPreConditions()
+	l.lintPodInfoExtractors()
+	l.lintActions()
+	return l.results
+}
+
+type opsDefLinter struct {
+	opsDef            *v1alpha1.OpsDefinition
+	installedCompDefs []*kbappsv1.ComponentDefinition
+	// matchedCompDefs are the installed ComponentDefinitions matched by the componentInfos
+	matchedCompDefs []*kbappsv1.ComponentDefinition
+	results         lint.Results
+}
+
+func (l *opsDefLinter) parameters() []string {
+	schema := l.opsDef.Spec.ParametersSchema
+	if schema == nil || schema.OpenAPIV3Schema == nil {
+		return nil
+	}
+	var params []string
+	for name := range schema.OpenAPIV3Schema.Properties {
+		params = append(params, name)
+	}
+	return params
+}
+
+func (l *opsDefLinter) lintParametersSchema() {
+	const field = "spec.parametersSchema"
+	schema := l.opsDef.Spec.ParametersSchema
+	if schema == nil {
+		return
+	}
+	if schema.OpenAPIV3Schema == nil {
+		l.results.Errorf(field, "openAPIV3Schema is required")
+		return
+	}
+	props := schema.OpenAPIV3Schema
+	errCount := l.results.ErrorCount()
+	for _, name := range props.Required {
+		if _, ok := props.Properties[name]; !ok {
+			l.results.Errorf(field+".openAPIV3Schema.required", "required parameter %s is not defined in the properties", name)
+		}
+	}
+	for name, prop := range props.Properties {
+		l.lintSchemaProperty(fmt.Sprintf("%s.openAPIV3Schema.properties.%s", field, name), prop)
+		if name == "component" {
+			l.results.Warnf(field, `parameter "component" conflicts with the flag of custom-ops, it will be renamed to "--component-fork"`)
+		}
+	}
+
+	// make sure the custom-ops command can build the flags from a valid schema
+	if l.results.ErrorCount() > errCount {
+		return
+	}
+	schemaData, err := json.Marshal(props)
+	if err != nil {
+		l.results.Errorf(field, "%s", err.Error())
+		return
+	}
+	s := &spec.Schema{}
+	if err = json.Unmarshal(schemaData, s); err != nil {
+		l.results.Errorf(field, "%s", err.Error())
+		return
+	}
+	if err = flags.BuildFlagsBySchema(&cobra.Command{}, s); err != nil {
+		l.results.Errorf(field, "failed to build the flags of custom-ops: %s", err.Error())
+	}
+}
+
+func (l *opsDefLinter) lintSchemaProperty(field string, prop apiextensionsv1.JSONSchemaProps) {
+	switch prop.Type {
+	case "":
+		l.results.Errorf(field, "type is required")
+		return
+	case "string", "integer", "number", "boolean":
+	case "object":
+		for name, p := range prop.Properties {
+			l.lintSchemaProperty(field+".properties."+name, p)
+		}
+	case "array":
+		if prop.Items == nil || prop.Items.Schema == nil {
+			l.results.Errorf(field, "items is required for the array type")
+			return
+		}
+		l.lintSchemaProperty(field+".items", *prop.Items.Schema)
+	default:
+		l.results.Errorf(field, "unsupported type %s", prop.Type)
+		return
+	}
+	if prop.Default != nil && len(prop.Enum) > 0 {
+		if !slices.ContainsFunc(prop.Enum, func(e apiextensionsv1.JSON) bool {
+			return string(e.Raw) == string(prop.Default.Raw)
+		}) {
+			l.results.Errorf(field, "default value %s is not in the enum", string(prop.Default.Raw))
+		}
+	}
+}
+
+func (l *opsDefLinter) lintComponentInfos() {
+	const field = "spec.componentInfos"
+	if len(l.opsDef.Spec.ComponentInfos) == 0 {
+		l.results.Warnf(field, "no componentInfos defined, the component must be specified when creating the custom ops")
+		return
+	}
+	for i, info := range l.opsDef.Spec.ComponentInfos {
+		infoField := fmt.Sprintf("%s[%d]", field, i)
+		if _, err := regexp.Compile(info.ComponentDefinitionName); err != nil {
+			l.results.Errorf(infoField+".componentDefinitionName", "invalid regular expression: %s", err.Error())
+			continue
+		}
+		if l.installedCompDefs == nil {
+			continue
+		}
+		var matched []*kbappsv1.ComponentDefinition
+		for _, compDef := range l.installedCompDefs {
+			if component.PrefixOrRegexMatched(compDef.Name, info.ComponentDefinitionName) {
+				matched = append(matched, compDef)
+			}
+		}
+		if len(matched) == 0 {
+			l.results.Warnf(infoField+".componentDefinitionName", "%s does not match any installed ComponentDefinition", info.ComponentDefinitionName)
+			continue
+		}
+		l.matchedCompDefs = append(l.matchedCompDefs, matched...)
+		for _, compDef := range matched {
+			if info.AccountName != "" && !slices.ContainsFunc(compDef.Spec.SystemAccounts, func(a kbappsv1.SystemAccount) bool {
+				return a.Name == info.AccountName
+			}) {
+				l.results.Errorf(infoField+".accountName", "account %s is not defined in the ComponentDefinition %s", info.AccountName, compDef.Name)
+			}
+			if info.ServiceName != "" && !slices.ContainsFunc(compDef.Spec.Services, func(s kbappsv1.ComponentService) bool {
+				return s.Name == info.ServiceName
+			}) {
+				l.results.Errorf(infoField+".serviceName", "service %s is not defined in the ComponentDefinition %s", info.ServiceName, compDef.Name)
+			}
+		}
+	}
+}
+
+func (l *opsDefLinter) lintPreConditions() {
+	for i, c := range l.opsDef.Spec.PreConditions {
+		field := fmt.Sprintf("spec.preConditions[%d].rule.expression", i)
+		if c.Rule == nil || c.Rule.Expression == "" {
+			l.results.Errorf(field, "expression is required")
+			continue
+		}
+		if _, err := template.New("preCondition").Parse(c.Rule.Expression); err != nil {
+			l.results.Errorf(field, "invalid template expression: %s", err.Error())
+		}
+	}
+}
+
+func (l *opsDefLinter) lintPodInfoExtractors() {
+	names := map[string]bool{}
+	for i, extractor := range l.opsDef.Spec.PodInfoExtractors {
+		field := fmt.Sprintf("spec.podInfoExtractors[%d]", i)
+		if names[extractor.Name] {
+			l.results.Errorf(field+".name", "duplicated podInfoExtractor %s", extractor.Name)
+		}
+		names[extractor.Name] = true
+		if extractor.PodSelector.Role != "" {
+			l.lintRole(field+".podSelector.role", extractor.PodSelector.Role)
+		}
+		envNames := map[string]bool{}
+		for j, env := range extractor.Env {
+			envField := fmt.Sprintf("%s.env[%d]", field, j)
+			if envNames[env.Name] {
+				l.results.Errorf(envField+".name", "duplicated env %s", env.Name)
+			}
+			envNames[env.Name] = true
+			switch {
+			case env.ValueFrom.EnvVarRef != nil && env.ValueFrom.FieldRef != nil:
+				l.results.Errorf(envField+".valueFrom", "only one of envRef and fieldPath can be specified")
+			case env.ValueFrom.EnvVarRef != nil:
+				if c := env.ValueFrom.EnvVarRef.TargetContainerName; c != "" {
+					l.lintContainer(envField+".valueFrom.envRef.targetContainerName", c)
+				}
+			case env.ValueFrom.FieldRef != nil:
+				if env.ValueFrom.FieldRef.FieldPath == "" {
+					l.results.Errorf(envField+".valueFrom.fieldPath", "fieldPath is required")
+				}
+			default:
+				l.results.Errorf(envField+".valueFrom", "one of envRef and fieldPath must be specified")
+			}
+		}
+	}
+}
+
+// lintRole checks if the role is defined in the matched ComponentDefinitions.
+func (l *opsDefLinter) lintRole(field, role string) {
+	for _, compDef := range l.matchedCompDefs {
+		if !slices.ContainsFunc(compDef.Spec.Roles, func(r kbappsv1.ReplicaRole) bool {
+			return r.Name == role
+		}) {
+			l.results.Warnf(field, "role %s is not defined in the ComponentDefinition %s", role, compDef.Name)
+		}
+	}
+}
+
+// lintContainer checks if the container is defined in the matched ComponentDefinitions.
+func (l *opsDefLinter) lintContainer(field, container string) {
+	for _, compDef := range l.matchedCompDefs {
+		if !slices.ContainsFunc(compDef.Spec.Runtime.Containers, func(c corev1.Container) bool {
+			return c.Name == container
+		}) {
+			l.results.Warnf(field, "container %s is not defined in the ComponentDefinition %s", container, compDef.Name)
+		}
+	}
+}
+
+func (l *opsDefLinter) lintActions() {
+	if len(l.opsDef.Spec.Actions) == 0 {
+		l.results.Errorf("spec.actions", "at least one action is required")
+		return
+	}
+	params := l.parameters()
+	names := map[string]bool{}
+	for i, action := range l.opsDef.Spec.Actions {
+		field := fmt.Sprintf("spec.actions[%d]", i)
+		if names[action.Name] {
+			l.results.Errorf(field+".name", "duplicated action %s", action.Name)
+		}
+		names[action.Name] = true
+		for _, p := range action.Parameters {
+			if !slices.Contains(params, p) {
+				l.results.Errorf(field+".parameters", "parameter %s is not defined in the parametersSchema", p)
+			}
+		}
+		var count int
+		if action.Workload != nil {
+			count++
+			l.lintWorkloadAction(field+".workload", action)
+		}
+		if action.Exec != nil {
+			count++
+			l.lintExtractorRef(field+".exec.podInfoExtractorName", action.Exec.PodInfoExtractorName)
+			if action.Exec.ContainerName != "" {
+				l.lintContainer(field+".exec.containerName", action.Exec.ContainerName)
+			}
+			if len(action.Exec.Command) == 0 {
+				l.results.Errorf(field+".exec.command", "command is required")
+			}
+		}
+		if action.ResourceModifier != nil {
+			count++
+		}
+		if count != 1 {
+			l.results.Errorf(field, "exactly one of workload, exec and resourceModifier must be specified")
+		}
+	}
+}
+
+func (l *opsDefLinter) lintExtractorRef(field, name string) *v1alpha1.PodInfoExtractor {
+	if name == "" {
+		l.results.Errorf(field, "podInfoExtractorName is required")
+		return nil
+	}
+	extractor := getPodInfoExtractor(l.opsDef, name)
+	if extractor == nil {
+		l.results.Errorf(field, "podInfoExtractor %s is not defined", name)
+	}
+	return extractor
+}
+
+// lintWorkloadAction checks the containers of the workload and the env references like $(NAME) in them,
+// the references should be resolved by the action parameters, the env of the podInfoExtractor,
+// the built-in env or the env of the container itself.
+func (l *opsDefLinter) lintWorkloadAction(field string, action v1alpha1.OpsAction) {
+	extractor := l.lintExtractorRef(field+".podInfoExtractorName", action.Workload.PodInfoExtractorName)
+	if len(action.Workload.PodSpec.Containers) == 0 {
+		l.results.Errorf(field+".podSpec.containers", "at least one container is required")
+		return
+	}
+	known := append([]string{}, builtInActionEnvs...)
+	known = append(known, action.Parameters...)
+	if extractor != nil {
+		for _, env := range extractor.Env {
+			known = append(known, env.Name)
+		}
+	}
+	for i, c := range action.Workload.PodSpec.Containers {
+		containerField := fmt.Sprintf("%s.podSpec.containers[%d]", field, i)
+		containerEnvs := append([]string{}, known...)
+		for _, env := range c.Env {
+			containerEnvs = append(containerEnvs, env.Name)
+		}
+		values := append(append([]string{}, c.Command...), c.Args...)
+		for _, env := range c.Env {
+			values = append(values, env.Value)
+		}
+		var unresolved []string
+		for _, v := range values {
+			for _, m := range envRefRegex.FindAllStringSubmatch(v, -1) {
+				if !slices.Contains(containerEnvs, m[1]) && !slices.Contains(unresolved, m[1]) {
+					unresolved = append(unresolved, m[1])
+				}
+			}
+		}
+		for _, name := range unresolved {
+			l.results.Warnf(containerField, "env $(%s) is not provided by the parameters, podInfoExtractor, built-in env or the container", name)
+		}
+	}
+}
+
+// formatResults is used in the error message of render
+func formatResults(results lint.Results) string {
+	var msgs []string
+	for _, r := range results {
+		if r.Level == lint.LevelError {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", r.Target, r.Message))
+		}
+	}
+	return strings.Join(msgs, "\n")
+}
@@ -0,0 +1,173 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsdefinition
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	"github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+	"github.com/apecloud/kbcli/pkg/util/lint"
+)
+
+const opsDefYAML = `apiVersion: operations.kubeblocks.io/v1alpha1
+kind: OpsDefinition
+metadata:
+  name: kafka-topic
+spec:
+  componentInfos:
+  - componentDefinitionName: fake-component
+  parametersSchema:
+    openAPIV3Schema:
+      properties:
+        topic:
+          type: string
+        partition:
+          type: integer
+      required:
+      - topic
+  podInfoExtractors:
+  - name: broker
+    podSelector:
+      role: leader
+      multiPodSelectionPolicy: Any
+    env:
+    - name: POD_IP
+      valueFrom:
+        fieldPath:
+          fieldPath: status.podIP
+  actions:
+  - name: create-topic
+    parameters:
+    - topic
+    - partition
+    workload:
+      type: Job
+      podInfoExtractorName: broker
+      podSpec:
+        containers:
+        - name: kafka
+          image: kafka
+          command:
+          - sh
+          - -c
+          - kafka-topics.sh --create --topic $(topic) --partitions $(partition) --bootstrap-server $(POD_IP):9092
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: not-an-ops-definition
+`
+
+var _ = Describe("ops-definition lint", func() {
+	var opsDef *v1alpha1.OpsDefinition
+
+	BeforeEach(func() {
+		opsDefs, err := loadOpsDefinitions("-", bytes.NewBufferString(opsDefYAML))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(opsDefs).Should(HaveLen(1))
+		opsDef = opsDefs[0]
+	})
+
+	It("load ops-definitions", func() {
+		Expect(opsDef.Name).Should(Equal("kafka-topic"))
+		Expect(opsDef.Spec.Actions).Should(HaveLen(1))
+		_, err := loadOpsDefinitions("-", bytes.NewBufferString("apiVersion: v1\nkind: ConfigMap\n"))
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("lint a valid ops-definition", func() {
+		results := lintOpsDefinition(opsDef, []*kbappsv1.ComponentDefinition{testing.FakeCompDef()})
+		Expect(results).Should(BeEmpty())
+	})
+
+	It("lint an invalid ops-definition", func() {
+		opsDef.Spec.ParametersSchema.OpenAPIV3Schema.Required = append(opsDef.Spec.ParametersSchema.OpenAPIV3Schema.Required, "replicas")
+		opsDef.Spec.ParametersSchema.OpenAPIV3Schema.Properties["type"] = apiextensionsv1.JSONSchemaProps{
+			Type:    "string",
+			Enum:    []apiextensionsv1.JSON{{Raw: []byte(`"create"`)}},
+			Default: &apiextensionsv1.JSON{Raw: []byte(`"delete"`)},
+		}
+		opsDef.Spec.ComponentInfos[0].AccountName = "not-exist"
+		opsDef.Spec.PodInfoExtractors[0].PodSelector.Role = "not-exist"
+		opsDef.Spec.Actions[0].Parameters = append(opsDef.Spec.Actions[0].Parameters, "not-exist")
+		opsDef.Spec.Actions[0].Workload.PodSpec.Containers[0].Args = []string{"$(UNKNOWN_ENV)"}
+		opsDef.Spec.Actions = append(opsDef.Spec.Actions, v1alpha1.OpsAction{
+			Name: "exec",
+			Exec: &v1alpha1.OpsExecAction{PodInfoExtractorName: "not-exist"},
+		})
+
+		results := lintOpsDefinition(opsDef, []*kbappsv1.ComponentDefinition{testing.FakeCompDef()})
+		out := &bytes.Buffer{}
+		results.Print(out, "OpsDefinition", opsDef.Name, "FIELD")
+		for _, msg := range []string{
+			"required parameter replicas is not defined",
+			`default value "delete" is not in the enum`,
+			"account not-exist is not defined",
+			"role not-exist is not defined",
+			"parameter not-exist is not defined in the parametersSchema",
+			"env $(UNKNOWN_ENV) is not provided",
+			"podInfoExtractor not-exist is not defined",
+			"command is required",
+		} {
+			Expect(out.String()).Should(ContainSubstring(msg))
+		}
+
+		By("the componentInfos do not match any installed ComponentDefinition")
+		results = lintOpsDefinition(opsDef, []*kbappsv1.ComponentDefinition{})
+		Expect(results).Should(ContainElement(lint.Result{
+			Level:   lint.LevelWarning,
+			Target:  "spec.componentInfos[0].componentDefinitionName",
+			Message: "fake-component does not match any installed ComponentDefinition",
+		}))
+	})
+
+	It("lint cmd", func() {
+		streams, in, out, _ := genericiooptions.NewTestIOStreams()
+		tf := testing.NewTestFactory(testing.Namespace)
+		defer tf.Cleanup()
+		Expect(NewLintCmd(tf, streams)).ShouldNot(BeNil())
+
+		in.WriteString(opsDefYAML)
+		o := &lintOptions{factory: tf, IOStreams: streams, file: "-"}
+		o.dynamic = testing.FakeDynamicClient(testing.FakeCompDef())
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("OpsDefinition kafka-topic: OK"))
+	})
+
+	It("get pod field value", func() {
+		pod := &corev1.Pod{}
+		pod.Labels = map[string]string{"app": "kafka"}
+		pod.Status.PodIP = "10.0.0.1"
+		v, err := getPodFieldValue(pod, "status.podIP")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(v).Should(Equal("10.0.0.1"))
+		v, err = getPodFieldValue(pod, "metadata.labels['app']")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(v).Should(Equal("kafka"))
+		_, err = getPodFieldValue(pod, "spec.notExist")
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -47,6 +47,8 @@ func NewOpsDefinitionCmd(f cmdutil.Factory, streams genericiooptions.IOStreams)
 
 	cmd.AddCommand(NewListCmd(f, streams))
 	cmd.AddCommand(NewDescribeCmd(f, streams))
+	cmd.AddCommand(NewLintCmd(f, streams))
+	cmd.AddCommand(NewRenderCmd(f, streams))
 	return cmd
 }
 
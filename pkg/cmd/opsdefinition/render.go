@@ -0,0 +1,500 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsdefinition
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	"github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+
+	"github.com/apecloud/kbcli/pkg/cluster"
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/types"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var (
+	renderExample = templates.Examples(`
+		# render the OpsRequest and the env of the action jobs for the cluster mycluster
+		kbcli ops-definition render -f my-ops-definition.yaml --cluster mycluster --params topic=test --params partition=3
+
+		# render with the specified component and OpsDefinition in the file
+		kbcli ops-definition render -f my-ops-definitions.yaml --name kafka-topic --cluster mycluster --component kafka-broker`)
+
+	// fieldPathMapRegex matches the field path like metadata.labels['app']
+	fieldPathMapRegex = regexp.MustCompile(`^metadata\.(labels|annotations)\['(.+)'\]$`)
+)
+
+type renderOptions struct {
+	factory   cmdutil.Factory
+	client    clientset.Interface
+	dynamic   dynamic.Interface
+	namespace string
+
+	file        string
+	name        string
+	clusterName string
+	component   string
+	params      []string
+	genericiooptions.IOStreams
+}
+
+// renderedAction is an action job of the custom ops, it is created for each target pod.
+type renderedAction struct {
+	Name          string
+	Type          string
+	FailurePolicy string
+	Pod           string
+	Env           []corev1.EnvVar
+}
+
+func NewRenderCmd(f cmdutil.Factory, streams genericiooptions.IOStreams) *cobra.Command {
+	o := &renderOptions{
+		factory:   f,
+		IOStreams: streams,
+	}
+	cmd := &cobra.Command{
+		Use:     "render -f FILE --cluster NAME",
+		Short:   "Render the OpsRequest and the env of each action job of a local OpsDefinition without creating anything.",
+		Example: renderExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.complete())
+			cmdutil.CheckErr(o.run())
+		},
+	}
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "The file of the OpsDefinition, use \"-\" to read from stdin")
+	cmd.Flags().StringVar(&o.name, "name", "", "The name of the OpsDefinition, required if the file contains multiple OpsDefinitions")
+	cmd.Flags().StringVar(&o.clusterName, "cluster", "", "The cluster to render the OpsRequest for")
+	cmd.Flags().StringVar(&o.component, "component", "", "The component name, if not specified, using the first component which matches the componentInfos")
+	cmd.Flags().StringArrayVar(&o.params, "params", nil, "The params of the OpsRequest in the format of key=value, can be specified multiple times")
+	util.CheckErr(cmd.MarkFlagRequired("file"))
+	util.CheckErr(cmd.MarkFlagRequired("cluster"))
+	util.CheckErr(cmd.RegisterFlagCompletionFunc("cluster", util.ResourceNameCompletionFunc(f, types.ClusterGVR())))
+	return cmd
+}
+
+func (o *renderOptions) complete() error {
+	var err error
+	if o.file == "" {
+		return fmt.Errorf("the file of the OpsDefinition should be specified by -f")
+	}
+	if o.clusterName == "" {
+		return fmt.Errorf("the cluster should be specified by --cluster")
+	}
+	if o.client, err = o.factory.KubernetesClientSet(); err != nil {
+		return err
+	}
+	if o.dynamic, err = o.factory.DynamicClient(); err != nil {
+		return err
+	}
+	if o.namespace, _, err = o.factory.ToRawKubeConfigLoader().Namespace(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *renderOptions) run() error {
+	opsDef, err := o.getOpsDefinition()
+	if err != nil {
+		return err
+	}
+	clusterObj, err := cluster.GetClusterByName(o.dynamic, o.clusterName, o.namespace)
+	if err != nil {
+		return err
+	}
+	compSpec, info, err := o.getComponent(opsDef, clusterObj)
+	if err != nil {
+		return err
+	}
+	params, err := o.buildParams(opsDef)
+	if err != nil {
+		return err
+	}
+	pods, err := o.listComponentPods(clusterObj)
+	if err != nil {
+		return err
+	}
+	ops := buildOpsRequest(opsDef, clusterObj, o.component, params)
+	actions, err := renderActions(opsDef, clusterObj, compSpec, o.component, info, params, pods)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(ops.Object)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "# OpsRequest\n%s\n", string(data))
+	printRenderedActions(o.Out, actions)
+	return nil
+}
+
+func (o *renderOptions) getOpsDefinition() (*v1alpha1.OpsDefinition, error) {
+	opsDefs, err := loadOpsDefinitions(o.file, o.In)
+	if err != nil {
+		return nil, err
+	}
+	var opsDef *v1alpha1.OpsDefinition
+	switch {
+	case o.name != "":
+		for i := range opsDefs {
+			if opsDefs[i].Name == o.name {
+				opsDef = opsDefs[i]
+			}
+		}
+		if opsDef == nil {
+			return nil, fmt.Errorf("OpsDefinition %s is not found in %s", o.name, o.file)
+		}
+	case len(opsDefs) > 1:
+		var names []string
+		for _, d := range opsDefs {
+			names = append(names, d.Name)
+		}
+		return nil, fmt.Errorf("multiple OpsDefinitions found in %s, please specify one of them by --name: %s", o.file, strings.Join(names, ", "))
+	default:
+		opsDef = opsDefs[0]
+	}
+	// the installed ComponentDefinitions are checked when resolving the component
+	if msg := formatResults(lintOpsDefinition(opsDef, nil)); msg != "" {
+		return nil, fmt.Errorf("OpsDefinition %s is invalid, run \"kbcli ops-definition lint\" for details:\n%s", opsDef.Name, msg)
+	}
+	return opsDef, nil
+}
+
+// getComponent gets the component spec and the matched componentInfo, the first supported component is used if
+// the component is not specified.
+func (o *renderOptions) getComponent(opsDef *v1alpha1.OpsDefinition, clusterObj *kbappsv1.Cluster) (*kbappsv1.ClusterComponentSpec, *v1alpha1.ComponentInfo, error) {
+	matchedInfo := func(compDef string) *v1alpha1.ComponentInfo {
+		for i, info := range opsDef.Spec.ComponentInfos {
+			if component.PrefixOrRegexMatched(compDef, info.ComponentDefinitionName) {
+				return &opsDef.Spec.ComponentInfos[i]
+			}
+		}
+		return nil
+	}
+	if o.component != "" {
+		compSpec := cluster.GetComponentSpec(clusterObj, o.component)
+		if compSpec == nil {
+			return nil, nil, fmt.Errorf("component %s is not found in the cluster %s", o.component, o.clusterName)
+		}
+		if len(opsDef.Spec.ComponentInfos) == 0 {
+			return compSpec, nil, nil
+		}
+		info := matchedInfo(compSpec.ComponentDef)
+		if info == nil {
+			return nil, nil, fmt.Errorf(`OpsDefinition "%s" does not support the component "%s"`, opsDef.Name, o.component)
+		}
+		return compSpec, info, nil
+	}
+	if len(opsDef.Spec.ComponentInfos) == 0 {
+		return nil, nil, fmt.Errorf("component name can not be empty")
+	}
+	for i := range clusterObj.Spec.ComponentSpecs {
+		compSpec := &clusterObj.Spec.ComponentSpecs[i]
+		if info := matchedInfo(compSpec.ComponentDef); info != nil {
+			o.component = compSpec.Name
+			return compSpec, info, nil
+		}
+	}
+	return nil, nil, fmt.Errorf(`OpsDefinition "%s" does not support any component of the cluster %s`, opsDef.Name, o.clusterName)
+}
+
+// buildParams parses and validates the params with the parameters schema.
+func (o *renderOptions) buildParams(opsDef *v1alpha1.OpsDefinition) (map[string]string, error) {
+	params := map[string]string{}
+	for _, p := range o.params {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid params %s, the format should be key=value", p)
+		}
+		params[kv[0]] = kv[1]
+	}
+	if opsDef.Spec.ParametersSchema == nil || opsDef.Spec.ParametersSchema.OpenAPIV3Schema == nil {
+		if len(params) > 0 {
+			return nil, fmt.Errorf("OpsDefinition %s does not define any params", opsDef.Name)
+		}
+		return params, nil
+	}
+	schema := opsDef.Spec.ParametersSchema.OpenAPIV3Schema
+	for name := range params {
+		if _, ok := schema.Properties[name]; !ok {
+			return nil, fmt.Errorf("unknown param %s", name)
+		}
+	}
+	data, err := common.ConvertStringToInterfaceBySchemaType(schema, params)
+	if err != nil {
+		return nil, err
+	}
+	if err = common.ValidateDataWithSchema(schema, data); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func (o *renderOptions) listComponentPods(clusterObj *kbappsv1.Cluster) ([]corev1.Pod, error) {
+	selector := fmt.Sprintf("%s=%s,%s=%s", constant.AppInstanceLabelKey, clusterObj.Name,
+		cluster.ComponentNameLabelKey(clusterObj, o.component), o.component)
+	pods, err := o.client.CoreV1().Pods(o.namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+	return pods.Items, nil
+}
+
+// buildOpsRequest builds the OpsRequest which will be created by "kbcli cluster custom-ops".
+func buildOpsRequest(opsDef *v1alpha1.OpsDefinition, clusterObj *kbappsv1.Cluster, compName string, params map[string]string) *unstructured.Unstructured {
+	var parameters []interface{}
+	for _, name := range sortedKeys(params) {
+		parameters = append(parameters, map[string]interface{}{"name": name, "value": params[name]})
+	}
+	comp := map[string]interface{}{"componentName": compName}
+	if len(parameters) > 0 {
+		comp["parameters"] = parameters
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": types.OpsAPIGroup + "/" + types.OpsAPIVersion,
+		"kind":       types.KindOps,
+		"metadata": map[string]interface{}{
+			"generateName": fmt.Sprintf("%s-custom-", clusterObj.Name),
+			"namespace":    clusterObj.Namespace,
+		},
+		"spec": map[string]interface{}{
+			"clusterName": clusterObj.Name,
+			"type":        string(v1alpha1.CustomType),
+			"custom": map[string]interface{}{
+				"opsDefinitionName": opsDef.Name,
+				"components":        []interface{}{comp},
+			},
+		},
+	}}
+}
+
+// renderActions renders the action jobs and their env. The env consists of the built-in env, the env of
+// the component account and service, the action parameters and the env extracted from the target pod.
+func renderActions(opsDef *v1alpha1.OpsDefinition,
+	clusterObj *kbappsv1.Cluster,
+	compSpec *kbappsv1.ClusterComponentSpec,
+	compName string,
+	info *v1alpha1.ComponentInfo,
+	params map[string]string,
+	pods []corev1.Pod) ([]renderedAction, error) {
+	baseEnv := []corev1.EnvVar{
+		{Name: "KB_CLUSTER_NAME", Value: clusterObj.Name},
+		{Name: "KB_COMP_NAME", Value: compName},
+		{Name: "KB_CLUSTER_COMP_NAME", Value: fmt.Sprintf("%s-%s", clusterObj.Name, compName)},
+		{Name: "KB_COMP_REPLICAS", Value: strconv.Itoa(int(compSpec.Replicas))},
+	}
+	if compSpec.ServiceVersion != "" {
+		baseEnv = append(baseEnv, corev1.EnvVar{Name: "KB_COMP_SERVICE_VERSION", Value: compSpec.ServiceVersion})
+	}
+	if info != nil && info.AccountName != "" {
+		secretName := constant.GenerateAccountSecretName(clusterObj.Name, compName, info.AccountName)
+		baseEnv = append(baseEnv,
+			corev1.EnvVar{Name: "KB_ACCOUNT_USERNAME", Value: fmt.Sprintf("<secret %s: username>", secretName)},
+			corev1.EnvVar{Name: "KB_ACCOUNT_PASSWORD", Value: fmt.Sprintf("<secret %s: password>", secretName)})
+	}
+	if info != nil && info.ServiceName != "" {
+		baseEnv = append(baseEnv, corev1.EnvVar{Name: "KB_COMP_SVC_NAME", Value: fmt.Sprintf("%s-%s-%s", clusterObj.Name, compName, info.ServiceName)})
+	}
+
+	var actions []renderedAction
+	for _, action := range opsDef.Spec.Actions {
+		env := append([]corev1.EnvVar{}, baseEnv...)
+		for _, p := range action.Parameters {
+			if v, ok := params[p]; ok {
+				env = append(env, corev1.EnvVar{Name: p, Value: v})
+			}
+		}
+		r := renderedAction{Name: action.Name, FailurePolicy: string(action.FailurePolicy)}
+		var extractorName string
+		switch {
+		case action.Workload != nil:
+			r.Type = fmt.Sprintf("Workload(%s)", action.Workload.Type)
+			extractorName = action.Workload.PodInfoExtractorName
+		case action.Exec != nil:
+			r.Type = "Exec"
+			extractorName = action.Exec.PodInfoExtractorName
+		default:
+			// the resource modifier patches the resource directly, no job is created
+			r.Type = "ResourceModifier"
+			r.Env = env
+			actions = append(actions, r)
+			continue
+		}
+		extractor := getPodInfoExtractor(opsDef, extractorName)
+		if extractor == nil {
+			return nil, fmt.Errorf("podInfoExtractor %s is not defined", extractorName)
+		}
+		targetPods := selectPods(pods, extractor.PodSelector)
+		if len(targetPods) == 0 {
+			return nil, fmt.Errorf("no pod of the component %s is selected by the podInfoExtractor %s", compName, extractor.Name)
+		}
+		for i := range targetPods {
+			podAction := r
+			podAction.Pod = targetPods[i].Name
+			podAction.Env = append(append([]corev1.EnvVar{}, env...), extractPodEnv(&targetPods[i], extractor.Env)...)
+			actions = append(actions, podAction)
+		}
+	}
+	return actions, nil
+}
+
+func getPodInfoExtractor(opsDef *v1alpha1.OpsDefinition, name string) *v1alpha1.PodInfoExtractor {
+	for i := range opsDef.Spec.PodInfoExtractors {
+		if opsDef.Spec.PodInfoExtractors[i].Name == name {
+			return &opsDef.Spec.PodInfoExtractors[i]
+		}
+	}
+	return nil
+}
+
+// selectPods selects the pods by role, only the first pod is selected if the policy is Any.
+func selectPods(pods []corev1.Pod, selector v1alpha1.PodSelector) []corev1.Pod {
+	var selected []corev1.Pod
+	for _, pod := range pods {
+		if selector.Role != "" && pod.Labels[constant.RoleLabelKey] != selector.Role {
+			continue
+		}
+		selected = append(selected, pod)
+	}
+	if len(selected) > 1 && selector.MultiPodSelectionPolicy != v1alpha1.All {
+		return selected[:1]
+	}
+	return selected
+}
+
+// extractPodEnv resolves the env of the podInfoExtractor from the pod.
+func extractPodEnv(pod *corev1.Pod, envs []v1alpha1.OpsEnvVar) []corev1.EnvVar {
+	var res []corev1.EnvVar
+	for _, env := range envs {
+		value := "<not found>"
+		switch {
+		case env.ValueFrom.EnvVarRef != nil:
+			value = getContainerEnv(pod, env.ValueFrom.EnvVarRef.TargetContainerName, env.ValueFrom.EnvVarRef.EnvName)
+		case env.ValueFrom.FieldRef != nil:
+			if v, err := getPodFieldValue(pod, env.ValueFrom.FieldRef.FieldPath); err == nil {
+				value = v
+			}
+		}
+		res = append(res, corev1.EnvVar{Name: env.Name, Value: value})
+	}
+	return res
+}
+
+func getContainerEnv(pod *corev1.Pod, containerName, envName string) string {
+	for _, c := range pod.Spec.Containers {
+		if containerName != "" && c.Name != containerName {
+			continue
+		}
+		for _, env := range c.Env {
+			if env.Name != envName {
+				continue
+			}
+			switch {
+			case env.ValueFrom == nil:
+				return env.Value
+			case env.ValueFrom.SecretKeyRef != nil:
+				return fmt.Sprintf("<secret %s: %s>", env.ValueFrom.SecretKeyRef.Name, env.ValueFrom.SecretKeyRef.Key)
+			case env.ValueFrom.ConfigMapKeyRef != nil:
+				return fmt.Sprintf("<configmap %s: %s>", env.ValueFrom.ConfigMapKeyRef.Name, env.ValueFrom.ConfigMapKeyRef.Key)
+			case env.ValueFrom.FieldRef != nil:
+				if v, err := getPodFieldValue(pod, env.ValueFrom.FieldRef.FieldPath); err == nil {
+					return v
+				}
+			}
+			return "<runtime value>"
+		}
+		// only the first container is used if the container name is not specified
+		if containerName == "" {
+			break
+		}
+	}
+	return "<not found>"
+}
+
+// getPodFieldValue gets the value of the field path of the pod, such as status.podIP and metadata.labels['app'].
+func getPodFieldValue(pod *corev1.Pod, fieldPath string) (string, error) {
+	if m := fieldPathMapRegex.FindStringSubmatch(fieldPath); m != nil {
+		if m[1] == "labels" {
+			return pod.Labels[m[2]], nil
+		}
+		return pod.Annotations[m[2]], nil
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		return "", err
+	}
+	value, found, err := unstructured.NestedFieldNoCopy(obj, strings.Split(fieldPath, ".")...)
+	if err != nil || !found {
+		return "", fmt.Errorf("field path %s is not found", fieldPath)
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func printRenderedActions(out io.Writer, actions []renderedAction) {
+	fmt.Fprintf(out, "Actions:\n")
+	for _, a := range actions {
+		fmt.Fprintf(out, "  %s:\n", a.Name)
+		fmt.Fprintf(out, "    Type: %s\n", a.Type)
+		if a.FailurePolicy != "" {
+			fmt.Fprintf(out, "    Failure Policy: %s\n", a.FailurePolicy)
+		}
+		if a.Pod != "" {
+			fmt.Fprintf(out, "    Target Pod: %s\n", a.Pod)
+		}
+		tbl := printer.NewTablePrinter(out)
+		tbl.SetHeader("\tENV", "VALUE")
+		for _, env := range a.Env {
+			tbl.AddRow("\t"+env.Name, env.Value)
+		}
+		tbl.Print()
+		fmt.Fprintln(out)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsdefinition
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	kbappsv1 "github.com/apecloud/kubeblocks/apis/apps/v1"
+	"github.com/apecloud/kubeblocks/apis/operations/v1alpha1"
+
+	"github.com/apecloud/kbcli/pkg/testing"
+)
+
+var _ = Describe("ops-definition render", func() {
+	var (
+		opsDef     *v1alpha1.OpsDefinition
+		clusterObj *kbappsv1.Cluster
+		pods       []corev1.Pod
+	)
+
+	BeforeEach(func() {
+		opsDefs, err := loadOpsDefinitions("-", bytes.NewBufferString(opsDefYAML))
+		Expect(err).ShouldNot(HaveOccurred())
+		opsDef = opsDefs[0]
+		clusterObj = testing.FakeCluster(testing.ClusterName, testing.Namespace)
+		clusterObj.Spec.ComponentSpecs[0].ComponentDef = testing.CompDefName
+		pods = testing.FakePods(3, testing.Namespace, testing.ClusterName).Items
+		pods[0].Status.PodIP = "10.0.0.1"
+	})
+
+	It("build params", func() {
+		o := &renderOptions{params: []string{"topic=test", "partition=3"}}
+		params, err := o.buildParams(opsDef)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(params).Should(Equal(map[string]string{"topic": "test", "partition": "3"}))
+
+		o.params = []string{"partition=3"}
+		_, err = o.buildParams(opsDef)
+		Expect(err).Should(HaveOccurred())
+
+		o.params = []string{"topic=test", "unknown=1"}
+		_, err = o.buildParams(opsDef)
+		Expect(err).Should(MatchError(ContainSubstring("unknown param")))
+	})
+
+	It("get component", func() {
+		o := &renderOptions{clusterName: testing.ClusterName}
+		compSpec, info, err := o.getComponent(opsDef, clusterObj)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(compSpec.Name).Should(Equal(testing.ComponentName))
+		Expect(info.ComponentDefinitionName).Should(Equal("fake-component"))
+		Expect(o.component).Should(Equal(testing.ComponentName))
+
+		o.component = "not-exist"
+		_, _, err = o.getComponent(opsDef, clusterObj)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("render actions", func() {
+		params := map[string]string{"topic": "test"}
+		compSpec := &clusterObj.Spec.ComponentSpecs[0]
+		ops := buildOpsRequest(opsDef, clusterObj, compSpec.Name, params)
+		Expect(ops.GetKind()).Should(Equal("OpsRequest"))
+		Expect(ops.Object["spec"]).Should(HaveKeyWithValue("clusterName", testing.ClusterName))
+
+		actions, err := renderActions(opsDef, clusterObj, compSpec, compSpec.Name, &opsDef.Spec.ComponentInfos[0], params, pods)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(actions).Should(HaveLen(1))
+		Expect(actions[0].Pod).Should(Equal(pods[0].Name))
+		Expect(actions[0].Env).Should(ContainElements(
+			corev1.EnvVar{Name: "KB_CLUSTER_NAME", Value: testing.ClusterName},
+			corev1.EnvVar{Name: "topic", Value: "test"},
+			corev1.EnvVar{Name: "POD_IP", Value: "10.0.0.1"},
+		))
+
+		By("select all the followers")
+		opsDef.Spec.PodInfoExtractors[0].PodSelector.Role = "follower"
+		opsDef.Spec.PodInfoExtractors[0].PodSelector.MultiPodSelectionPolicy = v1alpha1.All
+		actions, err = renderActions(opsDef, clusterObj, compSpec, compSpec.Name, nil, params, pods)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(actions).Should(HaveLen(2))
+
+		out := &bytes.Buffer{}
+		printRenderedActions(out, actions)
+		Expect(out.String()).Should(ContainSubstring("Target Pod: " + pods[2].Name))
+	})
+
+	It("render cmd", func() {
+		streams, in, out, _ := genericiooptions.NewTestIOStreams()
+		tf := testing.NewTestFactory(testing.Namespace)
+		defer tf.Cleanup()
+		Expect(NewRenderCmd(tf, streams)).ShouldNot(BeNil())
+
+		in.WriteString(opsDefYAML)
+		o := &renderOptions{
+			factory:     tf,
+			IOStreams:   streams,
+			file:        "-",
+			clusterName: testing.ClusterName,
+			namespace:   testing.Namespace,
+			params:      []string{"topic=test"},
+			dynamic:     testing.FakeDynamicClient(clusterObj),
+			client:      testing.FakeClientSet(&pods[0], &pods[1]),
+		}
+		Expect(o.run()).Should(Succeed())
+		Expect(out.String()).Should(ContainSubstring("opsDefinitionName: kafka-topic"))
+		Expect(out.String()).Should(ContainSubstring("create-topic"))
+	})
+})
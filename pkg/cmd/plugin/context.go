@@ -0,0 +1,151 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	kccmd "k8s.io/kubectl/pkg/cmd"
+
+	"github.com/apecloud/kbcli/pkg/pluginsdk"
+	"github.com/apecloud/kbcli/pkg/util"
+	"github.com/apecloud/kbcli/version"
+)
+
+// kubeBlocksLookupTimeout is the timeout to look up the KubeBlocks deployment for the plugin context
+const kubeBlocksLookupTimeout = 5 * time.Second
+
+// ContextPluginHandler wraps a plugin handler and passes the runtime context
+// resolved from the kbcli command line to the plugins it executes.
+type ContextPluginHandler struct {
+	kccmd.PluginHandler
+
+	// the kbcli command line args, without the kbcli executable
+	args []string
+}
+
+func NewContextPluginHandler(handler kccmd.PluginHandler, args []string) *ContextPluginHandler {
+	return &ContextPluginHandler{
+		PluginHandler: handler,
+		args:          args,
+	}
+}
+
+// Execute passes the context through the environment variables and the context file to the plugin,
+// the context file is created exclusively in the temp directory with a random name. The file is
+// removed by pluginsdk.Load once the plugin reads it, or here if the plugin fails to be executed since
+// kbcli is replaced by the plugin process on success.
+func (h *ContextPluginHandler) Execute(executablePath string, cmdArgs, environment []string) error {
+	ctx := BuildPluginContext(h.args, executablePath)
+	environment = append(environment, ctx.Environ()...)
+	if file, err := ctx.WriteTempFile(); err != nil {
+		klog.V(1).Infof("Failed to write the plugin context file: %v", err)
+	} else {
+		environment = append(environment, pluginsdk.EnvContextFile+"="+file)
+		defer os.Remove(file)
+	}
+	return h.PluginHandler.Execute(executablePath, cmdArgs, environment)
+}
+
+// BuildPluginContext resolves the plugin context from the kbcli command line args, the kubeconfig
+// and the KubeBlocks deployment, the values which can not be resolved are left empty. The KubeBlocks
+// deployment is only looked up for the cluster plugins to avoid a request before every plugin run.
+func BuildPluginContext(args []string, executablePath string) *pluginsdk.Context {
+	configFlags := util.NewConfigFlagNoWarnings()
+	var output string
+	fs := pflag.NewFlagSet("plugin-context", pflag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	fs.BoolP("help", "h", false, "")
+	configFlags.AddFlags(fs)
+	fs.StringVarP(&output, "output", "o", "", "")
+	if err := fs.Parse(args); err != nil {
+		klog.V(1).Infof("Failed to parse the args for the plugin context: %v", err)
+	}
+
+	ctx := &pluginsdk.Context{
+		Version:      pluginsdk.ContextVersion,
+		KbcliVersion: version.GetVersion(),
+		Output:       output,
+		Cluster:      pluginClusterArg(fs.Args(), executablePath),
+	}
+	loader := configFlags.ToRawKubeConfigLoader()
+	ctx.Kubeconfig = strings.Join(loader.ConfigAccess().GetLoadingPrecedence(), string(filepath.ListSeparator))
+	if configFlags.Context != nil && *configFlags.Context != "" {
+		ctx.KubeContext = *configFlags.Context
+	} else if rawConfig, err := loader.RawConfig(); err == nil {
+		ctx.KubeContext = rawConfig.CurrentContext
+	}
+	if ns, _, err := loader.Namespace(); err == nil {
+		ctx.Namespace = ns
+	}
+	if pluginNamePieces(executablePath)[0] != "cluster" {
+		return ctx
+	}
+
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		klog.V(1).Infof("Failed to get the kubeconfig for the plugin context: %v", err)
+		return ctx
+	}
+	restConfig.Timeout = kubeBlocksLookupTimeout
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		klog.V(1).Infof("Failed to create the kubernetes client for the plugin context: %v", err)
+		return ctx
+	}
+	deploy, err := util.GetKubeBlocksDeploy(client)
+	if err != nil || deploy == nil {
+		klog.V(1).Infof("Failed to get the KubeBlocks deployment for the plugin context: %v", err)
+		return ctx
+	}
+	ctx.KubeBlocksNamespace = deploy.Namespace
+	ctx.KubeBlocksVersion = deploy.Labels[constant.AppVersionLabelKey]
+	return ctx
+}
+
+// pluginClusterArg returns the cluster name if the plugin is invoked as 'kbcli cluster <plugin> NAME',
+// the positional args start with the plugin name pieces, e.g. "cluster foo NAME" for 'kbcli-cluster-foo'.
+func pluginClusterArg(positionalArgs []string, executablePath string) string {
+	pieces := pluginNamePieces(executablePath)
+	if pieces[0] != "cluster" || len(positionalArgs) <= len(pieces) {
+		return ""
+	}
+	return positionalArgs[len(pieces)]
+}
+
+// pluginNamePieces returns the command pieces of the plugin, e.g. ["cluster", "foo"] for 'kbcli-cluster-foo'
+func pluginNamePieces(executablePath string) []string {
+	name := strings.TrimSuffix(filepath.Base(executablePath), ".exe")
+	for _, prefix := range ValidPluginFilenamePrefixes {
+		name = strings.TrimPrefix(name, prefix+"-")
+	}
+	return strings.Split(name, "-")
+}
@@ -0,0 +1,58 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/apecloud/kbcli/pkg/pluginsdk"
+)
+
+func TestPluginClusterArg(t *testing.T) {
+	tests := []struct {
+		args     []string
+		path     string
+		expected string
+	}{
+		{args: []string{"cluster", "foo", "mycluster"}, path: "/bin/kbcli-cluster-foo", expected: "mycluster"},
+		{args: []string{"cluster", "foo", "mycluster"}, path: "/bin/kubectl-cluster-foo.exe", expected: "mycluster"},
+		{args: []string{"cluster", "foo"}, path: "/bin/kbcli-cluster-foo"},
+		{args: []string{"foo", "mycluster"}, path: "/bin/kbcli-foo"},
+	}
+	for _, tt := range tests {
+		if cluster := pluginClusterArg(tt.args, tt.path); cluster != tt.expected {
+			t.Errorf("pluginClusterArg(%v, %s) = %q, want %q", tt.args, tt.path, cluster, tt.expected)
+		}
+	}
+}
+
+func TestBuildPluginContext(t *testing.T) {
+	t.Setenv("KUBECONFIG", "/nonexistent/kubeconfig")
+	ctx := BuildPluginContext([]string{"cluster", "foo", "-n", "demo", "mycluster", "-o", "json", "--unknown"}, "/bin/kbcli-cluster-foo")
+	if ctx.Version != pluginsdk.ContextVersion {
+		t.Errorf("unexpected context version %q", ctx.Version)
+	}
+	if ctx.Namespace != "demo" || ctx.Output != "json" || ctx.Cluster != "mycluster" {
+		t.Errorf("unexpected context: %+v", ctx)
+	}
+	if ctx.Kubeconfig != "/nonexistent/kubeconfig" {
+		t.Errorf("unexpected kubeconfig %q", ctx.Kubeconfig)
+	}
+}
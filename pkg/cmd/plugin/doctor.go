@@ -0,0 +1,472 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+var (
+	pluginDoctorExample = templates.Examples(`
+	# check the installed plugins and report the problems with the fix actions
+	kbcli plugin doctor
+
+	# check the installed plugins and apply the fix actions
+	kbcli plugin doctor --fix
+	`)
+
+	// userPath is the PATH of the user, before the plugin bin directory is added to it by kbcli
+	userPath = os.Getenv("PATH")
+)
+
+type doctorCheck string
+
+const (
+	checkMissingBinary   doctorCheck = "missing-binary"
+	checkOrphanBinary    doctorCheck = "orphan-binary"
+	checkBrokenLink      doctorCheck = "broken-link"
+	checkShadowedCommand doctorCheck = "shadowed-command"
+	checkPlatform        doctorCheck = "platform"
+	checkPath            doctorCheck = "path"
+	checkOutdated        doctorCheck = "outdated"
+)
+
+// doctorFinding is a problem found by 'kbcli plugin doctor', the fix is nil
+// if the problem can only be fixed manually.
+type doctorFinding struct {
+	check   doctorCheck
+	plugin  string
+	problem string
+	action  string
+	fix     func() error
+}
+
+type PluginDoctorOptions struct {
+	fix bool
+
+	root     *cobra.Command
+	paths    *Paths
+	userPath string
+	findings []doctorFinding
+
+	genericiooptions.IOStreams
+}
+
+func NewPluginDoctorCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &PluginDoctorOptions{
+		IOStreams: streams,
+	}
+	cmd := &cobra.Command{
+		Use:     "doctor",
+		Short:   "Check the installed plugins and fix the problems",
+		Example: pluginDoctorExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(cmd))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().BoolVar(&o.fix, "fix", false, "Apply the fix actions of the problems found")
+	return cmd
+}
+
+func (o *PluginDoctorOptions) Complete(cmd *cobra.Command) error {
+	o.root = cmd.Root()
+	o.paths = paths
+	o.userPath = userPath
+	return nil
+}
+
+func (o *PluginDoctorOptions) Run() error {
+	var err error
+	if o.findings, err = o.diagnose(); err != nil {
+		return err
+	}
+	if len(o.findings) == 0 {
+		fmt.Fprintln(o.Out, "No problems found")
+		return nil
+	}
+	printDoctorFindings(o.Out, o.findings)
+	if !o.fix {
+		fmt.Fprintln(o.Out, "\nRun 'kbcli plugin doctor --fix' to apply the fix actions")
+		return nil
+	}
+
+	var failed []string
+	// the fixes of a plugin may conflict with each other, e.g. the upgrade of a plugin
+	// which is reinstalled, so at most one fix is applied to each plugin in a run
+	fixed := map[string]bool{}
+	for _, finding := range o.findings {
+		if finding.fix == nil {
+			continue
+		}
+		if finding.plugin != "" {
+			if fixed[finding.plugin] {
+				fmt.Fprintf(o.Out, "Skipped %s: %s, run 'kbcli plugin doctor' again after plugin %s is fixed\n", finding.check, finding.action, finding.plugin)
+				continue
+			}
+			fixed[finding.plugin] = true
+		}
+		if err = finding.fix(); err != nil {
+			fmt.Fprintf(o.ErrOut, "Failed to %s: %v\n", finding.action, err)
+			failed = append(failed, string(finding.check)+"/"+finding.plugin)
+			continue
+		}
+		fmt.Fprintf(o.Out, "Fixed %s: %s\n", finding.check, finding.action)
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("failed to fix some problems: %v", failed)
+	}
+	return nil
+}
+
+// diagnose runs all checks, the checks only read the plugin state and the fixes are applied later
+func (o *PluginDoctorOptions) diagnose() ([]doctorFinding, error) {
+	receipts, err := GetInstalledPluginReceipts(o.paths.InstallReceiptsPath())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the plugin receipts")
+	}
+	var findings []doctorFinding
+	for _, check := range []func([]Receipt) ([]doctorFinding, error){
+		o.checkBinaries,
+		o.checkShadowedCommands,
+		o.checkPlatforms,
+		o.checkPath,
+		o.checkOutdated,
+	} {
+		res, err := check(receipts)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, res...)
+	}
+	return findings, nil
+}
+
+// checkBinaries checks the links in the bin path match the receipts
+func (o *PluginDoctorOptions) checkBinaries(receipts []Receipt) ([]doctorFinding, error) {
+	binPath := o.paths.BinPath()
+	entries, err := os.ReadDir(binPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	receiptOfBin := map[string]Receipt{}
+	for _, receipt := range receipts {
+		receiptOfBin[pluginNameToBin(receipt.Name, util.IsWindows())] = receipt
+	}
+
+	var findings []doctorFinding
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		path := filepath.Join(binPath, entry.Name())
+		seen[entry.Name()] = true
+		receipt, hasReceipt := receiptOfBin[entry.Name()]
+		if _, err = os.Stat(path); err != nil {
+			finding := doctorFinding{
+				check:   checkBrokenLink,
+				plugin:  receipt.Name,
+				problem: fmt.Sprintf("%s is a broken symlink", path),
+			}
+			if hasReceipt {
+				finding.action, finding.fix = o.repairFix(receipt)
+			} else {
+				finding.action, finding.fix = o.removeFix(path)
+			}
+			findings = append(findings, finding)
+			continue
+		}
+		if !hasReceipt {
+			finding := doctorFinding{
+				check:   checkOrphanBinary,
+				problem: fmt.Sprintf("%s is not installed by 'kbcli plugin install'", path),
+			}
+			finding.action, finding.fix = o.removeFix(path)
+			findings = append(findings, finding)
+		}
+	}
+
+	for _, receipt := range receipts {
+		if seen[pluginNameToBin(receipt.Name, util.IsWindows())] {
+			continue
+		}
+		finding := doctorFinding{
+			check:   checkMissingBinary,
+			plugin:  receipt.Name,
+			problem: fmt.Sprintf("plugin %s %s is installed but its binary is missing in %s", receipt.Name, receipt.Spec.Version, binPath),
+		}
+		finding.action, finding.fix = o.repairFix(receipt)
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// checkShadowedCommands checks the plugins in PATH which are never run because
+// they have the same names as the builtin commands or other plugins
+func (o *PluginDoctorOptions) checkShadowedCommands(receipts []Receipt) ([]doctorFinding, error) {
+	installed := map[string]Receipt{}
+	for _, receipt := range receipts {
+		installed[filepath.Join(o.paths.BinPath(), pluginNameToBin(receipt.Name, util.IsWindows()))] = receipt
+	}
+	lister := &PluginListOptions{
+		PluginPaths: filepath.SplitList(o.userPath),
+	}
+	// the plugins installed by kbcli are found first, the same as the PATH set by kbcli
+	lister.PluginPaths = append([]string{o.paths.BinPath()}, lister.PluginPaths...)
+	plugins, _ := lister.ListPlugins()
+
+	var findings []doctorFinding
+	seenPlugins := map[string]string{}
+	for _, path := range plugins {
+		binName := filepath.Base(path)
+		receipt, isInstalled := installed[path]
+		if existingPath, ok := seenPlugins[binName]; ok {
+			findings = append(findings, doctorFinding{
+				check:   checkShadowedCommand,
+				plugin:  receipt.Name,
+				problem: fmt.Sprintf("%s is shadowed by %s", path, existingPath),
+				action:  fmt.Sprintf("remove or rename %s manually", path),
+			})
+			continue
+		}
+		seenPlugins[binName] = path
+
+		cmdPath := strings.Split(strings.TrimSuffix(binName, filepath.Ext(binName)), "-")[1:]
+		cmd, args, err := o.root.Find(cmdPath)
+		if err != nil || cmd == o.root || len(args) > 0 {
+			continue
+		}
+		finding := doctorFinding{
+			check:   checkShadowedCommand,
+			plugin:  receipt.Name,
+			problem: fmt.Sprintf("%s is shadowed by the builtin command %q", path, cmd.CommandPath()),
+			action:  fmt.Sprintf("remove or rename %s manually", path),
+		}
+		if isInstalled {
+			finding.action, finding.fix = o.uninstallFix(receipt.Name)
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// checkPlatforms checks the installed plugins still support this platform
+func (o *PluginDoctorOptions) checkPlatforms(receipts []Receipt) ([]doctorFinding, error) {
+	var findings []doctorFinding
+	for _, receipt := range receipts {
+		if _, ok, err := GetMatchingPlatform(receipt.Spec.Platforms); err == nil && ok {
+			continue
+		}
+		finding := doctorFinding{
+			check:   checkPlatform,
+			plugin:  receipt.Name,
+			problem: fmt.Sprintf("plugin %s %s does not support the platform %s", receipt.Name, receipt.Spec.Version, OSArch()),
+		}
+		finding.action, finding.fix = o.uninstallFix(receipt.Name)
+		if plugin, err := o.loadLatest(receipt); err == nil {
+			if _, ok, err := GetMatchingPlatform(plugin.Spec.Platforms); err == nil && ok {
+				finding.action, finding.fix = o.reinstallFix(receipt, plugin)
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// checkPath checks the plugin bin path is in the PATH of the user, which is required
+// to run the plugins by kubectl or directly
+func (o *PluginDoctorOptions) checkPath(receipts []Receipt) ([]doctorFinding, error) {
+	binPath := filepath.Clean(o.paths.BinPath())
+	for _, dir := range filepath.SplitList(o.userPath) {
+		if filepath.Clean(dir) == binPath {
+			return nil, nil
+		}
+	}
+	finding := doctorFinding{
+		check:   checkPath,
+		problem: fmt.Sprintf("%s is not in PATH", binPath),
+		action:  fmt.Sprintf("add 'export PATH=\"%s:$PATH\"' to your shell profile manually", binPath),
+	}
+	if profile := shellProfile(); profile != "" {
+		finding.action = fmt.Sprintf("add %s to PATH in %s", binPath, profile)
+		finding.fix = func() error {
+			f, err := os.OpenFile(profile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = fmt.Fprintf(f, "\n# added by 'kbcli plugin doctor --fix'\nexport PATH=\"%s:$PATH\"\n", binPath)
+			return err
+		}
+	}
+	return []doctorFinding{finding}, nil
+}
+
+// checkOutdated checks the newer versions in the source indexes of the plugins
+func (o *PluginDoctorOptions) checkOutdated(receipts []Receipt) ([]doctorFinding, error) {
+	var findings []doctorFinding
+	for _, receipt := range receipts {
+		plugin, err := o.loadLatest(receipt)
+		if err != nil {
+			continue
+		}
+		curv, err := parseVersion(receipt.Spec.Version)
+		if err != nil {
+			continue
+		}
+		newv, err := parseVersion(plugin.Spec.Version)
+		if err != nil || !curv.LessThan(newv) {
+			continue
+		}
+		finding := doctorFinding{
+			check:   checkOutdated,
+			plugin:  receipt.Name,
+			problem: fmt.Sprintf("plugin %s %s is outdated, %s is available in the %s index", receipt.Name, receipt.Spec.Version, plugin.Spec.Version, receipt.Status.Source.Name),
+		}
+		if receipt.Status.Pinned != "" {
+			finding.action = fmt.Sprintf("it is pinned to %s, run 'kbcli plugin upgrade %s --unpin' manually", receipt.Status.Pinned, receipt.Name)
+		} else {
+			index := receipt.Status.Source.Name
+			finding.action = fmt.Sprintf("upgrade %s to %s", receipt.Name, plugin.Spec.Version)
+			finding.fix = func() error {
+				return Upgrade(o.paths, plugin, index)
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// loadLatest loads the latest manifest of the plugin from its source index
+func (o *PluginDoctorOptions) loadLatest(receipt Receipt) (Plugin, error) {
+	index := receipt.Status.Source.Name
+	if index == ArchiveIndexName {
+		return Plugin{}, errors.Errorf("plugin %s is installed from an archive", receipt.Name)
+	}
+	return LoadPluginByName(o.paths.IndexPluginsPath(index), receipt.Name)
+}
+
+// repairFix relinks the active version if it is installed, or reinstalls it from the source index
+func (o *PluginDoctorOptions) repairFix(receipt Receipt) (string, func() error) {
+	name, version := receipt.Name, receipt.Spec.Version
+	candidate, ok, err := GetMatchingPlatform(receipt.Spec.Platforms)
+	if err == nil && ok {
+		applyDefaults(&candidate)
+		bin := filepath.Join(o.paths.PluginVersionInstallPath(name, version), filepath.FromSlash(candidate.Bin))
+		if _, err = os.Stat(bin); err == nil {
+			return fmt.Sprintf("relink %s to %s", name, bin), func() error {
+				return createOrUpdateLink(o.paths.BinPath(), bin, name)
+			}
+		}
+	}
+	if receipt.Status.Source.Name == ArchiveIndexName {
+		return o.uninstallFix(name)
+	}
+	plugin, err := LoadPluginVersion(o.paths, receipt.Status.Source.Name, name, version)
+	if err != nil {
+		return o.uninstallFix(name)
+	}
+	return o.reinstallFix(receipt, plugin)
+}
+
+// reinstallFix replaces the install directory of the plugin version and makes it the active one,
+// the other installed versions are kept
+func (o *PluginDoctorOptions) reinstallFix(receipt Receipt, plugin Plugin) (string, func() error) {
+	return fmt.Sprintf("reinstall %s %s", plugin.Name, plugin.Spec.Version), func() error {
+		candidate, ok, err := GetMatchingPlatform(plugin.Spec.Platforms)
+		if err != nil {
+			return errors.Wrap(err, "failed trying to find a matching platform in plugin spec")
+		}
+		if !ok {
+			return errors.Errorf("plugin %q does not offer installation for this platform", plugin.Name)
+		}
+		installDir := o.paths.PluginVersionInstallPath(plugin.Name, plugin.Spec.Version)
+		if err = os.RemoveAll(installDir); err != nil {
+			return errors.Wrapf(err, "could not remove plugin directory %q", installDir)
+		}
+		if err = install(installOperation{
+			pluginName: plugin.Name,
+			platform:   candidate,
+
+			binDir:     o.paths.BinPath(),
+			installDir: installDir,
+		}, InstallOpts{}); err != nil {
+			return errors.Wrap(err, "install failed")
+		}
+		receipt.addVersion(plugin)
+		receipt.setActive(plugin)
+		if receipt.Status.Pinned != "" {
+			receipt.Status.Pinned = plugin.Spec.Version
+		}
+		return StoreReceipt(receipt, o.paths.PluginInstallReceiptPath(plugin.Name))
+	}
+}
+
+func (o *PluginDoctorOptions) uninstallFix(name string) (string, func() error) {
+	return fmt.Sprintf("uninstall %s", name), func() error {
+		return uninstall(o.paths, name)
+	}
+}
+
+func (o *PluginDoctorOptions) removeFix(path string) (string, func() error) {
+	return fmt.Sprintf("remove %s", path), func() error {
+		return os.Remove(path)
+	}
+}
+
+// shellProfile returns the profile of the user shell to set PATH
+func shellProfile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	switch filepath.Base(os.Getenv("SHELL")) {
+	case "bash":
+		return filepath.Join(home, ".bashrc")
+	case "zsh":
+		return filepath.Join(home, ".zshrc")
+	}
+	return ""
+}
+
+func printDoctorFindings(out io.Writer, findings []doctorFinding) {
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("CHECK", "PLUGIN", "PROBLEM", "FIX")
+	for _, finding := range findings {
+		action := finding.action
+		if finding.fix == nil {
+			action += " (manual)"
+		}
+		tbl.AddRow(finding.check, finding.plugin, finding.problem, action)
+	}
+	tbl.Print()
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+func TestPluginDoctor(t *testing.T) {
+	t.Setenv("SHELL", "")
+	p := NewPaths(t.TempDir())
+	if err := EnsureDirs(p.BinPath(), p.InstallReceiptsPath(), p.IndexBase()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	newTestIndex(t, p, "foo", "v1.0.0", "v1.1.0")
+
+	install := func(plugin Plugin, link bool, pinned bool) {
+		dir := p.PluginVersionInstallPath(plugin.Name, plugin.Spec.Version)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		bin := filepath.Join(dir, plugin.Spec.Platforms[0].Bin)
+		if err := os.WriteFile(bin, []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if link {
+			if err := createOrUpdateLink(p.BinPath(), bin, plugin.Name); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		receipt := NewReceipt(plugin, DefaultIndexName, metav1.Now())
+		if pinned {
+			receipt.Status.Pinned = plugin.Spec.Version
+		}
+		if err := StoreReceipt(receipt, p.PluginInstallReceiptPath(plugin.Name)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// foo is outdated and has no binary link, bar is pinned and has no binary link,
+	// version shadows the builtin command
+	install(testPluginManifest("foo", "v1.0.0"), false, false)
+	install(testPluginManifest("bar", "v1.0.0"), false, true)
+	install(testPluginManifest("version", "v1.0.0"), true, false)
+	// old does not support this platform
+	old := testPluginManifest("old", "v1.0.0")
+	old.Spec.Platforms[0].Selector.MatchLabels["os"] = "nonexistent"
+	install(old, true, false)
+	// baz is not installed by kbcli and qux is a broken link
+	if err := os.WriteFile(filepath.Join(p.BinPath(), "kbcli-baz"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(p.BinPath(), "nonexistent"), filepath.Join(p.BinPath(), "kbcli-qux")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	root := &cobra.Command{Use: "kbcli"}
+	root.AddCommand(&cobra.Command{Use: "version", Run: func(*cobra.Command, []string) {}})
+	streams, _, out, _ := genericiooptions.NewTestIOStreams()
+	o := &PluginDoctorOptions{
+		root:      root,
+		paths:     p,
+		userPath:  "/usr/bin",
+		IOStreams: streams,
+	}
+	checks := func() []string {
+		findings, err := o.diagnose()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var res []string
+		for _, finding := range findings {
+			res = append(res, string(finding.check)+"/"+finding.plugin)
+		}
+		sort.Strings(res)
+		return res
+	}
+	expected := []string{
+		"broken-link/",
+		"missing-binary/bar",
+		"missing-binary/foo",
+		"orphan-binary/",
+		"outdated/foo",
+		"path/",
+		"platform/old",
+		"shadowed-command/version",
+	}
+	if res := checks(); !slices.Equal(res, expected) {
+		t.Fatalf("expected findings %v, got %v", expected, res)
+	}
+
+	o.fix = true
+	if err := o.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Fixed missing-binary")) {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+	// foo is relinked and its upgrade is left to the next run
+	if !bytes.Contains(out.Bytes(), []byte("Skipped outdated: upgrade foo to v1.1.0")) {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+	// the skipped and manual fixes are left
+	expected = []string{"outdated/foo", "path/"}
+	if res := checks(); !slices.Equal(res, expected) {
+		t.Fatalf("expected findings %v after fix, got %v", expected, res)
+	}
+	if _, err := os.Stat(p.PluginInstallReceiptPath("old")); !os.IsNotExist(err) {
+		t.Errorf("expected plugin old to be uninstalled")
+	}
+}
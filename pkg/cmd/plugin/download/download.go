@@ -208,6 +208,15 @@ func extractArchive(dst string, at io.ReaderAt, size int64) error {
 	return errors.Wrap(exf(dst, at, size), "failed to extract file")
 }
 
+// Extract extracts the zip or tar.gz archive read from r into dst.
+func Extract(dst string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the archive")
+	}
+	return extractArchive(dst, bytes.NewReader(data), int64(len(data)))
+}
+
 // Downloader is responsible for fetching, verifying and extracting a binary.
 type Downloader struct {
 	verifier Verifier
@@ -46,6 +46,15 @@ var (
 	kbcli plugin index add default https://github.com/apecloud/block-index.git
 
 	kbcli plugin index add krew https://github.com/kubernetes-sigs/krew-index.git
+
+	# Add a plugin index from a local directory or archive
+	kbcli plugin index add local file:///path/to/index
+
+	# Add a plugin index from an archive hosted by an artifact server
+	kbcli plugin index add internal https://artifacts.example.com/plugins/index.tgz
+
+	# Add a plugin index from an OCI artifact, e.g. pushed by 'oras push registry.example.com/plugins/index:latest index.tgz'
+	kbcli plugin index add oci oci://registry.example.com/plugins/index:latest
 	`)
 
 	pluginDeleteIndexExample = templates.Examples(`
@@ -115,7 +124,7 @@ func (o *PluginIndexOptions) UpdateIndex() error {
 	for _, idx := range indexes {
 		indexPath := paths.IndexPath(idx.Name)
 		klog.V(1).Infof("Updating the local copy of plugin index (%s)", indexPath)
-		if err := util.EnsureUpdated(idx.URL, indexPath); err != nil {
+		if err := fetchIndex(idx.URL, indexPath); err != nil {
 			klog.Warningf("failed to update index %q: %v", idx.Name, err)
 			continue
 		}
@@ -222,7 +231,7 @@ func ListIndexes(paths *Paths) ([]Index, error) {
 			continue
 		}
 		indexName := e.Name()
-		remote, err := util.GitGetRemoteURL(paths.IndexPath(indexName))
+		remote, err := getIndexURL(paths.IndexPath(indexName))
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to list the remote URL for index %s", indexName)
 		}
@@ -242,7 +251,10 @@ func AddIndex(paths *Paths, name, url string) error {
 	}
 	dir := paths.IndexPath(name)
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return util.EnsureCloned(url, dir)
+		if getIndexSourceType(url) == gitIndexSource {
+			return util.EnsureCloned(url, dir)
+		}
+		return fetchIndex(url, dir)
 	} else if err != nil {
 		return err
 	}
@@ -0,0 +1,215 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/apecloud/kbcli/pkg/cmd/plugin/download"
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+// indexSourceFile records the URL of an index which is not a git repository
+const indexSourceFile = ".kbcli-index-source"
+
+type indexSourceType string
+
+const (
+	gitIndexSource     indexSourceType = "git"
+	fileIndexSource    indexSourceType = "file"
+	archiveIndexSource indexSourceType = "archive"
+	ociIndexSource     indexSourceType = "oci"
+)
+
+// getIndexSourceType returns the source type of the index URL, the local directory or archive
+// is specified by file://, the archive hosted by http(s) must end with .tgz, .tar.gz or .zip,
+// the OCI artifact is specified by oci://, and other URLs are git repositories.
+func getIndexSourceType(url string) indexSourceType {
+	switch {
+	case strings.HasPrefix(url, "file://"):
+		return fileIndexSource
+	case strings.HasPrefix(url, "oci://"):
+		return ociIndexSource
+	case (strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")) && isArchive(url):
+		return archiveIndexSource
+	default:
+		return gitIndexSource
+	}
+}
+
+func isArchive(path string) bool {
+	for _, ext := range []string{".tgz", ".tar.gz", ".zip"} {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// getIndexURL returns the URL of the index, which is recorded in the source file
+// or is the remote URL of the git repository.
+func getIndexURL(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, indexSourceFile))
+	if err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+	return util.GitGetRemoteURL(dir)
+}
+
+// isGitIndex checks whether the index is a git repository, whose history can be searched
+func isGitIndex(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, indexSourceFile))
+	return os.IsNotExist(err)
+}
+
+// fetchIndex fetches the index from the URL into dir, the existing index in dir
+// is replaced only after the new one is fetched successfully.
+func fetchIndex(url, dir string) error {
+	sourceType := getIndexSourceType(url)
+	if sourceType == gitIndexSource {
+		return util.EnsureUpdated(url, dir)
+	}
+
+	staging, err := os.MkdirTemp("", "kbcli-index")
+	if err != nil {
+		return errors.Wrap(err, "could not create staging dir")
+	}
+	defer func() {
+		if err := os.RemoveAll(staging); err != nil {
+			klog.Warningf("failed to clean up index staging directory: %s", err)
+		}
+	}()
+
+	klog.V(1).Infof("Fetching %s plugin index from %s", sourceType, url)
+	switch sourceType {
+	case fileIndexSource:
+		err = fetchFileIndex(strings.TrimPrefix(url, "file://"), staging)
+	case archiveIndexSource:
+		err = fetchArchiveIndex(url, staging)
+	case ociIndexSource:
+		err = fetchOCIIndex(strings.TrimPrefix(url, "oci://"), staging)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch plugin index from %s", url)
+	}
+
+	root, err := findIndexRoot(staging)
+	if err != nil {
+		return errors.Wrapf(err, "invalid plugin index %s", url)
+	}
+	if err = os.WriteFile(filepath.Join(root, indexSourceFile), []byte(url+"\n"), 0644); err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	return renameOrCopy(root, dir)
+}
+
+func fetchFileIndex(path, dst string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return copyTree(path, dst)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return download.Extract(dst, f)
+}
+
+func fetchArchiveIndex(url, dst string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to download %s, status: %s", url, resp.Status)
+	}
+	return download.Extract(dst, resp.Body)
+}
+
+// fetchOCIIndex pulls the OCI artifact and extracts its archive layers, e.g. the artifact
+// pushed by 'oras push registry/repo:tag index.tgz'.
+func fetchOCIIndex(ref, dst string) error {
+	img, err := crane.Pull(ref)
+	if err != nil {
+		return err
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+	extracted := 0
+	for _, layer := range layers {
+		rc, err := layer.Compressed()
+		if err != nil {
+			return err
+		}
+		err = download.Extract(dst, rc)
+		rc.Close()
+		if err != nil {
+			digest, _ := layer.Digest()
+			klog.V(1).Infof("Skip layer %s of %s: %v", digest, ref, err)
+			continue
+		}
+		extracted++
+	}
+	if extracted == 0 {
+		return errors.Errorf("no zip or tar.gz layer found in %s", ref)
+	}
+	return nil
+}
+
+// findIndexRoot returns the directory containing the plugin manifests directory,
+// which is the extracted dir or the only directory in it.
+func findIndexRoot(dir string) (string, error) {
+	for {
+		for _, pluginsDir := range []string{"plugins", "krew-plugins", "cli-plugins"} {
+			if fi, err := os.Stat(filepath.Join(dir, pluginsDir)); err == nil && fi.IsDir() {
+				return dir, nil
+			}
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", err
+		}
+		if len(entries) != 1 || !entries[0].IsDir() {
+			return "", fmt.Errorf("no plugins directory found")
+		}
+		dir = filepath.Join(dir, entries[0].Name())
+	}
+}
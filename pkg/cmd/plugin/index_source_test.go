@@ -0,0 +1,203 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGetIndexSourceType(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected indexSourceType
+	}{
+		{url: "https://github.com/apecloud/block-index.git", expected: gitIndexSource},
+		{url: "git@github.com:apecloud/block-index.git", expected: gitIndexSource},
+		{url: "file:///tmp/index", expected: fileIndexSource},
+		{url: "file:///tmp/index.tgz", expected: fileIndexSource},
+		{url: "https://artifacts.example.com/index.tgz", expected: archiveIndexSource},
+		{url: "http://artifacts.example.com/index.zip", expected: archiveIndexSource},
+		{url: "oci://registry.example.com/plugins/index:latest", expected: ociIndexSource},
+	}
+	for _, tt := range tests {
+		if sourceType := getIndexSourceType(tt.url); sourceType != tt.expected {
+			t.Errorf("getIndexSourceType(%q) = %s, want %s", tt.url, sourceType, tt.expected)
+		}
+	}
+}
+
+// newTestIndexArchive creates a tar.gz index archive, in which the plugins directory is nested in 'index/'
+func newTestIndexArchive(t *testing.T, plugins ...Plugin) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for _, dir := range []string{"index/", "index/plugins/"} {
+		if err := tw.WriteHeader(&tar.Header{Name: dir, Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, plugin := range plugins {
+		b, err := yaml.Marshal(plugin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		hdr := &tar.Header{Name: "index/plugins/" + plugin.Name + ManifestExtension, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(b))}
+		if err = tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err = tw.Write(b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func checkFetchedIndex(t *testing.T, p *Paths, name, url, version string) {
+	indexes, err := ListIndexes(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(indexes) != 1 || indexes[0].Name != name || indexes[0].URL != url {
+		t.Fatalf("unexpected indexes: %+v", indexes)
+	}
+	versions, err := listPluginVersions(p, name, "foo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Spec.Version != version {
+		t.Errorf("unexpected plugin versions: %+v", versions)
+	}
+}
+
+func TestFetchFileIndex(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "plugins"), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	writeManifest := func(version string) {
+		b, err := yaml.Marshal(testPluginManifest("foo", version))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err = os.WriteFile(filepath.Join(src, "plugins", "foo.yaml"), b, 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	writeManifest("v1.0.0")
+
+	p := NewPaths(t.TempDir())
+	url := "file://" + src
+	if err := AddIndex(p, "local", url); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if isGitIndex(p.IndexPath("local")) {
+		t.Error("expected the index not to be a git index")
+	}
+	checkFetchedIndex(t, p, "local", url, "v1.0.0")
+	if err := AddIndex(p, "local", url); err == nil {
+		t.Error("expected error when adding an existing index")
+	}
+
+	// the index is replaced when it is fetched again
+	writeManifest("v1.1.0")
+	if err := fetchIndex(url, p.IndexPath("local")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkFetchedIndex(t, p, "local", url, "v1.1.0")
+
+	// the local archive
+	archive := filepath.Join(t.TempDir(), "index.tgz")
+	if err := os.WriteFile(archive, newTestIndexArchive(t, testPluginManifest("foo", "v2.0.0")), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p = NewPaths(t.TempDir())
+	if err := AddIndex(p, "local", "file://"+archive); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkFetchedIndex(t, p, "local", "file://"+archive, "v2.0.0")
+
+	// an archive without plugins directory
+	if err := fetchIndex("file://"+src+"/plugins/foo.yaml", filepath.Join(t.TempDir(), "invalid")); err == nil {
+		t.Error("expected error for an invalid index")
+	}
+}
+
+func TestFetchOCIIndex(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+
+	layer := static.NewLayer(newTestIndexArchive(t, testPluginManifest("foo", "v1.0.0")), types.OCILayer)
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ref := strings.TrimPrefix(server.URL, "http://") + "/plugins/index:latest"
+	if err = crane.Push(img, ref); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p := NewPaths(t.TempDir())
+	if err = AddIndex(p, "oci", "oci://"+ref); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkFetchedIndex(t, p, "oci", "oci://"+ref, "v1.0.0")
+}
+
+func TestFindSidecarManifest(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "foo-v1.0.0.tar.gz")
+	if _, err := findSidecarManifest(archive); err == nil {
+		t.Error("expected error when no sidecar manifest exists")
+	}
+	for _, manifest := range []string{"foo-v1.0.0.yaml", "foo-v1.0.0.tar.gz.yaml"} {
+		path := filepath.Join(dir, manifest)
+		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		found, err := findSidecarManifest(archive)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if found != path {
+			t.Errorf("expected sidecar manifest %s, got %s", path, found)
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -42,10 +43,23 @@ var (
 
 	# install a kbcli or kubectl plugin by name and index
 	kbcli plugin install [INDEX/PLUGIN]
+
+	# install the specified version of a plugin side by side with the installed versions and pin it
+	kbcli plugin install [PLUGIN]@[VERSION]
+
+	# install a plugin from a local archive without accessing the index, the plugin manifest is read
+	# from the sidecar file 'foo-v1.0.0.tar.gz.yaml' or 'foo-v1.0.0.yaml' next to the archive
+	kbcli plugin install --archive ./foo-v1.0.0.tar.gz
+
+	# install a plugin from a local archive with the specified plugin manifest
+	kbcli plugin install --archive ./foo-v1.0.0.tar.gz --manifest ./foo.yaml
 	`)
 )
 
 type PluginInstallOption struct {
+	archive  string
+	manifest string
+
 	plugins []pluginEntry
 
 	genericiooptions.IOStreams
@@ -54,6 +68,10 @@ type PluginInstallOption struct {
 type pluginEntry struct {
 	index  string
 	plugin Plugin
+	// pin is true if the version is specified by NAME@VERSION
+	pin bool
+	// archive is the local archive to install the plugin from
+	archive string
 }
 
 func NewPluginInstallCmd(streams genericiooptions.IOStreams) *cobra.Command {
@@ -69,20 +87,46 @@ func NewPluginInstallCmd(streams genericiooptions.IOStreams) *cobra.Command {
 			cmdutil.CheckErr(o.Install())
 		},
 	}
+	cmd.Flags().StringVar(&o.archive, "archive", "", "Install the plugin from the local archive instead of downloading it")
+	cmd.Flags().StringVar(&o.manifest, "manifest", "", "The plugin manifest of the archive, use the sidecar file next to the archive by default")
 	return cmd
 }
 
 func (o *PluginInstallOption) Complete(names []string) error {
+	if o.archive != "" {
+		return o.completeArchive(names)
+	}
+	if o.manifest != "" {
+		return errors.New("--manifest can only be used with --archive")
+	}
+	if len(names) == 0 {
+		return errors.New("no plugin name specified")
+	}
 	for _, name := range names {
-		indexName, pluginName := CanonicalPluginName(name)
+		nameWithIndex, version := ParsePluginVersion(name)
+		indexName, pluginName := CanonicalPluginName(nameWithIndex)
 
-		// check whether the plugin exists
-		if _, err := os.Stat(paths.PluginInstallReceiptPath(pluginName)); err == nil {
-			fmt.Fprintf(o.Out, "plugin %q is already installed\n", name)
-			continue
+		// check whether the plugin or the plugin version exists
+		if receipt, err := ReadReceiptFromFile(paths.PluginInstallReceiptPath(pluginName)); err == nil {
+			if version == "" {
+				fmt.Fprintf(o.Out, "plugin %q is already installed\n", name)
+				continue
+			}
+			if _, ok := findInstalledVersion(receipt, version); ok {
+				fmt.Fprintf(o.Out, "plugin %q is already installed, use 'kbcli plugin use %s@%s' to switch to it\n", name, pluginName, version)
+				continue
+			}
+		} else if !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to look up receipt of plugin %q", pluginName)
 		}
 
-		plugin, err := LoadPluginByName(paths.IndexPluginsPath(indexName), pluginName)
+		var plugin Plugin
+		var err error
+		if version == "" {
+			plugin, err = LoadPluginByName(paths.IndexPluginsPath(indexName), pluginName)
+		} else {
+			plugin, err = LoadPluginVersion(paths, indexName, pluginName, version)
+		}
 		if err != nil {
 			if os.IsNotExist(err) {
 				return errors.Errorf("plugin %q does not exist in the %s plugin index", name, indexName)
@@ -92,18 +136,72 @@ func (o *PluginInstallOption) Complete(names []string) error {
 		o.plugins = append(o.plugins, pluginEntry{
 			index:  indexName,
 			plugin: plugin,
+			pin:    version != "",
 		})
 	}
 	return nil
 }
 
+// completeArchive reads the plugin manifest of the local archive, the archive is verified
+// with the sha256 sum in the manifest when it is installed.
+func (o *PluginInstallOption) completeArchive(names []string) error {
+	if _, err := os.Stat(o.archive); err != nil {
+		return errors.Wrapf(err, "failed to read the archive %s", o.archive)
+	}
+	manifestPath := o.manifest
+	if manifestPath == "" {
+		var err error
+		if manifestPath, err = findSidecarManifest(o.archive); err != nil {
+			return err
+		}
+	}
+	plugin, err := ReadPluginFromFile(manifestPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the plugin manifest %s", manifestPath)
+	}
+	if len(names) > 1 || (len(names) == 1 && names[0] != plugin.Name) {
+		return errors.Errorf("the archive %s is plugin %q, which does not match %v", o.archive, plugin.Name, names)
+	}
+	if receipt, err := ReadReceiptFromFile(paths.PluginInstallReceiptPath(plugin.Name)); err == nil {
+		if _, ok := findInstalledVersion(receipt, plugin.Spec.Version); ok {
+			fmt.Fprintf(o.Out, "plugin %q %s is already installed\n", plugin.Name, plugin.Spec.Version)
+			return nil
+		}
+	}
+	o.plugins = append(o.plugins, pluginEntry{
+		index:   ArchiveIndexName,
+		plugin:  plugin,
+		archive: o.archive,
+	})
+	return nil
+}
+
+// findSidecarManifest finds the plugin manifest next to the archive, which is named
+// after the archive, e.g. 'foo-v1.0.0.tar.gz.yaml' or 'foo-v1.0.0.yaml' for 'foo-v1.0.0.tar.gz'.
+func findSidecarManifest(archive string) (string, error) {
+	candidates := []string{archive + ManifestExtension}
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip"} {
+		if strings.HasSuffix(archive, ext) {
+			candidates = append(candidates, strings.TrimSuffix(archive, ext)+ManifestExtension)
+			break
+		}
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", errors.Errorf("no plugin manifest found for the archive %s, tried %s, use --manifest to specify it",
+		archive, strings.Join(candidates, ", "))
+}
+
 func (o *PluginInstallOption) Install() error {
 	var failed []string
 	var returnErr error
 	for _, entry := range o.plugins {
 		plugin := entry.plugin
 		fmt.Fprintf(o.Out, "Installing plugin: %s\n", plugin.Name)
-		err := Install(paths, plugin, entry.index, InstallOpts{})
+		err := Install(paths, plugin, entry.index, InstallOpts{Pin: entry.pin, ArchiveFileOverride: entry.archive})
 		if err == ErrIsAlreadyInstalled {
 			continue
 		}
@@ -116,6 +214,9 @@ func (o *PluginInstallOption) Install() error {
 			continue
 		}
 		fmt.Fprintf(o.Out, "Installed plugin: %s\n", plugin.Name)
+		if entry.pin {
+			fmt.Fprintf(o.Out, "Plugin %q is pinned to %s\n", plugin.Name, plugin.Spec.Version)
+		}
 		output := fmt.Sprintf("Use this plugin:\n\tkubectl %s\n", plugin.Name)
 		if plugin.Spec.Homepage != "" {
 			output += fmt.Sprintf("Documentation:\n\t%s\n", plugin.Spec.Homepage)
@@ -131,14 +232,19 @@ func (o *PluginInstallOption) Install() error {
 	return nil
 }
 
-// Install downloads and installs a plugin. The operation tries
+// Install downloads and installs a plugin. If other versions of the plugin are installed,
+// the version is installed side by side and becomes the active one. The operation tries
 // to keep the plugin dir in a healthy state if it fails during the process.
 func Install(p *Paths, plugin Plugin, indexName string, opts InstallOpts) error {
 	klog.V(2).Infof("Looking for installed versions")
-	_, err := ReadReceiptFromFile(p.PluginInstallReceiptPath(plugin.Name))
+	receipt, err := ReadReceiptFromFile(p.PluginInstallReceiptPath(plugin.Name))
 	if err == nil {
-		return ErrIsAlreadyInstalled
-	} else if !os.IsNotExist(err) {
+		if _, ok := findInstalledVersion(receipt, plugin.Spec.Version); ok {
+			return ErrIsAlreadyInstalled
+		}
+	} else if os.IsNotExist(err) {
+		receipt = NewReceipt(Plugin{}, indexName, metav1.Now())
+	} else {
 		return errors.Wrap(err, "failed to look up plugin receipt")
 	}
 
@@ -165,7 +271,12 @@ func Install(p *Paths, plugin Plugin, indexName string, opts InstallOpts) error
 	}
 
 	klog.V(3).Infof("Storing install receipt for plugin %s", plugin.Name)
-	err = StoreReceipt(NewReceipt(plugin, indexName, metav1.Now()), p.PluginInstallReceiptPath(plugin.Name))
+	receipt.addVersion(plugin)
+	receipt.setActive(plugin)
+	if opts.Pin {
+		receipt.Status.Pinned = plugin.Spec.Version
+	}
+	err = StoreReceipt(receipt, p.PluginInstallReceiptPath(plugin.Name))
 	return errors.Wrap(err, "installation receipt could not be stored, uninstall may fail")
 }
 
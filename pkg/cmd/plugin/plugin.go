@@ -71,6 +71,10 @@ func NewPluginCmd(streams genericiooptions.IOStreams) *cobra.Command {
 		NewPluginSearchCmd(streams),
 		NewPluginDescribeCmd(streams),
 		NewPluginUpgradeCmd(streams),
+		NewPluginUseCmd(streams),
+		NewPluginRollbackCmd(streams),
+		NewPluginSyncCmd(streams),
+		NewPluginDoctorCmd(streams),
 	)
 	return cmd
 }
@@ -136,9 +140,9 @@ func (o *PluginListOptions) Run() error {
 
 	if pluginWarnings > 0 {
 		if pluginWarnings == 1 {
-			pluginErrors = append(pluginErrors, fmt.Errorf("error: one plugin warining was found"))
+			pluginErrors = append(pluginErrors, fmt.Errorf("error: one plugin warining was found, run 'kbcli plugin doctor' for details"))
 		} else {
-			pluginErrors = append(pluginErrors, fmt.Errorf("error: %d plugin warnings were found", pluginWarnings))
+			pluginErrors = append(pluginErrors, fmt.Errorf("error: %d plugin warnings were found, run 'kbcli plugin doctor' for details", pluginWarnings))
 		}
 	}
 	if len(pluginErrors) > 0 {
@@ -227,7 +231,8 @@ func (v *CommandOverrideVerifier) Verify(path string) []error {
 		v.seenPlugins[binName] = path
 	}
 
-	if cmd, _, err := v.root.Find(cmdPath); err == nil {
+	// the plugin with extra args like 'kbcli-cluster-foo' is run as 'kbcli cluster foo'
+	if cmd, args, err := v.root.Find(cmdPath); err == nil && len(args) == 0 {
 		errors = append(errors, fmt.Errorf("warning: %q overwrites existing kbcli command: %q", path, cmd.CommandPath()))
 	}
 
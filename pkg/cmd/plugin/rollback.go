@@ -0,0 +1,71 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	pluginRollbackExample = templates.Examples(`
+	# roll back a plugin to the previously active version and pin it
+	kbcli plugin rollback [PLUGIN]
+	`)
+)
+
+func NewPluginRollbackCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rollback NAME",
+		Short:   "Roll back a plugin to the previously active version",
+		Example: pluginRollbackExample,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(rollbackPlugin(streams.Out, paths, args[0]))
+		},
+	}
+	return cmd
+}
+
+func rollbackPlugin(out io.Writer, p *Paths, name string) error {
+	receipt, err := readInstalledReceipt(p, name)
+	if err != nil {
+		return err
+	}
+	previous, current := receipt.Status.Previous, receipt.Spec.Version
+	if previous == "" {
+		return errors.Errorf("plugin %q has no previous version to roll back to", name)
+	}
+	if err = switchVersion(p, &receipt, previous); err != nil {
+		return err
+	}
+	receipt.Status.Pinned = previous
+	if err = StoreReceipt(receipt, p.PluginInstallReceiptPath(name)); err != nil {
+		return errors.Wrap(err, "installation receipt could not be stored")
+	}
+	fmt.Fprintf(out, "Plugin %q is rolled back from %s to %s and pinned\n", name, current, previous)
+	return nil
+}
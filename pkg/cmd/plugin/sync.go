@@ -0,0 +1,569 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+)
+
+var (
+	pluginSyncExample = templates.Examples(`
+	# show the plan to sync the installed plugins with the manifest
+	kbcli plugin sync -f plugins.yaml --dry-run
+
+	# sync the installed plugins with the manifest, the resolved versions and sha256 sums
+	# are written to the lockfile 'plugins.lock.yaml' and reused by the later syncs
+	kbcli plugin sync -f plugins.yaml
+
+	# sync the installed plugins and uninstall the plugins not listed in the manifest
+	kbcli plugin sync -f plugins.yaml --prune
+
+	# resolve the versions again and ignore the versions in the lockfile
+	kbcli plugin sync -f plugins.yaml --update
+
+	# an example of the plugins manifest, the version is a semver constraint
+	# and the latest version in the index is used if not specified
+	indexes:
+	- name: krew
+	  url: https://github.com/kubernetes-sigs/krew-index.git
+	plugins:
+	- name: foo
+	  version: ">= v0.2.0, < v1.0.0"
+	- name: bar
+	  index: krew
+	  version: v0.3.1
+	`)
+)
+
+// pluginSetManifest is the declarative plugin set synced by 'kbcli plugin sync'
+type pluginSetManifest struct {
+	Indexes []pluginSetIndex `json:"indexes,omitempty"`
+	Plugins []pluginSetItem  `json:"plugins"`
+}
+
+type pluginSetIndex struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type pluginSetItem struct {
+	Name string `json:"name"`
+	// the index to find the plugin, use the default index if not specified
+	Index string `json:"index,omitempty"`
+	// the semver constraint of the plugin version
+	Version string `json:"version,omitempty"`
+}
+
+// pluginSetLockfile records the resolved versions and sha256 sums of the plugins
+type pluginSetLockfile struct {
+	Indexes []pluginSetIndex `json:"indexes,omitempty"`
+	Plugins []pluginLockItem `json:"plugins"`
+}
+
+type pluginLockItem struct {
+	Name    string `json:"name"`
+	Index   string `json:"index"`
+	Version string `json:"version"`
+	// the archives and sha256 sums of all platforms of the plugin version
+	Platforms []pluginLockPlatform `json:"platforms"`
+}
+
+type pluginLockPlatform struct {
+	URI    string `json:"uri"`
+	Sha256 string `json:"sha256"`
+}
+
+type pluginSyncAction string
+
+const (
+	pluginSyncAddIndex  pluginSyncAction = "add-index"
+	pluginSyncInstall   pluginSyncAction = "install"
+	pluginSyncUpgrade   pluginSyncAction = "upgrade"
+	pluginSyncDowngrade pluginSyncAction = "downgrade"
+	pluginSyncUninstall pluginSyncAction = "uninstall"
+)
+
+type pluginSyncStep struct {
+	action         pluginSyncAction
+	name           string
+	index          string
+	currentVersion string
+	targetVersion  string
+	plugin         Plugin
+}
+
+type PluginSyncOptions struct {
+	file     string
+	lockfile string
+	prune    bool
+	update   bool
+	dryRun   bool
+
+	manifest *pluginSetManifest
+	lock     *pluginSetLockfile
+	plan     []pluginSyncStep
+
+	genericiooptions.IOStreams
+}
+
+func NewPluginSyncCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &PluginSyncOptions{
+		IOStreams: streams,
+	}
+	cmd := &cobra.Command{
+		Use:     "sync",
+		Short:   "Sync the installed plugins with a plugins manifest",
+		Example: pluginSyncExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.file, "file", "f", "plugins.yaml", "The plugins manifest")
+	cmd.Flags().StringVar(&o.lockfile, "lockfile", "", "The lockfile of the resolved plugin versions and sha256 sums, use '<file>.lock.yaml' by default")
+	cmd.Flags().BoolVar(&o.prune, "prune", false, "Uninstall the installed plugins not listed in the manifest")
+	cmd.Flags().BoolVar(&o.update, "update", false, "Resolve the plugin versions again and ignore the versions in the lockfile")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only print the sync plan, which is resolved from the local copies of the indexes without updating them")
+	return cmd
+}
+
+func (o *PluginSyncOptions) Complete() error {
+	var err error
+	if o.manifest, err = loadPluginSetManifest(o.file); err != nil {
+		return err
+	}
+	if o.lockfile == "" {
+		o.lockfile = strings.TrimSuffix(o.file, filepath.Ext(o.file)) + ".lock.yaml"
+	}
+	if o.update {
+		o.lock = &pluginSetLockfile{}
+		return nil
+	}
+	o.lock, err = loadPluginSetLockfile(o.lockfile)
+	return err
+}
+
+func (o *PluginSyncOptions) Run() error {
+	var err error
+	if o.plan, err = o.buildPlan(); err != nil {
+		return err
+	}
+	printPluginSyncPlan(o.Out, o.plan)
+	if o.dryRun {
+		return nil
+	}
+
+	for _, step := range o.plan {
+		if err = o.applyStep(step); err != nil {
+			return errors.Wrapf(err, "failed to %s %s", step.action, step.name)
+		}
+	}
+	if err = writePluginSetLockfile(o.lockfile, o.newLockfile()); err != nil {
+		return errors.Wrapf(err, "failed to write the lockfile %s", o.lockfile)
+	}
+	fmt.Fprintf(o.Out, "Plugins are synced, the resolved versions are locked in %s\n", o.lockfile)
+	return nil
+}
+
+// buildPlan updates the existing indexes, adds the missing indexes and resolves the plugin versions,
+// the plugins of the indexes to be added are resolved after the indexes are added.
+func (o *PluginSyncOptions) buildPlan() ([]pluginSyncStep, error) {
+	var (
+		plan            []pluginSyncStep
+		existingIndexes []pluginSetIndex
+	)
+	missingIndexes := map[string]bool{}
+	for _, index := range o.manifest.Indexes {
+		dir := paths.IndexPath(index.Name)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			missingIndexes[index.Name] = true
+			plan = append(plan, pluginSyncStep{action: pluginSyncAddIndex, name: index.Name, index: index.URL})
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		url, err := getIndexURL(dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the URL of index %s", index.Name)
+		}
+		if url != index.URL {
+			return nil, errors.Errorf("index %s already exists with URL %s instead of %s, delete it with 'kbcli plugin index delete %s' first",
+				index.Name, url, index.URL, index.Name)
+		}
+		existingIndexes = append(existingIndexes, index)
+	}
+
+	// update the existing indexes and add the missing indexes before resolving the plugins unless it is
+	// a dry run, which resolves the plugins from the local copies of the indexes
+	if !o.dryRun {
+		for _, index := range existingIndexes {
+			if err := fetchIndex(index.URL, paths.IndexPath(index.Name)); err != nil {
+				return nil, errors.Wrapf(err, "failed to update index %s", index.Name)
+			}
+			fmt.Fprintf(o.Out, "Updated the local copy of plugin index %q\n", index.Name)
+		}
+		for _, step := range plan {
+			if err := o.applyStep(step); err != nil {
+				return nil, errors.Wrapf(err, "failed to add index %s", step.name)
+			}
+		}
+		plan = nil
+		missingIndexes = map[string]bool{}
+	}
+
+	installed, err := GetInstalledPluginReceipts(paths.InstallReceiptsPath())
+	if err != nil {
+		return nil, err
+	}
+	receipts := map[string]Receipt{}
+	for _, receipt := range installed {
+		receipts[receipt.Name] = receipt
+	}
+
+	listed := map[string]bool{}
+	for _, item := range o.manifest.Plugins {
+		listed[item.Name] = true
+		receipt, isInstalled := receipts[item.Name]
+		if missingIndexes[item.Index] {
+			step := pluginSyncStep{action: pluginSyncInstall, name: item.Name, index: item.Index, targetVersion: item.Version}
+			if isInstalled {
+				step.action, step.currentVersion = pluginSyncUpgrade, receipt.Spec.Version
+			}
+			plan = append(plan, step)
+			continue
+		}
+
+		plugin, err := o.resolve(item)
+		if err != nil {
+			return nil, err
+		}
+		step := pluginSyncStep{
+			action:        pluginSyncInstall,
+			name:          item.Name,
+			index:         item.Index,
+			targetVersion: plugin.Spec.Version,
+			plugin:        plugin,
+		}
+		if isInstalled {
+			if receipt.Spec.Version == plugin.Spec.Version {
+				continue
+			}
+			step.currentVersion = receipt.Spec.Version
+			step.action = pluginSyncUpgrade
+			if isDowngrade(receipt.Spec.Version, plugin.Spec.Version) {
+				step.action = pluginSyncDowngrade
+			}
+		}
+		plan = append(plan, step)
+	}
+
+	if o.prune {
+		for _, receipt := range installed {
+			if !listed[receipt.Name] {
+				plan = append(plan, pluginSyncStep{
+					action:         pluginSyncUninstall,
+					name:           receipt.Name,
+					index:          receipt.Status.Source.Name,
+					currentVersion: receipt.Spec.Version,
+				})
+			}
+		}
+	}
+	return plan, nil
+}
+
+// resolve finds the plugin version matching the constraint from the index, the locked version
+// is preferred if it still matches and the sha256 sums must be the same as the locked ones.
+func (o *PluginSyncOptions) resolve(item pluginSetItem) (Plugin, error) {
+	versions, err := listPluginVersions(paths, item.Index, item.Name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Plugin{}, errors.Errorf("plugin %q does not exist in the %s plugin index", item.Name, item.Index)
+		}
+		return Plugin{}, errors.Wrapf(err, "failed to load plugin %q from the %s plugin index", item.Name, item.Index)
+	}
+
+	if locked := o.lock.find(item.Name); locked != nil && locked.Index == item.Index {
+		ok, err := matchVersionConstraint(item.Version, locked.Version)
+		if err != nil {
+			return Plugin{}, err
+		}
+		if ok {
+			for _, plugin := range versions {
+				if plugin.Spec.Version == locked.Version {
+					return plugin, verifyLockedPlugin(plugin, locked)
+				}
+			}
+			return Plugin{}, errors.Errorf("the locked version %s of plugin %q does not exist in the %s plugin index, use --update to resolve it again",
+				locked.Version, item.Name, item.Index)
+		}
+		klog.V(1).Infof("The locked version %s of plugin %s does not match %q, resolve it again", locked.Version, item.Name, item.Version)
+	}
+	return resolvePluginVersion(versions, item.Version)
+}
+
+func (o *PluginSyncOptions) applyStep(step pluginSyncStep) error {
+	switch step.action {
+	case pluginSyncAddIndex:
+		if err := AddIndex(paths, step.name, step.index); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Added plugin index %q\n", step.name)
+	case pluginSyncInstall:
+		if err := Install(paths, step.plugin, step.index, InstallOpts{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Installed plugin %q %s\n", step.name, step.targetVersion)
+	case pluginSyncUpgrade, pluginSyncDowngrade:
+		receipt, err := readInstalledReceipt(paths, step.name)
+		if err != nil {
+			return err
+		}
+		if _, ok := findInstalledVersion(receipt, step.targetVersion); !ok {
+			if err = Install(paths, step.plugin, step.index, InstallOpts{}); err != nil {
+				return err
+			}
+			if receipt, err = readInstalledReceipt(paths, step.name); err != nil {
+				return err
+			}
+		} else if err = switchVersion(paths, &receipt, step.targetVersion); err != nil {
+			return err
+		}
+		receipt.Status.Source.Name = step.index
+		receipt.Status.Pinned = ""
+		if err = StoreReceipt(receipt, paths.PluginInstallReceiptPath(step.name)); err != nil {
+			return errors.Wrap(err, "installation receipt could not be stored")
+		}
+		fmt.Fprintf(o.Out, "Switched plugin %q from %s to %s\n", step.name, step.currentVersion, step.targetVersion)
+	case pluginSyncUninstall:
+		if err := uninstall(paths, step.name); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Uninstalled plugin %q\n", step.name)
+	}
+	return nil
+}
+
+// newLockfile records the indexes and the active versions of the plugins in the manifest
+func (o *PluginSyncOptions) newLockfile() *pluginSetLockfile {
+	lock := &pluginSetLockfile{Indexes: o.manifest.Indexes}
+	for _, item := range o.manifest.Plugins {
+		receipt, err := ReadReceiptFromFile(paths.PluginInstallReceiptPath(item.Name))
+		if err != nil {
+			klog.Warningf("failed to read the receipt of plugin %q: %v", item.Name, err)
+			continue
+		}
+		lock.Plugins = append(lock.Plugins, newPluginLockItem(receipt.Plugin, item.Index))
+	}
+	return lock
+}
+
+func newPluginLockItem(plugin Plugin, index string) pluginLockItem {
+	item := pluginLockItem{
+		Name:    plugin.Name,
+		Index:   index,
+		Version: plugin.Spec.Version,
+	}
+	for _, platform := range plugin.Spec.Platforms {
+		item.Platforms = append(item.Platforms, pluginLockPlatform{URI: platform.URI, Sha256: platform.Sha256})
+	}
+	return item
+}
+
+// verifyLockedPlugin checks the archive of this platform is the same as the locked one
+func verifyLockedPlugin(plugin Plugin, locked *pluginLockItem) error {
+	candidate, ok, err := GetMatchingPlatform(plugin.Spec.Platforms)
+	if err != nil {
+		return errors.Wrap(err, "failed trying to find a matching platform in plugin spec")
+	}
+	if !ok {
+		return errors.Errorf("plugin %q does not offer installation for this platform", plugin.Name)
+	}
+	for _, platform := range locked.Platforms {
+		if platform.URI != candidate.URI {
+			continue
+		}
+		if !strings.EqualFold(platform.Sha256, candidate.Sha256) {
+			return errors.Errorf("the sha256 sum of plugin %q %s is %s, which does not match %s in the lockfile",
+				plugin.Name, plugin.Spec.Version, candidate.Sha256, platform.Sha256)
+		}
+		return nil
+	}
+	return errors.Errorf("the archive %s of plugin %q %s is not in the lockfile", candidate.URI, plugin.Name, plugin.Spec.Version)
+}
+
+// resolvePluginVersion returns the highest version matching the constraint,
+// the latest version in the index is returned if there is no constraint.
+func resolvePluginVersion(versions []Plugin, constraint string) (Plugin, error) {
+	if len(versions) == 0 {
+		return Plugin{}, errors.New("no plugin version found")
+	}
+	if constraint == "" {
+		return versions[0], nil
+	}
+	var (
+		res  Plugin
+		resv *semver.Version
+	)
+	for _, plugin := range versions {
+		ok, err := matchVersionConstraint(constraint, plugin.Spec.Version)
+		if err != nil {
+			return Plugin{}, err
+		}
+		if !ok {
+			continue
+		}
+		v, _ := semver.NewVersion(plugin.Spec.Version)
+		if resv == nil || v.GreaterThan(resv) {
+			res, resv = plugin, v
+		}
+	}
+	if resv == nil {
+		return Plugin{}, errors.Errorf("no version of plugin %q matches %q", versions[0].Name, constraint)
+	}
+	return res, nil
+}
+
+func matchVersionConstraint(constraint, version string) (bool, error) {
+	if constraint == "" {
+		return true, nil
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid version constraint %q", constraint)
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false, nil
+	}
+	return c.Check(v), nil
+}
+
+func isDowngrade(current, target string) bool {
+	curv, err := parseVersion(current)
+	if err != nil {
+		return false
+	}
+	targetv, err := parseVersion(target)
+	if err != nil {
+		return false
+	}
+	return targetv.LessThan(curv)
+}
+
+func printPluginSyncPlan(out io.Writer, plan []pluginSyncStep) {
+	if len(plan) == 0 {
+		fmt.Fprintln(out, "Plugins are up to date, nothing to do")
+		return
+	}
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("ACTION", "NAME", "INDEX", "CURRENT VERSION", "TARGET VERSION")
+	for _, step := range plan {
+		tbl.AddRow(step.action, step.name, step.index, step.currentVersion, step.targetVersion)
+	}
+	tbl.Print()
+}
+
+func loadPluginSetManifest(file string) (*pluginSetManifest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &pluginSetManifest{}
+	if err = yaml.UnmarshalStrict(data, manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the plugins manifest %s", file)
+	}
+	for _, index := range manifest.Indexes {
+		if index.Name == "" || index.URL == "" {
+			return nil, errors.Errorf("the name and url of index are required in the plugins manifest %s", file)
+		}
+	}
+	names := map[string]bool{}
+	for i := range manifest.Plugins {
+		item := &manifest.Plugins[i]
+		if item.Name == "" {
+			return nil, errors.Errorf("the name of plugin is required in the plugins manifest %s", file)
+		}
+		if names[item.Name] {
+			return nil, errors.Errorf("plugin %s is duplicated in the plugins manifest %s", item.Name, file)
+		}
+		names[item.Name] = true
+		if item.Index == "" {
+			item.Index = DefaultIndexName
+		}
+		if _, err = matchVersionConstraint(item.Version, "v0.0.0"); err != nil {
+			return nil, errors.Wrapf(err, "plugin %s", item.Name)
+		}
+	}
+	sort.SliceStable(manifest.Plugins, func(i, j int) bool {
+		return manifest.Plugins[i].Name < manifest.Plugins[j].Name
+	})
+	return manifest, nil
+}
+
+func loadPluginSetLockfile(file string) (*pluginSetLockfile, error) {
+	lock := &pluginSetLockfile{}
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(data, lock); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the lockfile %s", file)
+	}
+	return lock, nil
+}
+
+func writePluginSetLockfile(file string, lock *pluginSetLockfile) error {
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+func (l *pluginSetLockfile) find(name string) *pluginLockItem {
+	for i := range l.Plugins {
+		if l.Plugins[i].Name == name {
+			return &l.Plugins[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestResolvePluginVersion(t *testing.T) {
+	versions := []Plugin{
+		testPluginManifest("foo", "v1.2.0"),
+		testPluginManifest("foo", "v0.9.0"),
+		testPluginManifest("foo", "v1.1.0"),
+		testPluginManifest("foo", "v1.0.0"),
+	}
+	tests := []struct {
+		constraint string
+		expected   string
+		expectErr  bool
+	}{
+		{constraint: "", expected: "v1.2.0"},
+		{constraint: "v1.0.0", expected: "v1.0.0"},
+		{constraint: ">= v1.0.0, < v1.2.0", expected: "v1.1.0"},
+		{constraint: "~0.9", expected: "v0.9.0"},
+		{constraint: ">= v2.0.0", expectErr: true},
+		{constraint: "not a constraint", expectErr: true},
+	}
+	for _, tt := range tests {
+		plugin, err := resolvePluginVersion(versions, tt.constraint)
+		if tt.expectErr {
+			if err == nil {
+				t.Errorf("expected error for constraint %q", tt.constraint)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if plugin.Spec.Version != tt.expected {
+			t.Errorf("constraint %q resolved to %s, want %s", tt.constraint, plugin.Spec.Version, tt.expected)
+		}
+	}
+}
+
+func TestVerifyLockedPlugin(t *testing.T) {
+	plugin := testPluginManifest("foo", "v1.0.0")
+	locked := newPluginLockItem(plugin, DefaultIndexName)
+	if err := verifyLockedPlugin(plugin, &locked); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	changed := testPluginManifest("foo", "v1.0.0")
+	changed.Spec.Platforms[0].Sha256 = "cafebabe"
+	if err := verifyLockedPlugin(changed, &locked); err == nil {
+		t.Error("expected error for a changed sha256 sum")
+	}
+	changed.Spec.Platforms[0].URI = "https://example.com/other.tar.gz"
+	if err := verifyLockedPlugin(changed, &locked); err == nil {
+		t.Error("expected error for an archive not in the lockfile")
+	}
+}
+
+func TestLoadPluginSetManifest(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		file := filepath.Join(dir, "plugins.yaml")
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return file
+	}
+
+	manifest, err := loadPluginSetManifest(write(`
+plugins:
+- name: foo
+  version: ">= v1.0.0"
+- name: bar
+  index: krew
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(manifest.Plugins) != 2 || manifest.Plugins[0].Name != "bar" || manifest.Plugins[1].Index != DefaultIndexName {
+		t.Errorf("unexpected plugins: %+v", manifest.Plugins)
+	}
+
+	for _, content := range []string{
+		"plugins:\n- name: foo\n- name: foo\n",
+		"plugins:\n- version: v1.0.0\n",
+		"plugins:\n- name: foo\n  version: abc\n",
+		"indexes:\n- name: krew\n",
+		"plugins:\n- name: foo\n  unknown: true\n",
+	} {
+		if _, err = loadPluginSetManifest(write(content)); err == nil {
+			t.Errorf("expected error for manifest %q", content)
+		}
+	}
+}
+
+func TestPluginSyncPlan(t *testing.T) {
+	oldPaths := paths
+	defer func() { paths = oldPaths }()
+	paths = NewPaths(t.TempDir())
+	if err := EnsureDirs(paths.InstallReceiptsPath()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	newTestIndex(t, paths, "foo", "v1.0.0", "v1.1.0", "v2.0.0")
+	newTestIndex(t, paths, "bar", "v0.1.0")
+
+	// foo is installed at v2.0.0 and baz is not listed in the manifest
+	for _, plugin := range []Plugin{testPluginManifest("foo", "v2.0.0"), testPluginManifest("baz", "v0.1.0")} {
+		if err := StoreReceipt(NewReceipt(plugin, DefaultIndexName, metav1.Now()), paths.PluginInstallReceiptPath(plugin.Name)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	o := &PluginSyncOptions{
+		prune: true,
+		manifest: &pluginSetManifest{
+			Plugins: []pluginSetItem{
+				{Name: "bar", Index: DefaultIndexName},
+				{Name: "foo", Index: DefaultIndexName, Version: "~1"},
+			},
+		},
+		lock: &pluginSetLockfile{},
+	}
+	plan, err := o.buildPlan()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []pluginSyncStep{
+		{action: pluginSyncInstall, name: "bar", targetVersion: "v0.1.0"},
+		{action: pluginSyncDowngrade, name: "foo", currentVersion: "v2.0.0", targetVersion: "v1.1.0"},
+		{action: pluginSyncUninstall, name: "baz", currentVersion: "v0.1.0"},
+	}
+	if len(plan) != len(expected) {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	for i, step := range plan {
+		e := expected[i]
+		if step.action != e.action || step.name != e.name || step.currentVersion != e.currentVersion || step.targetVersion != e.targetVersion {
+			t.Errorf("unexpected step %d: %+v, want %+v", i, step, e)
+		}
+	}
+
+	// the locked version is preferred if it matches the constraint
+	o.prune = false
+	o.lock.Plugins = []pluginLockItem{newPluginLockItem(testPluginManifest("foo", "v1.0.0"), DefaultIndexName)}
+	if plan, err = o.buildPlan(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan) != 2 || plan[1].targetVersion != "v1.0.0" {
+		t.Errorf("unexpected plan with lockfile: %+v", plan)
+	}
+
+	out := &bytes.Buffer{}
+	printPluginSyncPlan(out, plan)
+	if !bytes.Contains(out.Bytes(), []byte("downgrade")) {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestPluginSyncUpdateIndex(t *testing.T) {
+	oldPaths := paths
+	defer func() { paths = oldPaths }()
+	paths = NewPaths(t.TempDir())
+	if err := EnsureDirs(paths.InstallReceiptsPath()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "plugins"), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	writeManifest := func(version string) {
+		b, err := yaml.Marshal(testPluginManifest("foo", version))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err = os.WriteFile(filepath.Join(src, "plugins", "foo.yaml"), b, 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	writeManifest("v1.0.0")
+	url := "file://" + src
+	if err := AddIndex(paths, "local", url); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	writeManifest("v1.1.0")
+
+	out := &bytes.Buffer{}
+	o := &PluginSyncOptions{
+		dryRun: true,
+		manifest: &pluginSetManifest{
+			Indexes: []pluginSetIndex{{Name: "local", URL: url}},
+			Plugins: []pluginSetItem{{Name: "foo", Index: "local"}},
+		},
+		lock: &pluginSetLockfile{},
+	}
+	o.Out = out
+
+	// the dry run resolves the plugins from the local copy of the index
+	plan, err := o.buildPlan()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan) != 1 || plan[0].targetVersion != "v1.0.0" {
+		t.Errorf("unexpected plan of the dry run: %+v", plan)
+	}
+
+	// the index is updated before resolving the plugins
+	o.dryRun = false
+	if plan, err = o.buildPlan(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan) != 1 || plan[0].targetVersion != "v1.1.0" {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+	if !bytes.Contains(out.Bytes(), []byte(`Updated the local copy of plugin index "local"`)) {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
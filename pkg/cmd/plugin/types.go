@@ -36,6 +36,8 @@ const (
 	KrewIndexName     = "krew"
 	ManifestExtension = ".yaml"
 	PluginKind        = "Plugin"
+	// ArchiveIndexName is the source index name of the plugins installed from local archives
+	ArchiveIndexName = "archive"
 )
 
 var SupportAPIVersion = []string{
@@ -152,6 +154,12 @@ type Receipt struct {
 // ReceiptStatus contains information about the installed plugin.
 type ReceiptStatus struct {
 	Source SourceIndex `json:"source"`
+	// Pinned is the version the plugin is pinned to, a pinned plugin is skipped by upgrade.
+	Pinned string `json:"pinned,omitempty"`
+	// Previous is the previously active version, used by rollback.
+	Previous string `json:"previous,omitempty"`
+	// Versions contains the manifests of all installed versions.
+	Versions []Plugin `json:"versions,omitempty"`
 }
 
 // SourceIndex contains information about the index a plugin was installed from.
@@ -162,6 +170,8 @@ type SourceIndex struct {
 
 type InstallOpts struct {
 	ArchiveFileOverride string
+	// Pin pins the plugin to the installed version
+	Pin bool
 }
 
 type installOperation struct {
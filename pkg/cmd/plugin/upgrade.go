@@ -21,7 +21,6 @@ package plugin
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -40,12 +39,16 @@ var (
 
 	# upgrade installed plugin to a newer version
 	kbcli plugin upgrade --all
+
+	# upgrade a pinned plugin and remove the pin
+	kbcli plugin upgrade myplugin --unpin
 	`)
 )
 
 type UpgradeOptions struct {
 	//	common user flags
-	all bool
+	all   bool
+	unpin bool
 
 	pluginNames []string
 	genericiooptions.IOStreams
@@ -67,6 +70,7 @@ func NewPluginUpgradeCmd(streams genericiooptions.IOStreams) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&o.all, "all", o.all, "Upgrade all installed plugins")
+	cmd.Flags().BoolVar(&o.unpin, "unpin", o.unpin, "Upgrade the pinned plugins and remove the pins")
 
 	return cmd
 }
@@ -99,6 +103,19 @@ func (o *UpgradeOptions) Complete(args []string) error {
 func (o *UpgradeOptions) Run() error {
 	for _, name := range o.pluginNames {
 		indexName, pluginName := CanonicalPluginName(name)
+		if indexName == ArchiveIndexName {
+			fmt.Fprintf(o.Out, "Plugin %q is installed from an archive, skip upgrading it\n", pluginName)
+			continue
+		}
+
+		receipt, err := ReadReceiptFromFile(paths.PluginInstallReceiptPath(pluginName))
+		if err != nil {
+			return err
+		}
+		if receipt.Status.Pinned != "" && !o.unpin {
+			fmt.Fprintf(o.Out, "Plugin %q is pinned to %s, skip upgrading it\n", name, receipt.Status.Pinned)
+			continue
+		}
 
 		plugin, err := LoadPluginByName(paths.IndexPluginsPath(indexName), pluginName)
 		if err != nil {
@@ -117,8 +134,9 @@ func (o *UpgradeOptions) Run() error {
 	return nil
 }
 
-// Upgrade reinstalls and deletes the old plugin. The operation tries
-// to keep dir in a healthy state if it fails during the process.
+// Upgrade installs the new version side by side with the old ones, makes it the active
+// version and removes the pin. The operation tries to keep dir in a healthy state if it
+// fails during the process.
 func Upgrade(p *Paths, plugin Plugin, indexName string) error {
 	installReceipt, err := ReadReceiptFromFile(p.PluginInstallReceiptPath(plugin.Name))
 	if err != nil {
@@ -155,32 +173,32 @@ func Upgrade(p *Paths, plugin Plugin, indexName string) error {
 	}
 	klog.V(1).Infof("Plugin needs upgrade (%s < %s)", curv, newv)
 
-	// Re-Install
-	klog.V(1).Infof("Installing new version %s", newVersion)
-	if err := install(installOperation{
-		pluginName: plugin.Name,
-		platform:   candidate,
-
-		installDir: p.PluginVersionInstallPath(plugin.Name, newVersion),
-		binDir:     p.BinPath(),
-	}, InstallOpts{}); err != nil {
-		return errors.Wrap(err, "failed to install new version")
+	// Re-Install if the new version is not installed yet
+	if _, ok := findInstalledVersion(installReceipt, newVersion); ok {
+		klog.V(1).Infof("Switching to installed version %s", newVersion)
+		if err = switchVersion(p, &installReceipt, newVersion); err != nil {
+			return err
+		}
+	} else {
+		klog.V(1).Infof("Installing new version %s", newVersion)
+		if err := install(installOperation{
+			pluginName: plugin.Name,
+			platform:   candidate,
+
+			installDir: p.PluginVersionInstallPath(plugin.Name, newVersion),
+			binDir:     p.BinPath(),
+		}, InstallOpts{}); err != nil {
+			return errors.Wrap(err, "failed to install new version")
+		}
+		installReceipt.addVersion(plugin)
+		installReceipt.setActive(plugin)
 	}
 
 	klog.V(2).Infof("Upgrading install receipt for plugin %s", plugin.Name)
-	if err = StoreReceipt(NewReceipt(plugin, indexName, installReceipt.CreationTimestamp), p.PluginInstallReceiptPath(plugin.Name)); err != nil {
-		return errors.Wrap(err, "installation receipt could not be stored, uninstall may fail")
-	}
-
-	// Clean old installations
-	klog.V(2).Infof("Starting old version cleanup")
-	return cleanupInstallation(p, plugin, curVersion)
-}
-
-// cleanupInstallation removes a plugin directly
-func cleanupInstallation(p *Paths, plugin Plugin, oldVersion string) error {
-	klog.V(1).Infof("Remove old plugin installation under %q", p.PluginVersionInstallPath(plugin.Name, oldVersion))
-	return os.RemoveAll(p.PluginVersionInstallPath(plugin.Name, oldVersion))
+	installReceipt.Status.Source.Name = indexName
+	installReceipt.Status.Pinned = ""
+	err = StoreReceipt(installReceipt, p.PluginInstallReceiptPath(plugin.Name))
+	return errors.Wrap(err, "installation receipt could not be stored, uninstall may fail")
 }
 
 func parseVersion(s string) (*k8sver.Version, error) {
@@ -0,0 +1,87 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	pluginUseExample = templates.Examples(`
+	# switch to an installed version of a plugin and pin it
+	kbcli plugin use [PLUGIN]@[VERSION]
+	`)
+)
+
+func NewPluginUseCmd(streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "use NAME@VERSION",
+		Short:   "Switch to an installed version of a plugin",
+		Example: pluginUseExample,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(usePluginVersion(streams.Out, paths, args[0]))
+		},
+	}
+	return cmd
+}
+
+func usePluginVersion(out io.Writer, p *Paths, name string) error {
+	pluginName, version := ParsePluginVersion(name)
+	if version == "" {
+		return errors.Errorf("no version specified, use %s@VERSION", pluginName)
+	}
+	receipt, err := readInstalledReceipt(p, pluginName)
+	if err != nil {
+		return err
+	}
+	if _, ok := findInstalledVersion(receipt, version); !ok {
+		return errors.Errorf("version %s of plugin %q is not installed, install it with 'kbcli plugin install %s@%s'",
+			version, pluginName, pluginName, version)
+	}
+	if err = switchVersion(p, &receipt, version); err != nil {
+		return err
+	}
+	receipt.Status.Pinned = version
+	if err = StoreReceipt(receipt, p.PluginInstallReceiptPath(pluginName)); err != nil {
+		return errors.Wrap(err, "installation receipt could not be stored")
+	}
+	fmt.Fprintf(out, "Plugin %q is switched to %s and pinned\n", pluginName, version)
+	return nil
+}
+
+func readInstalledReceipt(p *Paths, name string) (Receipt, error) {
+	receipt, err := ReadReceiptFromFile(p.PluginInstallReceiptPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return receipt, ErrIsNotInstalled
+		}
+		return receipt, errors.Wrapf(err, "failed to look up install receipt for plugin %q", name)
+	}
+	return receipt, nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+// ParsePluginVersion splits the NAME@VERSION form into plugin name and version,
+// the version is empty if not specified and always starts with 'v' otherwise.
+func ParsePluginVersion(in string) (string, string) {
+	name, version, found := strings.Cut(in, "@")
+	if !found || version == "" {
+		return name, ""
+	}
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return name, version
+}
+
+// installedVersions returns the manifests of all installed versions of the plugin,
+// receipts stored before side-by-side installation only contain the active version.
+func installedVersions(receipt Receipt) []Plugin {
+	if len(receipt.Status.Versions) > 0 || receipt.Name == "" {
+		return receipt.Status.Versions
+	}
+	plugin := receipt.Plugin
+	plugin.CreationTimestamp = receipt.CreationTimestamp
+	return []Plugin{plugin}
+}
+
+// findInstalledVersion returns the manifest of the installed plugin version.
+func findInstalledVersion(receipt Receipt, version string) (Plugin, bool) {
+	for _, plugin := range installedVersions(receipt) {
+		if plugin.Spec.Version == version {
+			return plugin, true
+		}
+	}
+	return Plugin{}, false
+}
+
+// addVersion records the plugin version in the receipt, an existing
+// entry of the same version is replaced.
+func (r *Receipt) addVersion(plugin Plugin) {
+	versions := installedVersions(*r)
+	res := make([]Plugin, 0, len(versions)+1)
+	for _, v := range versions {
+		if v.Spec.Version != plugin.Spec.Version {
+			res = append(res, v)
+		}
+	}
+	r.Status.Versions = append(res, plugin)
+}
+
+// setActive marks the plugin version as the active one and remembers
+// the currently active version for rollback.
+func (r *Receipt) setActive(plugin Plugin) {
+	if r.Spec.Version != "" && r.Spec.Version != plugin.Spec.Version {
+		r.Status.Previous = r.Spec.Version
+	}
+	ts := r.CreationTimestamp
+	r.Plugin = plugin
+	r.CreationTimestamp = ts
+}
+
+// switchVersion links the binary of an installed plugin version and makes it the active one.
+func switchVersion(p *Paths, receipt *Receipt, version string) error {
+	plugin, ok := findInstalledVersion(*receipt, version)
+	if !ok {
+		return errors.Errorf("version %s of plugin %q is not installed", version, receipt.Name)
+	}
+	candidate, ok, err := GetMatchingPlatform(plugin.Spec.Platforms)
+	if err != nil {
+		return errors.Wrap(err, "failed trying to find a matching platform in plugin spec")
+	}
+	if !ok {
+		return errors.Errorf("plugin %q does not offer installation for this platform", plugin.Name)
+	}
+	applyDefaults(&candidate)
+	fullPath := filepath.Join(p.PluginVersionInstallPath(plugin.Name, version), filepath.FromSlash(candidate.Bin))
+	if err = createOrUpdateLink(p.BinPath(), fullPath, plugin.Name); err != nil {
+		return errors.Wrap(err, "failed to link plugin")
+	}
+	receipt.addVersion(plugin)
+	receipt.setActive(plugin)
+	return nil
+}
+
+// LoadPluginVersion loads the manifest of the specified plugin version from the index,
+// the git history of the index is searched if the version is not the latest one.
+func LoadPluginVersion(p *Paths, indexName, pluginName, version string) (Plugin, error) {
+	plugin, err := LoadPluginByName(p.IndexPluginsPath(indexName), pluginName)
+	if err != nil {
+		return plugin, err
+	}
+	if plugin.Spec.Version == version {
+		return plugin, nil
+	}
+	versions, err := listPluginVersions(p, indexName, pluginName)
+	if err != nil {
+		return plugin, err
+	}
+	for _, v := range versions {
+		if v.Spec.Version == version {
+			return v, nil
+		}
+	}
+	return plugin, errors.Errorf("version %s of plugin %q does not exist in the %s plugin index, the latest version is %s",
+		version, pluginName, indexName, plugin.Spec.Version)
+}
+
+// listPluginVersions returns the manifests of all versions of the plugin in the index,
+// the latest one comes first and the others are read from the git history of the git index.
+func listPluginVersions(p *Paths, indexName, pluginName string) ([]Plugin, error) {
+	latest, err := LoadPluginByName(p.IndexPluginsPath(indexName), pluginName)
+	if err != nil {
+		return nil, err
+	}
+	versions := []Plugin{latest}
+	found := map[string]bool{latest.Spec.Version: true}
+
+	// only the latest version is available in the index fetched from a directory, archive or OCI artifact
+	indexPath := p.IndexPath(indexName)
+	if !isGitIndex(indexPath) {
+		return versions, nil
+	}
+	for _, dir := range p.IndexPluginsPath(indexName) {
+		rel, err := filepath.Rel(indexPath, filepath.Join(dir, pluginName+ManifestExtension))
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+		commits, err := util.ExecGitCommand(indexPath, "log", "--format=%H", "--", rel)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the history of the %s plugin index", indexName)
+		}
+		for _, commit := range strings.Fields(commits) {
+			content, err := util.ExecGitCommand(indexPath, "show", commit+":"+rel)
+			if err != nil {
+				// the manifest is deleted in this commit
+				continue
+			}
+			var plugin Plugin
+			if err = yaml.Unmarshal([]byte(content), &plugin); err != nil || found[plugin.Spec.Version] {
+				continue
+			}
+			if err = ValidatePlugin(pluginName, plugin); err != nil {
+				klog.V(2).Infof("Skip invalid manifest of plugin %s in commit %s: %v", pluginName, commit, err)
+				continue
+			}
+			found[plugin.Spec.Version] = true
+			versions = append(versions, plugin)
+		}
+	}
+	return versions, nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/util"
+)
+
+func testPluginManifest(name, version string) Plugin {
+	return Plugin{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SupportAPIVersion[0],
+			Kind:       PluginKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: PluginSpec{
+			Version:          version,
+			ShortDescription: "test plugin",
+			Platforms: []Platform{
+				{
+					URI:      "https://example.com/" + name + "-" + version + ".tar.gz",
+					Sha256:   "deadbeef",
+					Bin:      "kbcli-" + name,
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"os": runtime.GOOS}},
+				},
+			},
+		},
+	}
+}
+
+func TestParsePluginVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		name    string
+		version string
+	}{
+		{in: "foo", name: "foo"},
+		{in: "foo@", name: "foo"},
+		{in: "foo@v1.0.0", name: "foo", version: "v1.0.0"},
+		{in: "foo@1.0.0", name: "foo", version: "v1.0.0"},
+		{in: "krew/foo@v0.1.0", name: "krew/foo", version: "v0.1.0"},
+	}
+	for _, tt := range tests {
+		name, version := ParsePluginVersion(tt.in)
+		if name != tt.name || version != tt.version {
+			t.Errorf("ParsePluginVersion(%q) = %q, %q, want %q, %q", tt.in, name, version, tt.name, tt.version)
+		}
+	}
+}
+
+func TestSwitchAndRollbackPluginVersion(t *testing.T) {
+	p := NewPaths(t.TempDir())
+	if err := EnsureDirs(p.BinPath(), p.InstallReceiptsPath()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, version := range []string{"v1.0.0", "v2.0.0"} {
+		dir := p.PluginVersionInstallPath("foo", version)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "kbcli-foo"), []byte(version), 0755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	linkTarget := func() string {
+		target, err := os.Readlink(filepath.Join(p.BinPath(), pluginNameToBin("foo", false)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return target
+	}
+
+	// a receipt stored before side-by-side installation only has the active version
+	receipt := NewReceipt(testPluginManifest("foo", "v1.0.0"), DefaultIndexName, metav1.Now())
+	if versions := installedVersions(receipt); len(versions) != 1 || versions[0].Spec.Version != "v1.0.0" {
+		t.Fatalf("unexpected installed versions: %v", versions)
+	}
+	receipt.addVersion(testPluginManifest("foo", "v2.0.0"))
+	if err := switchVersion(p, &receipt, "v2.0.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if receipt.Spec.Version != "v2.0.0" || receipt.Status.Previous != "v1.0.0" || len(receipt.Status.Versions) != 2 {
+		t.Fatalf("unexpected receipt status after switch: %s %+v", receipt.Spec.Version, receipt.Status)
+	}
+	if target := linkTarget(); target != filepath.Join(p.PluginVersionInstallPath("foo", "v2.0.0"), "kbcli-foo") {
+		t.Fatalf("unexpected link target %q", target)
+	}
+	if err := switchVersion(p, &receipt, "v3.0.0"); err == nil {
+		t.Fatal("expected error when switching to a version not installed")
+	}
+	if err := StoreReceipt(receipt, p.PluginInstallReceiptPath("foo")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	if err := rollbackPlugin(out, p, "foo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	receipt, err := ReadReceiptFromFile(p.PluginInstallReceiptPath("foo"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if receipt.Spec.Version != "v1.0.0" || receipt.Status.Previous != "v2.0.0" || receipt.Status.Pinned != "v1.0.0" {
+		t.Fatalf("unexpected receipt status after rollback: %s %+v", receipt.Spec.Version, receipt.Status)
+	}
+	if target := linkTarget(); target != filepath.Join(p.PluginVersionInstallPath("foo", "v1.0.0"), "kbcli-foo") {
+		t.Fatalf("unexpected link target %q", target)
+	}
+
+	if err = usePluginVersion(out, p, "foo@2.0.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	receipt, _ = ReadReceiptFromFile(p.PluginInstallReceiptPath("foo"))
+	if receipt.Spec.Version != "v2.0.0" || receipt.Status.Pinned != "v2.0.0" {
+		t.Fatalf("unexpected receipt status after use: %s %+v", receipt.Spec.Version, receipt.Status)
+	}
+	if err = usePluginVersion(out, p, "bar@v1.0.0"); err != ErrIsNotInstalled {
+		t.Fatalf("expected ErrIsNotInstalled, got %v", err)
+	}
+}
+
+// newTestIndex creates a git plugin index, in which each version of the plugin is a commit
+func newTestIndex(t *testing.T, p *Paths, name string, versions ...string) {
+	indexPath := p.IndexPath(DefaultIndexName)
+	pluginsPath := filepath.Join(indexPath, "plugins")
+	if err := os.MkdirAll(pluginsPath, 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	git := func(args ...string) {
+		if _, err := util.ExecGitCommand(indexPath, args...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	git("init")
+	for _, version := range versions {
+		b, err := yaml.Marshal(testPluginManifest(name, version))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err = os.WriteFile(filepath.Join(pluginsPath, name+ManifestExtension), b, 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		git("add", "-A")
+		git("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-m", version)
+	}
+}
+
+func TestLoadPluginVersion(t *testing.T) {
+	p := NewPaths(t.TempDir())
+	newTestIndex(t, p, "foo", "v1.0.0", "v1.1.0")
+
+	for _, version := range []string{"v1.0.0", "v1.1.0"} {
+		plugin, err := LoadPluginVersion(p, DefaultIndexName, "foo", version)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if plugin.Spec.Version != version {
+			t.Errorf("expected version %s, got %s", version, plugin.Spec.Version)
+		}
+	}
+	if _, err := LoadPluginVersion(p, DefaultIndexName, "foo", "v2.0.0"); err == nil {
+		t.Error("expected error for a version not in the index")
+	}
+}
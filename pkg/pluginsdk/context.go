@@ -0,0 +1,231 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package pluginsdk helps kbcli plugins to read the runtime context passed by kbcli
+// and to print results consistently with the kbcli builtin commands.
+//
+// When a plugin is invoked by kbcli, the context is passed through the environment
+// variables below, and the whole context is also written to the JSON file specified
+// by KBCLI_PLUGIN_CONTEXT_FILE, which is removed once it is loaded:
+//
+//	KBCLI_PLUGIN_CONTEXT_VERSION      the version of the context contract, "v1"
+//	KBCLI_PLUGIN_KBCLI_VERSION        the version of kbcli
+//	KBCLI_PLUGIN_KUBECONFIG           the kubeconfig files, separated by the path list separator like KUBECONFIG
+//	KBCLI_PLUGIN_KUBE_CONTEXT         the kubeconfig context
+//	KBCLI_PLUGIN_NAMESPACE            the namespace
+//	KBCLI_PLUGIN_KUBEBLOCKS_NAMESPACE the namespace of KubeBlocks, only resolved for the cluster plugins
+//	KBCLI_PLUGIN_KUBEBLOCKS_VERSION   the version of KubeBlocks, only resolved for the cluster plugins
+//	KBCLI_PLUGIN_OUTPUT               the output format, one of table, wide, json and yaml
+//	KBCLI_PLUGIN_CLUSTER              the cluster name when invoked as 'kbcli cluster <plugin> NAME'
+package pluginsdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+)
+
+const (
+	// ContextVersion is the version of the context contract
+	ContextVersion = "v1"
+
+	EnvContextFile         = "KBCLI_PLUGIN_CONTEXT_FILE"
+	EnvContextVersion      = "KBCLI_PLUGIN_CONTEXT_VERSION"
+	EnvKbcliVersion        = "KBCLI_PLUGIN_KBCLI_VERSION"
+	EnvKubeconfig          = "KBCLI_PLUGIN_KUBECONFIG"
+	EnvKubeContext         = "KBCLI_PLUGIN_KUBE_CONTEXT"
+	EnvNamespace           = "KBCLI_PLUGIN_NAMESPACE"
+	EnvKubeBlocksNamespace = "KBCLI_PLUGIN_KUBEBLOCKS_NAMESPACE"
+	EnvKubeBlocksVersion   = "KBCLI_PLUGIN_KUBEBLOCKS_VERSION"
+	EnvOutput              = "KBCLI_PLUGIN_OUTPUT"
+	EnvCluster             = "KBCLI_PLUGIN_CLUSTER"
+)
+
+// ErrNotInvokedByKbcli is returned by Load if the plugin is not invoked by kbcli
+var ErrNotInvokedByKbcli = errors.New("the plugin is not invoked by kbcli")
+
+// Context is the runtime context passed by kbcli to plugins
+type Context struct {
+	Version             string `json:"version"`
+	KbcliVersion        string `json:"kbcliVersion,omitempty"`
+	Kubeconfig          string `json:"kubeconfig,omitempty"`
+	KubeContext         string `json:"kubeContext,omitempty"`
+	Namespace           string `json:"namespace,omitempty"`
+	KubeBlocksNamespace string `json:"kubeBlocksNamespace,omitempty"`
+	KubeBlocksVersion   string `json:"kubeBlocksVersion,omitempty"`
+	Output              string `json:"output,omitempty"`
+	Cluster             string `json:"cluster,omitempty"`
+}
+
+// Load reads the context from the context file and removes it, or from the environment
+// variables if the context file is not available.
+func Load() (*Context, error) {
+	if file := os.Getenv(EnvContextFile); file != "" {
+		if ctx, err := ReadFile(file); err == nil {
+			_ = os.Remove(file)
+			return ctx, nil
+		}
+	}
+	return FromEnv(os.Getenv)
+}
+
+// ReadFile reads the context from the JSON context file
+func ReadFile(file string) (*Context, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	ctx := &Context{}
+	if err = json.Unmarshal(data, ctx); err != nil {
+		return nil, fmt.Errorf("failed to parse the plugin context file %s: %w", file, err)
+	}
+	return ctx, nil
+}
+
+// FromEnv reads the context from the environment variables
+func FromEnv(getenv func(string) string) (*Context, error) {
+	ctx := &Context{Version: getenv(EnvContextVersion)}
+	if ctx.Version == "" {
+		return nil, ErrNotInvokedByKbcli
+	}
+	for _, kv := range ctx.envs() {
+		*kv.value = getenv(kv.key)
+	}
+	return ctx, nil
+}
+
+type envValue struct {
+	key   string
+	value *string
+}
+
+func (c *Context) envs() []envValue {
+	return []envValue{
+		{EnvContextVersion, &c.Version},
+		{EnvKbcliVersion, &c.KbcliVersion},
+		{EnvKubeconfig, &c.Kubeconfig},
+		{EnvKubeContext, &c.KubeContext},
+		{EnvNamespace, &c.Namespace},
+		{EnvKubeBlocksNamespace, &c.KubeBlocksNamespace},
+		{EnvKubeBlocksVersion, &c.KubeBlocksVersion},
+		{EnvOutput, &c.Output},
+		{EnvCluster, &c.Cluster},
+	}
+}
+
+// Environ returns the context as environment variables in the form "key=value"
+func (c *Context) Environ() []string {
+	var res []string
+	for _, kv := range c.envs() {
+		res = append(res, kv.key+"="+*kv.value)
+	}
+	return res
+}
+
+// WriteFile writes the context to the JSON context file
+func (c *Context) WriteFile(file string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0600)
+}
+
+// WriteTempFile writes the context to a new context file in the temp directory and returns the file name,
+// the file is created exclusively with a random name and is only accessible by the current user.
+func (c *Context) WriteTempFile() (string, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", "kbcli-plugin-context-*.json")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// ConfigFlags returns the kubernetes config flags of the context, which can be used
+// to create the kubernetes clients the same way as kbcli does. The kubeconfig flag only
+// accepts a single file, a list of kubeconfig files is loaded from the KUBECONFIG
+// environment variable inherited from kbcli, where the list is resolved from.
+func (c *Context) ConfigFlags() *genericclioptions.ConfigFlags {
+	flags := genericclioptions.NewConfigFlags(true)
+	if c.Kubeconfig != "" && len(filepath.SplitList(c.Kubeconfig)) == 1 {
+		flags.KubeConfig = &c.Kubeconfig
+	}
+	if c.KubeContext != "" {
+		flags.Context = &c.KubeContext
+	}
+	if c.Namespace != "" {
+		flags.Namespace = &c.Namespace
+	}
+	return flags
+}
+
+// OutputFormat returns the output format of the context, table is used by default
+func (c *Context) OutputFormat() printer.Format {
+	if format, err := printer.ParseFormat(c.Output); err == nil {
+		return format
+	}
+	return printer.Table
+}
+
+// Print prints the object in the output format of the context, the object is printed
+// as a table by the tableFn if the format is human-readable, or as JSON or YAML otherwise.
+func (c *Context) Print(out io.Writer, obj interface{}, tableFn func(*printer.TablePrinter)) error {
+	format := c.OutputFormat()
+	switch {
+	case format.IsHumanReadable():
+		tbl := printer.NewTablePrinter(out)
+		tableFn(tbl)
+		tbl.Print()
+		return nil
+	case format == printer.JSON:
+		data, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	default:
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(out, string(data))
+		return err
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package pluginsdk
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+)
+
+func testContext() *Context {
+	return &Context{
+		Version:             ContextVersion,
+		KbcliVersion:        "1.0.0",
+		Kubeconfig:          "/root/.kube/config",
+		KubeContext:         "kind-kind",
+		Namespace:           "demo",
+		KubeBlocksNamespace: "kb-system",
+		KubeBlocksVersion:   "1.0.0",
+		Output:              "json",
+		Cluster:             "mycluster",
+	}
+}
+
+func TestContextEnv(t *testing.T) {
+	if _, err := FromEnv(func(string) string { return "" }); err != ErrNotInvokedByKbcli {
+		t.Fatalf("expected ErrNotInvokedByKbcli, got %v", err)
+	}
+
+	ctx := testContext()
+	envs := map[string]string{}
+	for _, env := range ctx.Environ() {
+		kv := strings.SplitN(env, "=", 2)
+		envs[kv[0]] = kv[1]
+	}
+	if envs[EnvCluster] != "mycluster" || envs[EnvKubeContext] != "kind-kind" {
+		t.Errorf("unexpected environment variables: %v", envs)
+	}
+	loaded, err := FromEnv(func(key string) string { return envs[key] })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ctx, loaded) {
+		t.Errorf("expected %+v, got %+v", ctx, loaded)
+	}
+}
+
+func TestContextFile(t *testing.T) {
+	ctx := testContext()
+	file := filepath.Join(t.TempDir(), "context.json")
+	if err := ctx.WriteFile(file); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Setenv(EnvContextFile, file)
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ctx, loaded) {
+		t.Errorf("expected %+v, got %+v", ctx, loaded)
+	}
+	if _, err = os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("expected the context file to be removed after loaded, got %v", err)
+	}
+	flags := loaded.ConfigFlags()
+	if *flags.KubeConfig != ctx.Kubeconfig || *flags.Context != ctx.KubeContext || *flags.Namespace != ctx.Namespace {
+		t.Errorf("unexpected config flags")
+	}
+
+	// a list of kubeconfig files can not be passed by the kubeconfig flag
+	loaded.Kubeconfig = strings.Join([]string{"/root/.kube/config", "/root/.kube/other"}, string(filepath.ListSeparator))
+	if flags = loaded.ConfigFlags(); flags.KubeConfig != nil && *flags.KubeConfig != "" {
+		t.Errorf("unexpected kubeconfig flag %q", *flags.KubeConfig)
+	}
+}
+
+func TestContextTempFile(t *testing.T) {
+	ctx := testContext()
+	file, err := ctx.WriteTempFile()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(file)
+	other, err := ctx.WriteTempFile()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(other)
+	if file == other {
+		t.Errorf("expected different context files, got %s", file)
+	}
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(file)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fi.Mode().Perm() != 0600 {
+			t.Errorf("expected the context file to be only accessible by the current user, got %v", fi.Mode().Perm())
+		}
+	}
+	t.Setenv(EnvContextFile, file)
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ctx, loaded) {
+		t.Errorf("expected %+v, got %+v", ctx, loaded)
+	}
+}
+
+func TestContextPrint(t *testing.T) {
+	obj := map[string]string{"name": "mycluster"}
+	tableFn := func(tbl *printer.TablePrinter) {
+		tbl.SetHeader("NAME")
+		tbl.AddRow("mycluster")
+	}
+	tests := []struct {
+		output   string
+		format   printer.Format
+		expected string
+	}{
+		{output: "", format: printer.Table, expected: "NAME"},
+		{output: "invalid", format: printer.Table, expected: "NAME"},
+		{output: "json", format: printer.JSON, expected: `"name": "mycluster"`},
+		{output: "yaml", format: printer.YAML, expected: "name: mycluster"},
+	}
+	for _, tt := range tests {
+		ctx := &Context{Version: ContextVersion, Output: tt.output}
+		if ctx.OutputFormat() != tt.format {
+			t.Errorf("output %q: expected format %s, got %s", tt.output, tt.format, ctx.OutputFormat())
+		}
+		out := &bytes.Buffer{}
+		if err := ctx.Print(out, obj, tableFn); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(out.String(), tt.expected) {
+			t.Errorf("output %q: expected %q in %q", tt.output, tt.expected, out.String())
+		}
+	}
+}
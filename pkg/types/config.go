@@ -27,4 +27,6 @@ const (
 	CfgKeyClusterDefaultMemory      = "CLUSTER_DEFAULT_MEMORY"
 	CfgKeyHelmRepoURL               = "HELM_REPO_URL"
 	CfgKeyImageRegistry             = "IMAGE_REGISTRY"
+	// CfgKeyAddonTrustPolicy is the policy to verify the signatures of addons, one of none, warn and enforce
+	CfgKeyAddonTrustPolicy = "ADDON_TRUST_POLICY"
 )
@@ -42,6 +42,9 @@ const (
 	//	CliChartsCache defines kbcli charts cache dir name
 	CliChartsCache = "charts"
 
+	// CliClusterProfiles defines kbcli cluster profiles dir name
+	CliClusterProfiles = "profiles"
+
 	// CliLogDir defines kbcli log dir name
 	CliLogDir = "logs"
 
@@ -118,15 +121,19 @@ const (
 	ResourceConfigurationVersions    = "configurations"
 	KindCluster                      = "Cluster"
 	KindClusterDef                   = "ClusterDefinition"
+	KindComponentDef                 = "ComponentDefinition"
 	KindComponentVersion             = "ComponentVersion"
+	KindShardingDef                  = "ShardingDefinition"
 	KindConfigConstraint             = "ConfigConstraint"
 	KindConfiguration                = "Configuration"
 	KindBackup                       = "Backup"
 	KindRestore                      = "Restore"
 	KindBackupPolicy                 = "BackupPolicy"
 	KindOps                          = "OpsRequest"
+	KindOpsDef                       = "OpsDefinition"
 	KindBackupSchedule               = "BackupSchedule"
 	KindBackupPolicyTemplate         = "BackupPolicyTemplate"
+	KindActionSet                    = "ActionSet"
 	KindStatefulSet                  = "StatefulSet"
 	KindDeployment                   = "Deployment"
 	KindConfigMap                    = "ConfigMap"
@@ -153,6 +160,10 @@ const (
 	ReloadConfigMapAnnotationKey = "kubeblocks.io/reload-configmap" // mark an annotation to load configmap
 
 	KBVersionValidateAnnotationKey = "addon.kubeblocks.io/kubeblocks-version"
+	// AddonBundleDigestAnnotationKey records the chart digest of the bundle which the addon is installed from
+	AddonBundleDigestAnnotationKey = "addon.kubeblocks.io/bundle-digest"
+	// AddonChartDigestAnnotationKey pins the chart digest in a signed addon index entry
+	AddonChartDigestAnnotationKey = "addon.kubeblocks.io/chart-digest"
 )
 
 // Labels
@@ -163,6 +174,8 @@ const (
 	AddonVersionLabelKey = "addon.kubeblocks.io/version"
 	AddonNameLabelKey    = "addon.kubeblocks.io/name"
 	AddonModelLabelKey   = "addon.kubeblocks.io/model"
+	// FleetLabelKey marks the clusters created by the same fleet file
+	FleetLabelKey = "kbcli.kubeblocks.io/fleet"
 )
 
 // DataProtection API group
@@ -184,10 +197,12 @@ const (
 	ParametersAPIVersion = "v1alpha1"
 
 	KindParametersDef         = "ParametersDefinition"
-	KindParameterConfigRender = "ParameterConfigRender"
+	KindParameterConfigRender = "ParamConfigRenderer"
 
-	ResourceParameters          = "parameters"
-	ResourceComponentParameters = "componentparameters"
+	ResourceParameters            = "parameters"
+	ResourceComponentParameters   = "componentparameters"
+	ResourceParametersDefinitions = "parametersdefinitions"
+	ResourceParamConfigRenderers  = "paramconfigrenderers"
 )
 
 // Extensions API group
@@ -195,6 +210,7 @@ const (
 	ExtensionsAPIGroup   = "extensions.kubeblocks.io"
 	ExtensionsAPIVersion = "v1alpha1"
 	ResourceAddons       = "addons"
+	KindAddon            = "Addon"
 )
 
 // Storage API group
@@ -282,6 +298,9 @@ var (
 	// AddonIndexDir is the default addon index dir
 	AddonIndexDir = filepath.Join("addon", "index")
 
+	// AddonTrustDir is the dir of the trusted public keys to verify the addon index entries and charts
+	AddonTrustDir = filepath.Join("addon", "trust")
+
 	// ClusterChartsRepoName helm chart repo for installing cluster chart
 	ClusterChartsRepoName = "kubeblocks-addons"
 
@@ -516,3 +535,11 @@ func ParameterGVR() schema.GroupVersionResource {
 func ComponentParameterGVR() schema.GroupVersionResource {
 	return schema.GroupVersionResource{Group: ParametersAPIGroup, Version: ParametersAPIVersion, Resource: ResourceComponentParameters}
 }
+
+func ParametersDefinitionGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: ParametersAPIGroup, Version: ParametersAPIVersion, Resource: ResourceParametersDefinitions}
+}
+
+func ParamConfigRendererGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: ParametersAPIGroup, Version: ParametersAPIVersion, Resource: ResourceParamConfigRenderers}
+}
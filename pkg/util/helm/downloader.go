@@ -20,9 +20,12 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package helm
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"strings"
 
+	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/downloader"
 	"helm.sh/helm/v3/pkg/getter"
@@ -58,3 +61,57 @@ func NewDownloader(cfg *Config) (*downloader.ChartDownloader, error) {
 	}
 	return chartsDownloaders, nil
 }
+
+// DownloadOCIChart pulls the chart from the OCI registry into destDir and returns the path of the chart,
+// the latest version is pulled if the version is empty. The registry credentials are read from the helm
+// registry config, and fall back to the docker config.
+func DownloadOCIChart(ref, version, destDir string, plainHTTP bool) (string, error) {
+	settings := cli.New()
+	client, err := newRegistryClient(settings, plainHTTP)
+	if err != nil {
+		return "", err
+	}
+	chartsDownloader := &downloader.ChartDownloader{
+		Out:     io.Discard,
+		Verify:  downloader.VerifyNever,
+		Getters: getter.All(settings),
+		Options: []getter.Option{
+			getter.WithRegistryClient(client),
+			getter.WithPlainHTTP(plainHTTP),
+		},
+		RegistryClient: client,
+	}
+	chartPath, _, err := chartsDownloader.DownloadTo(ref, version, destDir)
+	return chartPath, err
+}
+
+// PushOCIChart pushes the chart archive to the OCI registry, such as oci://registry.example.com/charts,
+// and returns the reference of the chart without the version.
+func PushOCIChart(data []byte, registryURL string, plainHTTP bool) (string, error) {
+	c, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	client, err := newRegistryClient(cli.New(), plainHTTP)
+	if err != nil {
+		return "", err
+	}
+	ref := fmt.Sprintf("%s/%s", strings.TrimSuffix(registryURL, "/"), c.Name())
+	if _, err = client.Push(data, fmt.Sprintf("%s:%s", strings.TrimPrefix(ref, fmt.Sprintf("%s://", registry.OCIScheme)), c.Metadata.Version)); err != nil {
+		return "", err
+	}
+	return ref, nil
+}
+
+func newRegistryClient(settings *cli.EnvSettings, plainHTTP bool) (*registry.Client, error) {
+	opts := []registry.ClientOption{
+		registry.ClientOptDebug(settings.Debug),
+		registry.ClientOptEnableCache(true),
+		registry.ClientOptWriter(io.Discard),
+		registry.ClientOptCredentialsFile(settings.RegistryConfig),
+	}
+	if plainHTTP {
+		opts = append(opts, registry.ClientOptPlainHTTP())
+	}
+	return registry.NewClient(opts...)
+}
@@ -47,6 +47,7 @@ import (
 	kubefake "helm.sh/helm/v3/pkg/kube/fake"
 	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
 	"helm.sh/helm/v3/pkg/repo"
 	"helm.sh/helm/v3/pkg/storage"
 	"helm.sh/helm/v3/pkg/storage/driver"
@@ -59,7 +60,13 @@ import (
 	"github.com/apecloud/kbcli/pkg/types"
 )
 
-const defaultTimeout = time.Second * 600
+const (
+	defaultTimeout = time.Second * 600
+
+	// FakeKubeVersion is the kubernetes version used to render the charts without a cluster,
+	// it is high enough to pass the kubeVersion constraints of the charts.
+	FakeKubeVersion = "v99.99.0"
+)
 
 type InstallOpts struct {
 	Name            string
@@ -507,7 +514,7 @@ func fakeActionConfig() *action.Configuration {
 			RegistryClient: registryClient,
 			Log:            func(format string, v ...interface{}) {},
 		}
-		singletonFakeCfg.Capabilities.KubeVersion.Version = "v99.99.0"
+		singletonFakeCfg.Capabilities.KubeVersion.Version = FakeKubeVersion
 	}
 
 	return singletonFakeCfg
@@ -760,3 +767,35 @@ func GetHelmRelease(cfg *Config, releaseName string) (*release.Release, error) {
 	}
 	return rel, nil
 }
+
+// GetReleaseHistory gives an implementation of 'helm history', the releases are sorted by revision.
+func GetReleaseHistory(cfg *Config, releaseName string) ([]*release.Release, error) {
+	actionCfg, err := NewActionConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := action.NewHistory(actionCfg)
+	client.Max = 256
+	history, err := client.Run(releaseName)
+	if err != nil {
+		return nil, err
+	}
+	releaseutil.SortByRevision(history)
+	return history, nil
+}
+
+// Rollback gives an implementation of 'helm rollback', revision 0 means the previous revision.
+func Rollback(cfg *Config, releaseName string, revision int, wait bool, timeout time.Duration) error {
+	actionCfg, err := NewActionConfig(cfg)
+	if err != nil {
+		return err
+	}
+	client := action.NewRollback(actionCfg)
+	client.Version = revision
+	client.Wait = wait
+	client.Timeout = timeout
+	if client.Timeout == 0 {
+		client.Timeout = defaultTimeout
+	}
+	return client.Run(releaseName)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package lint
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apecloud/kbcli/pkg/printer"
+)
+
+const (
+	LevelError   = "Error"
+	LevelWarning = "Warning"
+)
+
+// Result is a problem found by a linter, Target is the object or the field where the problem is found.
+type Result struct {
+	Level   string
+	Target  string
+	Message string
+}
+
+// Results collects the problems found by a linter.
+type Results []Result
+
+func (r *Results) Errorf(target, format string, a ...interface{}) {
+	*r = append(*r, Result{Level: LevelError, Target: target, Message: fmt.Sprintf(format, a...)})
+}
+
+func (r *Results) Warnf(target, format string, a ...interface{}) {
+	*r = append(*r, Result{Level: LevelWarning, Target: target, Message: fmt.Sprintf(format, a...)})
+}
+
+// ErrorCount returns the number of the results at the error level.
+func (r Results) ErrorCount() int {
+	var count int
+	for _, res := range r {
+		if res.Level == LevelError {
+			count++
+		}
+	}
+	return count
+}
+
+// Print prints the results of the linted object in a table, the target column is named by targetHeader.
+func (r Results) Print(out io.Writer, kind, name, targetHeader string) {
+	if len(r) == 0 {
+		fmt.Fprintf(out, "%s %s: %s\n", kind, name, printer.BoldGreen("OK"))
+		return
+	}
+	fmt.Fprintf(out, "%s %s:\n", kind, name)
+	tbl := printer.NewTablePrinter(out)
+	tbl.SetHeader("\tLEVEL", targetHeader, "MESSAGE")
+	for _, res := range r {
+		level := printer.BoldYellow(res.Level)
+		if res.Level == LevelError {
+			level = printer.BoldRed(res.Level)
+		}
+		tbl.AddRow("\t"+level, res.Target, res.Message)
+	}
+	tbl.Print()
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,48 @@
+/*
+Copyright (C) 2022-2026 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package lint
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestResults(t *testing.T) {
+	out := &bytes.Buffer{}
+	var results Results
+	results.Print(out, "Chart", "mysql", "OBJECT")
+	if !strings.Contains(out.String(), "Chart mysql: ") {
+		t.Errorf("expected the OK line, got %q", out.String())
+	}
+
+	results.Errorf("ComponentDefinition/mysql", "config template %s is not found", "mysql-config")
+	results.Warnf("ParametersDefinition/mysql-pd", "it is not referenced")
+	if count := results.ErrorCount(); count != 1 {
+		t.Errorf("expected 1 error, got %d", count)
+	}
+	out.Reset()
+	results.Print(out, "Chart", "mysql", "OBJECT")
+	for _, s := range []string{"OBJECT", "ComponentDefinition/mysql", "config template mysql-config is not found", "it is not referenced"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("expected %q in the output, got %q", s, out.String())
+		}
+	}
+}